@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/arena"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/command"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	"go.uber.org/zap"
+)
+
+// arenaStatus shows the current champion, reign length and defenses plus the room's best defense record (`챔피언전`).
+func (b *bot) arenaStatus(c *command.Context) error {
+	logRoute(c, "arena_status", "pvp")
+	reign, rec, err := arenaMgr.Status(c.Ctx, c.Room)
+	if err != nil {
+		obslog.L().Warn("arena_status_error", zap.Error(err), zap.String("room_id", c.Room))
+		return command.Reject("arena.error.failed", map[string]string{"Error": err.Error()}, "")
+	}
+	var lines []string
+	if reign == nil {
+		if txt, e := b.catalog.Render("arena.vacant", map[string]string{"Prefix": b.cfg.BotPrefix}); e == nil {
+			lines = append(lines, txt)
+		}
+	} else if txt, e := b.catalog.Render("arena.status", map[string]string{
+		"ChampionName": reign.ChampionName, "Reign": durationLabel(time.Since(reign.Since)),
+		"Defenses": strconv.Itoa(reign.Defenses), "Challenger": reign.ChallengerName,
+	}); e == nil {
+		lines = append(lines, txt)
+	}
+	if rec != nil {
+		if txt, e := b.catalog.Render("arena.record", map[string]string{"ChampionName": rec.ChampionName, "Defenses": strconv.Itoa(rec.Defenses)}); e == nil {
+			lines = append(lines, txt)
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "챔피언전 현황 조회 실패")
+	}
+	return c.Reply(strings.Join(lines, "\n"))
+}
+
+// arenaChallenge handles `챔피언전 도전`: 빈 자리면 바로 챔피언이 되고, 아니면 타이틀전을 시작합니다.
+func (b *bot) arenaChallenge(c *command.Context) error {
+	logRoute(c, "arena_challenge", "pvp")
+	res, err := arenaMgr.Challenge(c.Ctx, c.Room, c.UserID, c.Sender)
+	if err != nil {
+		fallback := "챔피언전 처리 실패: " + err.Error()
+		switch {
+		case errors.Is(err, arena.ErrAlreadyChampion):
+			return command.Reject("arena.error.already_champion", nil, fallback)
+		case errors.Is(err, arena.ErrTitleGamePending):
+			return command.Reject("arena.error.pending", nil, fallback)
+		case errors.Is(err, arena.ErrChampionBusy):
+			champ := ""
+			if reign, _, _ := arenaMgr.Status(c.Ctx, c.Room); reign != nil {
+				champ = reign.ChampionName
+			}
+			return command.Reject("arena.error.champion_busy", map[string]string{"ChampionName": champ}, fallback)
+		case errors.Is(err, arena.ErrPlayerBusy):
+			return command.Reject("pvp.busy.in_room", nil, fallback)
+		case errors.Is(err, pvpchess.ErrTooManyGames):
+			return command.Reject("pvp.capacity", nil, fallback)
+		case errors.Is(err, pvpchess.ErrTooManyUserGames):
+			return command.RejectText(gameLimitText(b.cfg, b.catalog))
+		}
+		obslog.L().Warn("arena_challenge_error", zap.Error(err), zap.String("user_id", c.UserID), zap.String("room_id", c.Room))
+		return command.Reject("arena.error.failed", map[string]string{"Error": err.Error()}, fallback)
+	}
+	if res.Claimed {
+		return c.ReplyKey("arena.claimed", map[string]string{"ChampionName": res.Reign.ChampionName, "Prefix": b.cfg.BotPrefix}, "👑 "+res.Reign.ChampionName+" 님이 챔피언 자리를 차지했습니다.")
+	}
+	head, e := b.catalog.Render("arena.title_game", map[string]string{"ChampionName": res.Reign.ChampionName, "ChallengerName": res.Reign.ChallengerName, "Defenses": strconv.Itoa(res.Reign.Defenses)})
+	if e != nil {
+		head = "⚔️ 타이틀전 — " + res.Reign.ChampionName + " vs " + res.Reign.ChallengerName
+	}
+	b.announceStart(c, res.Game, head, "arena")
+	return nil
+}
+
+// durationLabel renders an elapsed time (챔피언 재위 기간, 통신 대국 마지막 수 이후 등).
+func durationLabel(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "1분 미만"
+	case d < time.Hour:
+		return strconv.Itoa(int(d/time.Minute)) + "분"
+	case d < 24*time.Hour:
+		return strconv.Itoa(int(d/time.Hour)) + "시간"
+	default:
+		days := int(d / (24 * time.Hour))
+		hours := int((d % (24 * time.Hour)) / time.Hour)
+		if hours == 0 {
+			return strconv.Itoa(days) + "일"
+		}
+		return strconv.Itoa(days) + "일 " + strconv.Itoa(hours) + "시간"
+	}
+}
+
+// announceArenaEvent posts the outcome of a title game to the arena room.
+func announceArenaEvent(catalog *msgcat.Catalog, ev *arena.Event) {
+	if ev == nil || ev.Reign == nil {
+		return
+	}
+	r := ev.Reign
+	var text string
+	var e error
+	switch ev.Kind {
+	case arena.EventNewChampion:
+		text, e = catalog.Render("arena.new_champion", map[string]string{
+			"ChampionName": r.ChampionName, "PreviousName": ev.Previous.ChampionName,
+			"Defenses": strconv.Itoa(ev.Previous.Defenses), "Reign": durationLabel(time.Since(ev.Previous.Since)),
+		})
+	case arena.EventDefended:
+		draw := ""
+		if ev.Draw {
+			draw = "1"
+		}
+		text, e = catalog.Render("arena.defended", map[string]string{
+			"ChampionName": r.ChampionName, "Draw": draw, "Defenses": strconv.Itoa(r.Defenses), "Reign": durationLabel(time.Since(r.Since)),
+		})
+	default:
+		text, e = catalog.Render("arena.voided", map[string]string{"ChampionName": r.ChampionName})
+	}
+	if e != nil {
+		text = "👑 챔피언: " + r.ChampionName
+	}
+	_ = defaultEgress.SendText(context.Background(), r.Room, text)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	appcfg "github.com/park285/Cheese-KakaoTalk-bot/internal/config"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irisfast"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"go.uber.org/zap"
+)
+
+// egressQueueOptions builds the outbound queue settings. EGRESS_RATE_MS가 음수면 Iris /config의
+// messageRate(ms)를 전체 전송 간격으로 사용하고, 조회 실패 시 간격 없이 시작합니다.
+func egressQueueOptions(cfg *appcfg.AppConfig, client *irisfast.Client, logger *zap.Logger) irisfast.QueueOptions {
+	rate := cfg.EgressRateMS
+	if rate < 0 {
+		rate = 0
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		irisCfg, err := client.GetConfig(ctx)
+		cancel()
+		if err != nil {
+			logger.Warn("egress_rate_config_error", zap.Error(err))
+		} else if irisCfg.MessageRate > 0 {
+			rate = irisCfg.MessageRate
+		}
+	}
+	logger.Info("egress_queue", zap.Int("rate_ms", rate), zap.Int("room_interval_ms", cfg.EgressRoomIntervalMS), zap.Int("attempts", cfg.EgressRetryMax), zap.Int("max_depth", cfg.EgressQueueMax))
+	return irisfast.QueueOptions{
+		RoomInterval:   time.Duration(cfg.EgressRoomIntervalMS) * time.Millisecond,
+		GlobalInterval: time.Duration(rate) * time.Millisecond,
+		MaxAttempts:    cfg.EgressRetryMax,
+		MaxDepth:       cfg.EgressQueueMax,
+		Logger:         logger,
+	}
+}
+
+// logEgressQueue periodically logs queue depth and delivery counters while anything changed.
+func logEgressQueue(q *irisfast.Queue, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	var prev irisfast.QueueStats
+	for range ticker.C {
+		st := q.Stats()
+		if st.Depth == 0 && st.Sent == prev.Sent && st.Failed == prev.Failed && st.Dropped == prev.Dropped {
+			continue
+		}
+		busy := st.BusyRooms()
+		if len(busy) > 5 {
+			busy = busy[:5]
+		}
+		obslog.L().Info("egress_queue_stats",
+			zap.Int("depth", st.Depth), zap.Strings("busy_rooms", busy),
+			zap.Uint64("sent", st.Sent), zap.Uint64("retried", st.Retried),
+			zap.Uint64("failed", st.Failed), zap.Uint64("dropped", st.Dropped))
+		prev = st
+	}
+}
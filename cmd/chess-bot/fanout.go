@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appcfg "github.com/park285/Cheese-KakaoTalk-bot/internal/config"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchan"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	"github.com/park285/Cheese-KakaoTalk-bot/pkg/chessdto"
+	"go.uber.org/zap"
+)
+
+// sendPvPBoards sends the board with text to every room from the room viewer's side (see pvpRoomViewer).
+// 이미지 전송 실패 시의 축소/텍스트 보드 폴백은 Presenter.DeliverBoard가 처리하며, 그것도 실패하면
+// 문구 없는 보드에 한해 안내문만 보냅니다(이미 보낸 문구는 다시 보내지 않음).
+func sendPvPBoards(ctx context.Context, pvpChessMgr *pvpchess.Manager, pvpChanMgr *pvpchan.Manager, catalog *msgcat.Catalog, g *pvpchess.Game, rooms []string, text, phase string) {
+	sendPvPRoomBoards(ctx, pvpChessMgr, pvpChanMgr, catalog, g, rooms, func(string) string { return text }, phase)
+}
+
+// sendPvPRoomBoards is sendPvPBoards with a per-room text (예: 통신 대국의 차례 알림).
+func sendPvPRoomBoards(ctx context.Context, pvpChessMgr *pvpchess.Manager, pvpChanMgr *pvpchan.Manager, catalog *msgcat.Catalog, g *pvpchess.Game, rooms []string, roomText func(room string) string, phase string) {
+	wDTO, wErr := pvpBoardView(ctx, pvpChessMgr, g, g.WhiteID, pvpchess.White)
+	bDTO, bErr := pvpBoardView(ctx, pvpChessMgr, g, g.BlackID, pvpchess.Black)
+	if wErr != nil || bErr != nil {
+		obslog.L().Warn("pvp_render_error", zap.Error(errors.Join(wErr, bErr)), zap.String("game_id", g.ID), zap.String("phase", phase))
+		for _, r := range rooms {
+			fallback := strings.TrimSpace(roomText(r))
+			if fallback == "" {
+				if t, e := catalog.Render("render.board.failed", nil); e == nil {
+					fallback = t
+				} else {
+					fallback = "보드 렌더링 실패"
+				}
+			}
+			sendPvPText(ctx, []string{r}, fallback, g.ID, phase)
+		}
+		return
+	}
+	meta, _, _ := pvpChanMgr.MetaByGame(ctx, g)
+	for _, r := range rooms {
+		viewer := pvpRoomViewer(meta, g, r)
+		vdto := wDTO
+		if strings.TrimSpace(viewer) == strings.TrimSpace(g.BlackID) {
+			vdto = bDTO
+		}
+		text := roomText(r)
+		d := pvpPresenter.DeliverBoard(r, text, vdto)
+		if d.Fallback() {
+			obslog.L().Warn("pvp_board_fallback", zap.String("via", string(d.Via)), zap.Int("sends", len(d.Sends)), zap.String("room_id", r), zap.String("game_id", g.ID), zap.String("phase", phase))
+		}
+		if d.Err != nil {
+			obslog.L().Warn("pvp_board_send_error", zap.Error(d.Err), zap.String("room_id", r), zap.String("game_id", g.ID), zap.String("phase", phase))
+			if strings.TrimSpace(text) == "" {
+				notice, e := catalog.Render("board.send.failed", nil)
+				if e != nil {
+					notice = "보드 전송 실패"
+				}
+				sendPvPText(ctx, []string{r}, notice, g.ID, phase)
+			}
+		}
+	}
+}
+
+// pvpBoardView returns the board seen by viewerID. 국면이 그대로면(예: `보드` 재요청) 마지막으로 전달된
+// 이미지를 재사용하고, 아니면 새로 렌더링합니다.
+func pvpBoardView(ctx context.Context, pvpChessMgr *pvpchess.Manager, g *pvpchess.Game, viewerID string, color pvpchess.Color) (*chessdto.SessionState, error) {
+	if img, ok := pvpPresenter.LastBoard(g.ID, string(color), g.MovesUCI); ok {
+		obslog.L().Debug("pvp_board_reuse", zap.String("game_id", g.ID), zap.String("color", string(color)))
+		return &chessdto.SessionState{
+			SessionUUID: g.ID,
+			MovesUCI:    append([]string(nil), g.MovesUCI...),
+			MovesSAN:    append([]string(nil), g.MovesSAN...),
+			FEN:         g.FEN,
+			BoardImage:  img,
+			MoveCount:   len(g.MovesUCI),
+			PlayerColor: string(color),
+		}, nil
+	}
+	return pvpChessMgr.ToDTOForViewer(ctx, g, viewerID)
+}
+
+// sendPvPText sends the same text to every room (간격은 송신 큐가 적용).
+func sendPvPText(ctx context.Context, rooms []string, text, gameID, phase string) {
+	for _, r := range rooms {
+		if err := pvpEgress.SendText(ctx, r, text); err != nil {
+			obslog.L().Warn("pvp_text_send_error", zap.Error(err), zap.String("room_id", r), zap.String("game_id", gameID), zap.String("phase", phase))
+		}
+	}
+}
+
+// fanoutRooms: 채널 저장소/사용자 인덱스/현재 방을 합쳐 안정적 팬아웃 대상 생성
+func fanoutRooms(ctx context.Context, pvpChanMgr *pvpchan.Manager, g *pvpchess.Game, extras ...string) []string {
+	base := extras
+	if pvpChanMgr != nil && g != nil {
+		if meta, code, _ := pvpChanMgr.MetaByGame(ctx, g); meta != nil || code != "" {
+			if rs, _ := pvpChanMgr.Rooms(ctx, code); len(rs) > 0 {
+				base = append(base, rs...)
+			}
+			// 관전 방도 동일한 보드/안내를 받음
+			if rs, _ := pvpChanMgr.SpectatorRooms(ctx, code); len(rs) > 0 {
+				base = append(base, rs...)
+			}
+		}
+		if rs, _ := pvpChanMgr.RoomsByUserAndGame(ctx, g.WhiteID, g.ID); len(rs) > 0 {
+			base = append(base, rs...)
+		}
+		if rs, _ := pvpChanMgr.RoomsByUserAndGame(ctx, g.BlackID, g.ID); len(rs) > 0 {
+			base = append(base, rs...)
+		}
+	}
+	return mergeFanoutRooms(g, base)
+}
+
+// mergeFanoutRooms는 전달받은 방 목록에 게임의 원/해결 방을 합쳐 중복 제거.
+// 이유: 참가자 인덱스가 늦게 동기화되면 한쪽 방만 반환될 수 있음.
+func mergeFanoutRooms(g *pvpchess.Game, rooms []string) []string {
+	set := make(map[string]struct{})
+	add := func(s string) {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return
+		}
+		set[s] = struct{}{}
+	}
+	for _, r := range rooms {
+		add(r)
+	}
+	if g != nil {
+		add(g.OriginRoom)
+		add(g.ResolveRoom)
+	}
+	out := make([]string, 0, len(set))
+	for r := range set {
+		out = append(out, r)
+	}
+	return out
+}
+
+// pvpRoomViewer: 방별 보드 관점 — 생성자 방은 생성자, 상대 참가자 방은 상대, 관전 방은 "" (백 관점으로 표시)
+func pvpRoomViewer(meta *pvpchan.ChannelMeta, g *pvpchess.Game, room string) string {
+	r := strings.TrimSpace(room)
+	if meta == nil {
+		// 채널이 만료된 통신 대국: 플레이어별 참가 방으로 판단
+		if r != "" && r == strings.TrimSpace(g.BlackRoom) && r != strings.TrimSpace(g.WhiteRoom) {
+			return strings.TrimSpace(g.BlackID)
+		}
+		return strings.TrimSpace(g.WhiteID)
+	}
+	if r == strings.TrimSpace(meta.CreatorRoom) {
+		return strings.TrimSpace(meta.CreatorID)
+	}
+	if r != strings.TrimSpace(g.OriginRoom) && r != strings.TrimSpace(g.ResolveRoom) {
+		return ""
+	}
+	if strings.TrimSpace(meta.CreatorID) == strings.TrimSpace(g.WhiteID) {
+		return strings.TrimSpace(g.BlackID)
+	}
+	return strings.TrimSpace(g.WhiteID)
+}
+
+// prioritizeRooms: 현재 방을 선두로 이동(없으면 추가) — 순회 중 드롭 완화 우선순위 확보
+func prioritizeRooms(rooms []string, current string) []string {
+	cur := strings.TrimSpace(current)
+	if cur == "" {
+		return rooms
+	}
+	seen := make(map[string]struct{})
+	out := make([]string, 0, len(rooms)+1)
+	// 현재 방 먼저
+	out = append(out, cur)
+	seen[cur] = struct{}{}
+	// 나머지 보존 + 중복 제거
+	for _, r := range rooms {
+		rr := strings.TrimSpace(r)
+		if rr == "" {
+			continue
+		}
+		if _, ok := seen[rr]; ok {
+			continue
+		}
+		out = append(out, rr)
+		seen[rr] = struct{}{}
+	}
+	return out
+}
+
+// pvpPlayerName returns the display name of userID within the game, or empty when not a participant.
+func pvpPlayerName(g *pvpchess.Game, userID string) string {
+	if g == nil {
+		return ""
+	}
+	switch strings.TrimSpace(userID) {
+	case strings.TrimSpace(g.WhiteID):
+		return strings.TrimSpace(g.WhiteName)
+	case strings.TrimSpace(g.BlackID):
+		return strings.TrimSpace(g.BlackName)
+	}
+	return ""
+}
+
+// withPvPRatingLine appends the rating change line to a finish text when the game was rated.
+func withPvPRatingLine(catalog *msgcat.Catalog, g *pvpchess.Game, text string) string {
+	if g == nil || (g.WhiteRating == 0 && g.BlackRating == 0) {
+		return text
+	}
+	line, err := catalog.Render("pvp.rating.line", map[string]string{
+		"WhiteName":   strings.TrimSpace(g.WhiteName),
+		"WhiteRating": strconv.Itoa(g.WhiteRating),
+		"WhiteDelta":  formatRatingDelta(g.WhiteRatingDelta),
+		"BlackName":   strings.TrimSpace(g.BlackName),
+		"BlackRating": strconv.Itoa(g.BlackRating),
+		"BlackDelta":  formatRatingDelta(g.BlackRatingDelta),
+	})
+	if err != nil || strings.TrimSpace(line) == "" {
+		return text
+	}
+	if strings.TrimSpace(text) == "" {
+		return line
+	}
+	return text + "\n" + line
+}
+
+func formatRatingDelta(d int) string {
+	if d > 0 {
+		return fmt.Sprintf("▲%d", d)
+	}
+	if d < 0 {
+		return fmt.Sprintf("▼%d", -d)
+	}
+	return "±0"
+}
+
+// announcePvPAbandon notifies every fanout room that an idle game was forfeited or aborted by the sweeper.
+func announcePvPAbandon(pvpChanMgr *pvpchan.Manager, catalog *msgcat.Catalog, g *pvpchess.Game) {
+	if g == nil {
+		return
+	}
+	ctx := context.Background()
+	var text string
+	if g.Status == pvpchess.StatusAborted {
+		if t, e := catalog.Render("pvp.abandon.aborted", nil); e == nil {
+			text = t
+		} else {
+			text = "⏰ 장기간 진행이 없어 대국이 취소되었습니다."
+		}
+	} else {
+		winnerName := pvpPlayerName(g, g.Winner)
+		idleName := g.WhiteName
+		if strings.TrimSpace(g.Winner) == strings.TrimSpace(g.WhiteID) {
+			idleName = g.BlackName
+		}
+		if t, e := catalog.Render("pvp.abandon.forfeit", map[string]string{"IdleName": strings.TrimSpace(idleName), "WinnerName": winnerName}); e == nil {
+			text = t
+		} else {
+			text = "⏰ 장기간 응답이 없어 " + winnerName + " 님의 승리로 종료되었습니다."
+		}
+		text = withPvPRatingLine(catalog, g, text)
+	}
+	rooms := fanoutRooms(ctx, pvpChanMgr, g)
+	obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", "abandon"))
+	sendPvPText(ctx, rooms, text, g.ID, "abandon")
+}
+
+// correspondenceTurnNotice returns the room of the player to move and the "your move" text for an
+// ACTIVE correspondence game; ("", "") otherwise.
+func correspondenceTurnNotice(catalog *msgcat.Catalog, cfg *appcfg.AppConfig, g *pvpchess.Game) (string, string) {
+	if g == nil || !g.Correspondence || g.Status != pvpchess.StatusActive {
+		return "", ""
+	}
+	toMove, mover := g.WhiteID, g.BlackID
+	if g.Turn == pvpchess.Black {
+		toMove, mover = g.BlackID, g.WhiteID
+	}
+	room := strings.TrimSpace(g.RoomOf(toMove))
+	if room == "" {
+		return "", ""
+	}
+	last := ""
+	if n := len(g.MovesSAN); n > 0 {
+		last = g.MovesSAN[n-1]
+	}
+	text, e := catalog.Render("pvp.corr.your_move", map[string]string{
+		"PlayerName": pvpPlayerName(g, toMove), "OpponentName": pvpPlayerName(g, mover),
+		"Move": last, "Ply": strconv.Itoa(len(g.MovesUCI)), "Handle": strconv.Itoa(g.Handle), "Prefix": cfg.BotPrefix,
+	})
+	if e != nil {
+		text = "📮 " + pvpPlayerName(g, toMove) + " 님 차례입니다."
+	}
+	return room, text
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/command"
+	appcfg "github.com/park285/Cheese-KakaoTalk-bot/internal/config"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchan"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/util"
+	"go.uber.org/zap"
+)
+
+// lobbyMake creates a waiting lobby: `방 생성 [백|흑|랜덤] [비공개] [통신] [비번 <PIN>]`.
+func (b *bot) lobbyMake(c *command.Context) error {
+	mr, err := b.chans.MakeLobby(c.Ctx, c.Room, c.UserID, c.Sender, lobbyOptionsArg(c.Args))
+	if err != nil {
+		// 동일 사용자 다중 방 생성 제한 안내
+		if strings.Contains(strings.ToLower(err.Error()), "already has a lobby") {
+			return command.Reject("lobby.make.limit", map[string]string{"Prefix": b.cfg.BotPrefix}, "이미 생성한 대기 방이 있어 새로 만들 수 없습니다.")
+		}
+		if errors.Is(err, pvpchan.ErrInvalidPIN) {
+			return command.Reject("lobby.pin.invalid", nil, "채널 생성 실패: "+err.Error())
+		}
+		return command.Reject("channel.create.error", map[string]string{"Error": err.Error()}, "채널 생성 실패: "+err.Error())
+	}
+	return c.ReplyKey("lobby_make.success", lobbyMakeData(b.cfg, mr), "")
+}
+
+// join joins a lobby by code (`참가 <코드> [백|흑|랜덤] [비번 <PIN>]`); 대국이 시작되면 양쪽 방에 시작 안내와 보드를 보냅니다.
+func (b *bot) join(c *command.Context) error {
+	code := c.Arg(0)
+	rest := c.Args[1:]
+	jr, err := b.chans.JoinWithPIN(c.Ctx, c.Room, code, c.UserID, c.Sender, colorChoiceArg(rest), lobbyPINArg(rest))
+	if err != nil {
+		if key := joinPINErrorKey(err); key != "" {
+			return command.Reject(key, map[string]string{"Prefix": b.cfg.BotPrefix, "Code": code}, "")
+		}
+		if errors.Is(err, pvpchess.ErrTooManyGames) {
+			return command.Reject("pvp.capacity", nil, "")
+		}
+		if errors.Is(err, pvpchan.ErrPlayerBusy) {
+			return command.RejectText(gameLimitText(b.cfg, b.catalog))
+		}
+		return command.Reject("join.error", map[string]string{"Error": err.Error()}, "")
+	}
+	if !jr.Started {
+		return c.ReplyKey("join.waiting", nil, "")
+	}
+	g, _ := b.pvp.LoadGame(c.Ctx, jr.GameID)
+	if g == nil {
+		return command.Reject("game.not_found", nil, "")
+	}
+	wDTO, wErr := b.pvp.ToDTOForViewer(c.Ctx, g, g.WhiteID)
+	bDTO, bErr := b.pvp.ToDTOForViewer(c.Ctx, g, g.BlackID)
+	if wErr != nil || bErr != nil {
+		return command.Reject("render.error", nil, "")
+	}
+	// 현재 방 우선 순위 적용
+	rooms := prioritizeRooms(fanoutRooms(c.Ctx, b.chans, g, c.Room), c.Room)
+	obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", "start"))
+	text, _ := b.catalog.Render("pvp.start.announce", map[string]string{"WhiteName": g.WhiteName, "BlackName": g.BlackName})
+	for _, r := range rooms {
+		// 생성자 방은 생성자 관점, 그 밖의 방은 상대 관점
+		viewer := strings.TrimSpace(jr.Meta.CreatorID)
+		if strings.TrimSpace(r) != strings.TrimSpace(jr.Meta.CreatorRoom) {
+			if viewer == strings.TrimSpace(g.WhiteID) {
+				viewer = g.BlackID
+			} else {
+				viewer = g.WhiteID
+			}
+		}
+		vdto := wDTO
+		if strings.TrimSpace(viewer) == strings.TrimSpace(g.BlackID) {
+			vdto = bDTO
+		}
+		// 1) 텍스트 먼저 전송 (개별 에러 로그)
+		if strings.TrimSpace(text) != "" {
+			if err := pvpEgress.SendText(c.Ctx, r, text); err != nil {
+				obslog.L().Warn("pvp_start_text_error", zap.Error(err), zap.String("room_id", r), zap.String("game_id", g.ID))
+			}
+		}
+		// 2) 이미지만 전송 — 같은 방 간격(EGRESS_ROOM_INTERVAL_MS)은 송신 큐가 보장
+		if err := pvpPresenter.Board(r, "", vdto); err != nil {
+			obslog.L().Warn("pvp_board_send_error", zap.Error(err), zap.String("room_id", r), zap.String("game_id", g.ID), zap.String("phase", "start"))
+			// 보드 전송 실패 시 보조 안내문 전송
+			if t, e := b.catalog.Render("board.send.failed", nil); e == nil {
+				_ = pvpEgress.SendText(c.Ctx, r, t)
+			} else {
+				_ = pvpEgress.SendText(c.Ctx, r, "보드 전송 실패")
+			}
+		}
+	}
+	return nil
+}
+
+// lobbyList renders `방 리스트 [이방|허용|전체] [쪽]` for the requesting room.
+// 범위는 PVP_LOBBY_SCOPE를 넘을 수 없으며, 항목마다 대기 시간과 생성자 PvP 전적을 표시합니다.
+func (b *bot) lobbyList(c *command.Context) error {
+	maxScope, _ := pvpchan.ParseLobbyScope(b.cfg.PvpLobbyScope)
+	scope, page := maxScope, 1
+	for _, a := range c.Args {
+		if sc, ok := pvpchan.ParseLobbyScope(a); ok {
+			scope = sc
+		} else if n, err := strconv.Atoi(strings.TrimSpace(a)); err == nil && n > 0 {
+			page = n
+		}
+	}
+	scope = pvpchan.ClampLobbyScope(scope, maxScope)
+	metas, err := b.chans.ListLobbyScoped(c.Ctx, scope, c.Room, b.cfg.AllowedRooms)
+	obslog.L().Info("route_decision", zap.String("cmd", "lobby_list"), zap.String("mode", "pvp"), zap.String("room_id", c.Room), zap.String("scope", string(scope)), zap.Int("count", len(metas)))
+	if err != nil {
+		return command.Reject("lobby.list.error", nil, "")
+	}
+	if len(metas) == 0 {
+		return command.Reject("lobby.none", nil, "")
+	}
+	pages := (len(metas) + lobbyPageSize - 1) / lobbyPageSize
+	if page > pages {
+		page = pages
+	}
+	from := (page - 1) * lobbyPageSize
+	to := min(from+lobbyPageSize, len(metas))
+
+	header, e := b.catalog.Render("lobby.list.header", map[string]string{"ScopeLabel": lobbyScopeLabel(scope), "Page": strconv.Itoa(page), "Pages": strconv.Itoa(pages), "Total": strconv.Itoa(len(metas))})
+	if e != nil {
+		header = "대기방:"
+	}
+	now := time.Now()
+	var sb strings.Builder
+	for _, m := range metas[from:to] {
+		data := map[string]string{
+			"Code":           m.ID,
+			"CreatorName":    m.CreatorName,
+			"ColorLabel":     lobbyColorLabel(m.CreatorColor),
+			"Age":            lobbyAgeLabel(now.Sub(m.CreatedAt)),
+			"Record":         lobbyCreatorRecord(c.Ctx, b.pvp, b.catalog, m.CreatorID),
+			"PIN":            "",
+			"Correspondence": "",
+		}
+		if m.PINHash != "" {
+			data["PIN"] = "1"
+		}
+		if m.Correspondence {
+			data["Correspondence"] = "1"
+		}
+		if item, e := b.catalog.Render("lobby.list.item", data); e == nil {
+			sb.WriteString(item)
+		} else {
+			fmt.Fprintf(&sb, "• 코드: %s | 만든이: %s", m.ID, m.CreatorName)
+		}
+		sb.WriteString("\n")
+	}
+	if page < pages {
+		if more, e := b.catalog.Render("lobby.list.more", map[string]string{"Prefix": b.cfg.BotPrefix, "ScopeArg": lobbyScopeArg(scope, maxScope), "Next": strconv.Itoa(page + 1)}); e == nil {
+			sb.WriteString(more)
+			sb.WriteString("\n")
+		}
+	}
+	body := strings.TrimRight(sb.String(), "\n")
+	text := header + "\n" + body
+	if to-from > 5 {
+		// 항목이 많으면 헤더만 보이고 나머지는 '전체보기'로 접히도록 패딩
+		text = util.ApplyKakaoSeeMorePadding(body, header)
+	}
+	return c.Reply(text)
+}
+
+// lobbyCancel removes the sender's own waiting lobby.
+func (b *bot) lobbyCancel(c *command.Context) error {
+	meta, err := b.chans.CancelLobby(c.Ctx, c.UserID)
+	logRoute(c, "lobby_cancel", "pvp")
+	const fallback = "대기방 취소 처리 실패"
+	switch {
+	case err == nil:
+		return c.ReplyKey("lobby.cancel.done", map[string]string{"Code": meta.ID}, fallback)
+	case errors.Is(err, pvpchan.ErrNoLobby):
+		return command.Reject("lobby.cancel.none", nil, fallback)
+	}
+	obslog.L().Warn("lobby_cancel_error", zap.Error(err), zap.String("user_id", c.UserID))
+	return command.Reject("lobby.cancel.failed", map[string]string{"Error": err.Error()}, fallback)
+}
+
+// lobbyPageSize is the number of lobbies shown per `방 리스트` page.
+const lobbyPageSize = 10
+
+// lobbyOptionsArg parses `[백|흑|랜덤] [비공개] [비번 <PIN>]` for lobby creation.
+func lobbyOptionsArg(args []string) pvpchan.LobbyOptions {
+	opts := pvpchan.LobbyOptions{Color: colorChoiceArg(args), PIN: lobbyPINArg(args)}
+	for _, a := range args {
+		switch strings.ToLower(strings.TrimSpace(a)) {
+		case "비공개", "private", "unlisted":
+			opts.Unlisted = true
+		case "통신", "통신대국", "correspondence":
+			opts.Correspondence = true
+		}
+	}
+	return opts
+}
+
+// lobbyPINArg returns the token following `비번`/`pin`, or "".
+func lobbyPINArg(args []string) string {
+	for i := 0; i+1 < len(args); i++ {
+		switch strings.ToLower(strings.TrimSpace(args[i])) {
+		case "비번", "비밀번호", "pin":
+			return strings.TrimSpace(args[i+1])
+		}
+	}
+	return ""
+}
+
+// colorChoiceArg returns the first 백/흑/랜덤 token among args, defaulting to random.
+func colorChoiceArg(args []string) pvpchan.ColorChoice {
+	for _, a := range args {
+		if c, ok := pvpchan.ParseColorChoice(a); ok {
+			return c
+		}
+	}
+	return pvpchan.ColorRandom
+}
+
+// lobbyMakeData fills the lobby_make.success template fields.
+func lobbyMakeData(cfg *appcfg.AppConfig, mr *pvpchan.MakeResult) map[string]string {
+	data := map[string]string{"Code": mr.Code, "Prefix": cfg.BotPrefix, "Unlisted": "", "HasPIN": "", "Correspondence": ""}
+	if mr.Meta != nil && mr.Meta.Unlisted {
+		data["Unlisted"] = "1"
+	}
+	if mr.Meta != nil && mr.Meta.Correspondence {
+		data["Correspondence"] = "1"
+	}
+	if mr.Meta != nil && mr.Meta.PINHash != "" {
+		data["HasPIN"] = "1"
+	}
+	return data
+}
+
+// joinPINErrorKey maps lobby PIN errors to catalog keys ("" for other errors).
+func joinPINErrorKey(err error) string {
+	switch {
+	case errors.Is(err, pvpchan.ErrPINRequired):
+		return "join.pin.required"
+	case errors.Is(err, pvpchan.ErrWrongPIN):
+		return "join.pin.wrong"
+	case errors.Is(err, pvpchan.ErrPINLocked):
+		return "join.pin.locked"
+	}
+	return ""
+}
+
+// lobbyColorLabel returns the Korean side name for a lobby color preference, or "" for random.
+func lobbyColorLabel(c pvpchan.ColorChoice) string {
+	switch c {
+	case pvpchan.ColorWhite:
+		return "백"
+	case pvpchan.ColorBlack:
+		return "흑"
+	}
+	return ""
+}
+
+// lobbyScopeLabel returns the Korean label for a lobby list scope.
+func lobbyScopeLabel(scope pvpchan.LobbyScope) string {
+	switch scope {
+	case pvpchan.ScopeGlobal:
+		return "전체"
+	case pvpchan.ScopeAllowed:
+		return "허용 방"
+	default:
+		return "이 방"
+	}
+}
+
+// lobbyScopeArg returns the scope token to repeat in the next-page hint ("" when it is the default).
+func lobbyScopeArg(scope, def pvpchan.LobbyScope) string {
+	if scope == def {
+		return ""
+	}
+	switch scope {
+	case pvpchan.ScopeGlobal:
+		return "전체 "
+	case pvpchan.ScopeAllowed:
+		return "허용 "
+	default:
+		return "이방 "
+	}
+}
+
+// lobbyAgeLabel renders how long a lobby has been waiting (방금 / N분 / N시간).
+func lobbyAgeLabel(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "방금"
+	case d < time.Hour:
+		return strconv.Itoa(int(d/time.Minute)) + "분 대기"
+	default:
+		return strconv.Itoa(int(d/time.Hour)) + "시간 대기"
+	}
+}
+
+// lobbyCreatorRecord renders the creator's PvP rating and W/L/D record, or "" when unavailable.
+func lobbyCreatorRecord(ctx context.Context, pvpChessMgr *pvpchess.Manager, catalog *msgcat.Catalog, userID string) string {
+	if pvpChessMgr == nil || strings.TrimSpace(userID) == "" {
+		return ""
+	}
+	r, err := pvpChessMgr.Rating(ctx, userID)
+	if err != nil {
+		return ""
+	}
+	if r.Games == 0 {
+		if t, e := catalog.Render("lobby.list.record_new", nil); e == nil {
+			return t
+		}
+		return "신규"
+	}
+	t, e := catalog.Render("lobby.list.record", map[string]string{"Rating": strconv.Itoa(r.Rating), "Wins": strconv.Itoa(r.Wins), "Losses": strconv.Itoa(r.Losses), "Draws": strconv.Itoa(r.Draws)})
+	if e != nil {
+		return strconv.Itoa(r.Rating)
+	}
+	return t
+}
+
+// announceLobbyExpired tells the creator's room that their lobby expired without an opponent.
+func announceLobbyExpired(cfg *appcfg.AppConfig, catalog *msgcat.Catalog, meta *pvpchan.ChannelMeta) {
+	if meta == nil || strings.TrimSpace(meta.CreatorRoom) == "" {
+		return
+	}
+	text, e := catalog.Render("lobby.expired", map[string]string{"CreatorName": meta.CreatorName, "Code": meta.ID, "Minutes": strconv.Itoa(cfg.PvpLobbyTTLMin)})
+	if e != nil {
+		text = "⏰ 대기방 " + meta.ID + "이(가) 상대가 없어 만료되었습니다."
+	}
+	_ = defaultEgress.SendText(context.Background(), meta.CreatorRoom, text)
+}
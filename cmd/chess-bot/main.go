@@ -13,16 +13,16 @@ import (
 	"time"
 
 	"github.com/park285/Cheese-KakaoTalk-bot/internal/adapter/chesspresenter"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/arena"
 	"github.com/park285/Cheese-KakaoTalk-bot/internal/chessbuilder"
 	appcfg "github.com/park285/Cheese-KakaoTalk-bot/internal/config"
-	"github.com/park285/Cheese-KakaoTalk-bot/internal/domain"
 	"github.com/park285/Cheese-KakaoTalk-bot/internal/irisfast"
 	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
 	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
 	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchan"
 	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
 	svcchess "github.com/park285/Cheese-KakaoTalk-bot/internal/service/chess"
-	"github.com/park285/Cheese-KakaoTalk-bot/pkg/chessdto"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/tournament"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -32,6 +32,8 @@ import (
 var defaultEgress irisfast.Egress
 var pvpEgress irisfast.Egress
 var pvpPresenter *chesspresenter.Presenter
+var tournamentMgr *tournament.Manager
+var arenaMgr *arena.Manager
 
 func main() {
 	if err := obslog.InitFromEnv(); err != nil {
@@ -43,6 +45,9 @@ func main() {
 	if err != nil {
 		logger.Fatal("config_error", zap.Error(err))
 	}
+	for name, use := range cfg.DeprecatedEnv {
+		logger.Warn("config_deprecated_env", zap.String("name", name), zap.String("use", use))
+	}
 
 	// Align with legacy: do not inject custom HTTP headers
     client := irisfast.NewClient(cfg.IrisBaseURL)
@@ -63,6 +68,19 @@ func main() {
 		logger.Fatal("pvp_repo_init_error", zap.Error(err))
 	}
 	pvpChessMgr.AttachRepository(pvpRepo)
+	pvpChessMgr.SetMaxActiveGames(cfg.MaxConcurrentGames)
+	pvpChessMgr.SetMaxGamesPerUser(cfg.PvpMaxGamesPerUser)
+	pvpChessMgr.SetCorrespondenceIdle(time.Duration(cfg.PvpCorrespondenceIdleDays) * 24 * time.Hour)
+	// 통신 대국: DB(진실 원천)의 진행 중 대국을 Redis로 복원
+	if _, err := pvpChessMgr.RestoreCorrespondence(context.Background()); err != nil {
+		logger.Warn("pvp_correspondence_restore_error", zap.Error(err))
+	}
+	// Tournament repository (same database, pvp_tournament* tables)
+	tourRepo, err := tournament.NewRepository(cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("tournament_repo_init_error", zap.Error(err))
+	}
+	tournamentMgr = tournament.NewManager(tourRepo, pvpChessMgr)
 
 	// Channel lobby manager (Redis-backed)
 	addr, pass, db, err := pvpchess.ParseRedisURLForChan(cfg.RedisURL)
@@ -71,6 +89,7 @@ func main() {
 	}
 	chanRdb := redis.NewClient(&redis.Options{Addr: addr, Password: pass, DB: db})
 	pvpChanMgr := pvpchan.NewManager(chanRdb, pvpChessMgr)
+	arenaMgr = arena.NewManager(chanRdb, pvpChessMgr)
 
 	// 리더 락: 단일 인스턴스 보장 (Redis SET NX + TTL, 주기 갱신)
 	{
@@ -131,24 +150,21 @@ func main() {
 		}
 		deps = d
 	}
+    // 송신 큐: 방별 직렬화(텍스트→이미지 순서 보장) + 간격 + 재시도. 전역/PvP Egress가 공유
+    queue := irisfast.NewQueue(egressQueueOptions(cfg, client, logger))
+    go logEgressQueue(queue, time.Minute)
     // Egress: http|ws|auto (default http). WS dryrun supported.
-    egress := irisfast.NewEgress(cfg.EgressTransport, cfg.WSEgressDryRun, client, ws, obslog.L())
+    egress := queue.Wrap(irisfast.NewEgress(cfg.EgressTransport, cfg.WSEgressDryRun, client, ws, obslog.L()))
     defaultEgress = egress
-    presenter := chesspresenter.NewPresenter(
-        func(room, message string) error { return egress.SendText(context.Background(), room, message) },
-        func(room, imageBase64 string) error { return egress.SendImage(context.Background(), room, imageBase64) },
-    )
+    presenter := chesspresenter.NewEgressPresenter(egress)
 
     // PvP 전용 Egress/Presetner (오버라이드 없으면 전역과 동일)
     pvpMode := cfg.EgressTransport
     pvpDry := cfg.WSEgressDryRun
     if strings.TrimSpace(cfg.PvpEgressTransport) != "" { pvpMode = cfg.PvpEgressTransport }
     if cfg.PvpWSEgressDryRun { pvpDry = true }
-    pvpEgress = irisfast.NewEgress(pvpMode, pvpDry, client, ws, obslog.L())
-    pvpPresenter = chesspresenter.NewPresenter(
-        func(room, message string) error { return pvpEgress.SendText(context.Background(), room, message) },
-        func(room, imageBase64 string) error { return pvpEgress.SendImage(context.Background(), room, imageBase64) },
-    )
+    pvpEgress = queue.Wrap(irisfast.NewEgress(pvpMode, pvpDry, client, ws, obslog.L()))
+    pvpPresenter = chesspresenter.NewEgressPresenter(pvpEgress)
 	formatter := chesspresenter.NewFormatter(prefixProvider{prefix: cfg.BotPrefix})
 
 	// YAML message catalog (required): no code fallback.
@@ -162,34 +178,134 @@ func main() {
 	formatter.SetCatalog(catalog)
 	logger.Info("msgcat_loaded", zap.Bool("override", strings.TrimSpace(os.Getenv("TEMPLATE_DIR")) != ""))
 
+	var svc *svcchess.Service
+	if deps != nil {
+		svc = deps.Service
+	}
+	b := newBot(cfg, pvpChessMgr, pvpChanMgr, svc, presenter, formatter, catalog)
+
 	// Preflight required keys (YAML-only)
 	preflight := []struct {
 		key  string
 		data map[string]string
 	}{
-		{"help.korean", map[string]string{"Prefix": cfg.BotPrefix}},
 		{"pvp.resign.announce", map[string]string{"ResignerName": "A", "WinnerName": "B"}},
         {"lobby.list.error", nil},
         {"lobby.none", nil},
-        {"lobby.make.limit", nil},
-        {"lobby.list.header", nil},
-		{"lobby.list.item", map[string]string{"Code": "C", "CreatorName": "N"}},
+        {"lobby.make.limit", map[string]string{"Prefix": cfg.BotPrefix}},
+		{"lobby.cancel.done", map[string]string{"Code": "C"}},
+		{"lobby.cancel.none", nil},
+		{"lobby.cancel.failed", map[string]string{"Error": "e"}},
+		{"lobby.expired", map[string]string{"CreatorName": "N", "Code": "C", "Minutes": "30"}},
+        {"lobby.list.header", map[string]string{"ScopeLabel": "이 방", "Page": "1", "Pages": "2", "Total": "12"}},
+		{"lobby.list.more", map[string]string{"Prefix": cfg.BotPrefix, "ScopeArg": "", "Next": "2"}},
+		{"lobby.list.record", map[string]string{"Rating": "1500", "Wins": "1", "Losses": "0", "Draws": "0"}},
+		{"lobby.list.record_new", nil},
+		{"lobby.list.item", map[string]string{"Code": "C", "CreatorName": "N", "ColorLabel": "백", "Age": "3분 대기", "Record": "신규", "PIN": "1", "Correspondence": "1"}},
 		{"user.identify.error", nil},
 		{"pvp.busy.in_room", nil},
 		{"pvp.start.announce", map[string]string{"WhiteName": "W", "BlackName": "B"}},
 		{"channel.create.error", map[string]string{"Error": "e"}},
-		{"usage.lobby", map[string]string{"Prefix": cfg.BotPrefix}},
-		{"usage.join", map[string]string{"Prefix": cfg.BotPrefix}},
 		{"join.error", map[string]string{"Error": "e"}},
 		{"join.waiting", nil},
 		{"game.not_found", nil},
 		{"render.error", nil},
 		{"render.board.failed", nil},
 		{"board.send.failed", nil},
+		{"board.text_fallback", map[string]string{"Board": "8 . . ."}},
 		{"no.active.game", nil},
 		{"finish.checkmate", map[string]string{"Winner": "W"}},
 		{"finish.resign", map[string]string{"Winner": "W"}},
 		{"finish.draw", nil},
+		{"finish.agreement", nil},
+		{"pvp.takeback.request", map[string]string{"Name": "A", "Prefix": cfg.BotPrefix}},
+		{"pvp.takeback.accepted", map[string]string{"Name": "A", "Plies": "1"}},
+		{"pvp.takeback.declined", map[string]string{"Name": "A"}},
+		{"pvp.takeback.pending", nil},
+		{"pvp.takeback.none", nil},
+		{"pvp.takeback.own", nil},
+		{"pvp.takeback.empty", nil},
+		{"pvp.takeback.failed", map[string]string{"Error": "e"}},
+		{"pvp.rating.line", map[string]string{"WhiteName": "W", "WhiteRating": "1212", "WhiteDelta": "▲12", "BlackName": "B", "BlackRating": "1188", "BlackDelta": "▼12"}},
+		{"pvp.ranking.room_header", nil},
+		{"pvp.ranking.global_header", nil},
+		{"pvp.ranking.item", map[string]string{"Rank": "1", "Name": "N", "Rating": "1200", "Wins": "0", "Losses": "0", "Draws": "0"}},
+		{"pvp.ranking.empty", nil},
+		{"pvp.ranking.error", map[string]string{"Error": "e"}},
+		{"pvp.spectate.start", map[string]string{"Code": "C", "WhiteName": "W", "BlackName": "B", "Prefix": cfg.BotPrefix}},
+		{"pvp.spectate.stop", map[string]string{"Code": "C"}},
+		{"pvp.spectate.readonly", nil},
+		{"pvp.spectate.not_in_progress", nil},
+		{"pvp.spectate.limit", map[string]string{"Limit": "5"}},
+		{"pvp.spectate.player_room", nil},
+		{"pvp.spectate.already", nil},
+		{"pvp.spectate.none", nil},
+		{"pvp.spectate.failed", map[string]string{"Error": "e"}},
+		{"pvp.challenge.sent", map[string]string{"ChallengerName": "A", "TargetName": "B", "Minutes": "5", "Prefix": cfg.BotPrefix}},
+		{"pvp.challenge.declined", map[string]string{"ChallengerName": "A", "TargetName": "B"}},
+		{"pvp.challenge.cancelled", map[string]string{"TargetName": "B"}},
+		{"pvp.challenge.unknown", map[string]string{"TargetName": "B"}},
+		{"pvp.challenge.pending", nil},
+		{"pvp.challenge.none", nil},
+		{"pvp.challenge.self", nil},
+		{"pvp.challenge.failed", map[string]string{"Error": "e"}},
+		{"pvp.match.queued", map[string]string{"Waiting": "1", "Prefix": cfg.BotPrefix}},
+		{"pvp.match.left", nil},
+		{"pvp.match.already", map[string]string{"Prefix": cfg.BotPrefix}},
+		{"pvp.match.none", nil},
+		{"pvp.match.disabled", nil},
+		{"pvp.match.failed", map[string]string{"Error": "e"}},
+		{"pvp.capacity", nil},
+		{"tournament.created", map[string]string{"Name": "N", "FormatLabel": "풀리그", "Rounds": "", "Prefix": cfg.BotPrefix}},
+		{"tournament.joined", map[string]string{"PlayerName": "P", "Name": "N", "Count": "2"}},
+		{"tournament.left", map[string]string{"PlayerName": "P", "Name": "N", "Count": "1"}},
+		{"tournament.cancelled", map[string]string{"Name": "N"}},
+		{"tournament.round.header", map[string]string{"Name": "N", "Round": "1", "Rounds": "3"}},
+		{"tournament.round.pairing", map[string]string{"Board": "1", "White": "W", "Black": "B", "Result": "", "Pending": "1"}},
+		{"tournament.round.bye", map[string]string{"Name": "N"}},
+		{"tournament.standings.header", map[string]string{"Name": "N", "FormatLabel": "스위스", "Status": "진행 중", "Round": "1", "Rounds": "3"}},
+		{"tournament.standings.row", map[string]string{"Rank": "1", "Name": "N", "Points": "1.5", "Buchholz": "2", "SB": "1", "Games": "2"}},
+		{"tournament.standings.empty", nil},
+		{"tournament.finished", map[string]string{"Name": "N", "Winner": "W"}},
+		{"tournament.error.exists", nil},
+		{"tournament.error.none", map[string]string{"Prefix": cfg.BotPrefix}},
+		{"tournament.error.closed", nil},
+		{"tournament.error.already", nil},
+		{"tournament.error.not_registered", nil},
+		{"tournament.error.not_organizer", nil},
+		{"tournament.error.too_few", nil},
+		{"tournament.error.invalid_rounds", nil},
+		{"tournament.error.failed", map[string]string{"Error": "e"}},
+		{"pvp.corr.your_move", map[string]string{"PlayerName": "P", "OpponentName": "O", "Move": "e4", "Ply": "1", "Handle": "1", "Prefix": cfg.BotPrefix}},
+		{"pvp.busy.limit", map[string]string{"Max": "3", "Prefix": cfg.BotPrefix}},
+		{"pvp.games.header", map[string]string{"Count": "1", "Prefix": cfg.BotPrefix}},
+		{"pvp.games.item", map[string]string{"Handle": "1", "OpponentName": "O", "ColorLabel": "백", "Correspondence": "1", "MyTurn": "1", "Moves": "10", "Age": "3시간", "Default": "", "Here": "1"}},
+		{"pvp.games.none", map[string]string{"Prefix": cfg.BotPrefix}},
+		{"pvp.games.ambiguous", map[string]string{"Games": "#1 vs O, #2 vs P", "Prefix": cfg.BotPrefix}},
+		{"pvp.games.no_handle", map[string]string{"Handle": "3", "Prefix": cfg.BotPrefix}},
+		{"pvp.games.default.set", map[string]string{"Handle": "1", "WhiteName": "W", "BlackName": "B"}},
+		{"pvp.games.default.cleared", nil},
+		{"pvp.games.default.not_in_room", map[string]string{"Handle": "1", "Prefix": cfg.BotPrefix}},
+		{"arena.vacant", map[string]string{"Prefix": cfg.BotPrefix}},
+		{"arena.status", map[string]string{"ChampionName": "C", "Reign": "1시간", "Defenses": "0", "Challenger": ""}},
+		{"arena.record", map[string]string{"ChampionName": "C", "Defenses": "1"}},
+		{"arena.claimed", map[string]string{"ChampionName": "C", "Prefix": cfg.BotPrefix}},
+		{"arena.title_game", map[string]string{"ChampionName": "C", "ChallengerName": "D", "Defenses": "0"}},
+		{"arena.defended", map[string]string{"ChampionName": "C", "Draw": "", "Defenses": "1", "Reign": "1시간"}},
+		{"arena.new_champion", map[string]string{"ChampionName": "D", "PreviousName": "C", "Defenses": "1", "Reign": "1시간"}},
+		{"arena.voided", map[string]string{"ChampionName": "C"}},
+		{"arena.error.already_champion", nil},
+		{"arena.error.pending", nil},
+		{"arena.error.champion_busy", map[string]string{"ChampionName": "C"}},
+		{"arena.error.failed", map[string]string{"Error": "e"}},
+		{"pvp.abandon.forfeit", map[string]string{"IdleName": "A", "WinnerName": "B"}},
+		{"pvp.abandon.aborted", nil},
+		{"pvp.draw.offer", map[string]string{"Name": "A", "Prefix": cfg.BotPrefix}},
+		{"pvp.draw.declined", map[string]string{"Name": "A"}},
+		{"pvp.draw.pending", nil},
+		{"pvp.draw.none", nil},
+		{"pvp.draw.own", nil},
+		{"pvp.draw.failed", map[string]string{"Error": "e"}},
 		{"resign.process.error", nil},
 		{"resign.failed", map[string]string{"Error": "e"}},
 		{"move.failed", nil},
@@ -200,15 +316,23 @@ func main() {
 		{"chess.status.error", map[string]string{"Error": "e"}},
 		{"chess.undo.failed", map[string]string{"Error": "e"}},
 		{"chess.history.error", map[string]string{"Error": "e"}},
-		{"usage.game", map[string]string{"Prefix": cfg.BotPrefix}},
 		{"game.id.invalid", nil},
 		{"game.fetch.error", map[string]string{"Error": "e"}},
 		{"chess.profile.error", map[string]string{"Error": "e"}},
-		{"usage.preset", map[string]string{"Prefix": cfg.BotPrefix}},
 		{"chess.preset.update.failed", map[string]string{"Error": "e"}},
 		{"chess.assist.failed", map[string]string{"Error": "e"}},
-		{"lobby_make.success", map[string]string{"Code": "CODE", "Prefix": cfg.BotPrefix}},
-		{"formatter.start.body", map[string]string{"Resumed": "false", "Preset": "level3", "ProfileRatingLine": "• 레이팅: 1200 (▲10)", "ProfileRecordLine": "• 전적: 1승 0패 0무 (1판)", "Prefix": cfg.BotPrefix}},
+		{"chess.disabled", nil},
+		{"chess.start.invalid_position", map[string]string{"Error": "e"}},
+		{"chess.analysis.failed", map[string]string{"Error": "e"}},
+		{"chess.analysis.unavailable", nil},
+		{"chess.replay.failed", map[string]string{"Error": "e"}},
+		{"chess.replay.too_large", nil},
+		{"lobby_make.success", map[string]string{"Code": "CODE", "Prefix": cfg.BotPrefix, "Unlisted": "1", "HasPIN": "1", "Correspondence": "1"}},
+		{"lobby.pin.invalid", nil},
+		{"join.pin.required", map[string]string{"Prefix": cfg.BotPrefix, "Code": "C"}},
+		{"join.pin.wrong", nil},
+		{"join.pin.locked", nil},
+		{"formatter.start.body", map[string]string{"Resumed": "false", "Preset": "level3", "ProfileRatingLine": "• 레이팅: 1200 (▲10)", "ProfileRecordLine": "• 전적: 1승 0패 0무 (1판)", "PlayerSide": "흑", "CustomStart": "", "Prefix": cfg.BotPrefix}},
 		{"formatter.status.body", map[string]string{"Preset": "level3", "MoveCount": "10", "RecentLine": "• 최근 e2e4 e7e5", "ProfileInfo": "• 레이팅: 1200", "MaterialLine": "• 잡은 기물 점수 백 +3 / 흑 +0", "CapturedLine": "• 잡은 기물 백 P / 흑 -", "Prefix": cfg.BotPrefix}},
 		{"formatter.resign.body", map[string]string{"OutcomeText": "🛑 기권하여 패배로 기록되었습니다.", "ProfileInfo": "• 레이팅: 1200"}},
 		{"formatter.move.body", map[string]string{"OutcomeText": "✅ 승리했습니다! 축하드립니다.", "Preset": "level3", "RatingLine": "• 현재 레이팅: 1210 (▲10)", "RecordLine": "• 누적 전적: 1승 0패 0무 (1판)", "GameIDLine": "기보 ID: #1"}},
@@ -219,11 +343,20 @@ func main() {
 		{"formatter.history.footer", map[string]string{"Prefix": cfg.BotPrefix}},
 		{"formatter.profile.header", nil},
 	}
+	// 명령 레지스트리의 사용법/도움말 키
+	for _, key := range b.catalogKeys() {
+		preflight = append(preflight, struct {
+			key  string
+			data map[string]string
+		}{key, map[string]string{"Prefix": cfg.BotPrefix}})
+	}
 	for _, pf := range preflight {
 		if _, err := catalog.Render(pf.key, pf.data); err != nil {
 			logger.Fatal("msgcat_preflight_error", zap.String("key", pf.key), zap.Error(err))
 		}
 	}
+
+	b.results = registerResultHooks(pvpChessMgr, pvpChanMgr, tournamentMgr, arenaMgr, catalog)
 	type cachedSender struct {
 		name string
 		ts   time.Time
@@ -326,6 +459,8 @@ func main() {
 		sprefix := sanitizeText(cfg.BotPrefix)
 		smsg := sanitizeText(trimmed)
 		if !strings.HasPrefix(smsg, sprefix) {
+			// 지정 도전(@이름)이 사용자 ID로 확정되도록 명령이 아닌 대화도 말한 사람을 기록
+			_ = pvpChanMgr.NoteSpeaker(context.Background(), rid, userIDFromMessage(msg), displayUser)
 			// 최소 로그: room, room_id, user만 기록
 			logger.Info(
 				"recv_message",
@@ -384,13 +519,31 @@ func main() {
 			zap.String("user", strings.TrimSpace(displayUser)),
 			zap.String("cmd", cmdToken),
 		)
-		var svc *svcchess.Service
-		if deps != nil {
-			svc = deps.Service
-		}
-		go handleCommand(client, cfg, pvpChessMgr, pvpChanMgr, svc, presenter, formatter, catalog, msg)
+		go b.handle(msg)
 	})
 
+	// 방치 대국 정리: 리더 인스턴스에서만 실행
+	sweepCtx, stopSweep := context.WithCancel(context.Background())
+	defer stopSweep()
+	if cfg.PvpIdleTimeoutMin > 0 {
+		idle := time.Duration(cfg.PvpIdleTimeoutMin) * time.Minute
+		interval := time.Duration(cfg.PvpSweepIntervalSec) * time.Second
+		pvpChessMgr.StartAbandonSweeper(sweepCtx, idle, interval, func(g *pvpchess.Game) {
+			announcePvPAbandon(pvpChanMgr, catalog, g)
+			b.results.flush(sweepCtx, g.ID)
+		})
+		logger.Info("pvp_abandon_sweeper_started", zap.Duration("idle", idle), zap.Duration("interval", interval))
+	}
+	// 대기방 만료: 상대 없이 오래된 대기방 정리 후 생성자 방에 안내
+	if cfg.PvpLobbyTTLMin > 0 {
+		maxAge := time.Duration(cfg.PvpLobbyTTLMin) * time.Minute
+		interval := time.Duration(cfg.PvpSweepIntervalSec) * time.Second
+		pvpChanMgr.StartLobbySweeper(sweepCtx, maxAge, interval, func(meta *pvpchan.ChannelMeta) {
+			announceLobbyExpired(cfg, catalog, meta)
+		})
+		logger.Info("lobby_expiry_sweeper_started", zap.Duration("max_age", maxAge), zap.Duration("interval", interval))
+	}
+
 	cctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	if err := ws.Connect(cctx); err != nil {
 		cancel()
@@ -402,738 +555,67 @@ func main() {
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
 
+	stopSweep()
 	_ = ws.Close(context.Background())
 	_ = pvpChessMgr.Close()
 	_ = pvpRepo.Close()
+	_ = tourRepo.Close()
 }
 
-func handleCommand(client *irisfast.Client, cfg *appcfg.AppConfig, pvpChessMgr *pvpchess.Manager, pvpChanMgr *pvpchan.Manager, chess *svcchess.Service, presenter *chesspresenter.Presenter, formatter *chesspresenter.Formatter, catalog *msgcat.Catalog, msg *irisfast.Message) {
-	// Parse input using sanitized text to tolerate zero-width/NBSP and JSON-only frames
-	msgText := sanitizeText(messageText(msg))
-	prefix := sanitizeText(cfg.BotPrefix)
-	raw := strings.TrimSpace(strings.TrimPrefix(msgText, prefix))
-	if raw == "" {
-		_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), formatter.Help())
-		return
-	}
-	parts := strings.Fields(raw)
-	rawCmd := parts[0]
-	cmd := strings.ToLower(rawCmd)
-	args := parts[1:]
-
-	switch cmd {
-	case "help", "도움":
-		_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), formatter.Help())
-	case "방":
-		if len(args) >= 1 {
-			sub := strings.ToLower(strings.TrimSpace(args[0]))
-			if sub == "리스트" || sub == "목록" {
-				metas, err := pvpChanMgr.ListLobby(context.Background())
-				if err != nil {
-					if txt, err := catalog.Render("lobby.list.error", nil); err == nil {
-						_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-					} else {
-						obslog.L().Error("msgcat_render_error", zap.String("key", "lobby.list.error"), zap.Error(err))
-					}
-					return
-				}
-				if len(metas) == 0 {
-					if txt, err := catalog.Render("lobby.none", nil); err == nil {
-						_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-					} else {
-						obslog.L().Error("msgcat_render_error", zap.String("key", "lobby.none"), zap.Error(err))
-					}
-					return
-				}
-				var b strings.Builder
-				if header, e := catalog.Render("lobby.list.header", nil); e == nil {
-					b.WriteString(header)
-				} else {
-					b.WriteString("대기 중인 방:")
-				}
-				b.WriteString("\n")
-				for _, m := range metas {
-					if item, e := catalog.Render("lobby.list.item", map[string]string{"Code": m.ID, "CreatorName": m.CreatorName}); e == nil {
-						b.WriteString(item)
-					} else {
-						fmt.Fprintf(&b, "• 코드: %s | 만든이: %s", m.ID, m.CreatorName)
-					}
-					b.WriteString("\n")
-				}
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), b.String())
-				return
-			} else if sub == "생성" || sub == "만들기" {
-				user := userIDFromMessage(msg)
-				if user == "" {
-					if txt, err := catalog.Render("user.identify.error", nil); err == nil {
-						_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-					} else {
-						obslog.L().Error("msgcat_render_error", zap.String("key", "user.identify.error"), zap.Error(err))
-					}
-					return
-				}
-				roomID := extractRoomID(msg)
-				if g, _ := pvpChessMgr.GetActiveGameByUserInRoom(context.Background(), user, roomID); g != nil {
-					if txt, err := catalog.Render("pvp.busy.in_room", nil); err == nil {
-						_ = defaultEgress.SendText(context.Background(), roomID, txt)
-					} else {
-						obslog.L().Error("msgcat_render_error", zap.String("key", "pvp.busy.in_room"), zap.Error(err))
-					}
-					return
-				}
-				if chess != nil {
-					meta := svcchess.SessionMeta{SessionID: sessionIDFor(msg), Room: roomID, Sender: senderName(msg)}
-					if st, err := chess.Status(context.Background(), meta); err == nil && st != nil {
-						if txt, e := catalog.Render("pvp.busy.in_room", nil); e == nil {
-							_ = defaultEgress.SendText(context.Background(), roomID, txt)
-						} else {
-							_ = defaultEgress.SendText(context.Background(), roomID, "이미 진행 중인 대국이 있습니다. 종료 후 진행하세요.")
-						}
-						return
-					}
-				}
-                mr, err := pvpChanMgr.Make(context.Background(), roomID, user, senderName(msg), pvpchan.ColorRandom)
-                if err != nil {
-                    // 동일 사용자 다중 방 생성 제한 안내
-                    if strings.Contains(strings.ToLower(err.Error()), "already has a lobby") {
-                        if txt, e := catalog.Render("lobby.make.limit", nil); e == nil { _ = client.SendMessage(context.Background(), extractRoomID(msg), txt) } else { _ = client.SendMessage(context.Background(), extractRoomID(msg), "이미 생성한 대기 방이 있어 새로 만들 수 없습니다.") }
-                        return
-                    }
-                    if txt, e := catalog.Render("channel.create.error", map[string]string{"Error": err.Error()}); e == nil {
-                        _ = client.SendMessage(context.Background(), extractRoomID(msg), txt)
-                    } else {
-                        _ = client.SendMessage(context.Background(), extractRoomID(msg), "채널 생성 실패: "+err.Error())
-                    }
-                    return
-                }
-				if txt, err := catalog.Render("lobby_make.success", map[string]string{"Code": mr.Code, "Prefix": cfg.BotPrefix}); err == nil {
-					_ = client.SendMessage(context.Background(), extractRoomID(msg), txt)
-				} else {
-					obslog.L().Error("msgcat_render_error", zap.String("key", "lobby_make.success"), zap.Error(err))
-				}
-				return
-			}
-		}
-		if txt, err := catalog.Render("usage.lobby", map[string]string{"Prefix": cfg.BotPrefix}); err == nil {
-			_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-		} else {
-			obslog.L().Error("msgcat_render_error", zap.String("key", "usage.lobby"), zap.Error(err))
-		}
-		return
-	case "참가", "방참가":
-		if len(args) < 1 {
-			if txt, err := catalog.Render("usage.join", map[string]string{"Prefix": cfg.BotPrefix}); err == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				obslog.L().Error("msgcat_render_error", zap.String("key", "usage.join"), zap.Error(err))
-			}
-			return
-		}
-		code := strings.TrimSpace(args[0])
-		user := userIDFromMessage(msg)
-		roomID := extractRoomID(msg)
-		if g, _ := pvpChessMgr.GetActiveGameByUserInRoom(context.Background(), user, roomID); g != nil {
-			if txt, err := catalog.Render("pvp.busy.in_room", nil); err == nil {
-				_ = client.SendMessage(context.Background(), roomID, txt)
-			} else {
-				obslog.L().Error("msgcat_render_error", zap.String("key", "pvp.busy.in_room"), zap.Error(err))
-			}
-			return
-		}
-		if chess != nil {
-			meta := svcchess.SessionMeta{SessionID: sessionIDFor(msg), Room: roomID, Sender: senderName(msg)}
-			if st, err := chess.Status(context.Background(), meta); err == nil && st != nil {
-				if txt, err := catalog.Render("pvp.busy.in_room", nil); err == nil {
-					_ = client.SendMessage(context.Background(), roomID, txt)
-				} else {
-					obslog.L().Error("msgcat_render_error", zap.String("key", "pvp.busy.in_room"), zap.Error(err))
-				}
-				return
-			}
-		}
-		jr, err := pvpChanMgr.Join(context.Background(), extractRoomID(msg), code, user, senderName(msg), pvpchan.ColorRandom)
+// registerResultHooks feeds finished PvP games to the tournament and arena managers. 후속 안내(다음 라운드,
+// 최종 순위, 타이틀 결과)는 바로 보내지 않고 반환된 resultFollowUps에 쌓였다가, 종료 안내를 보낸 쪽이
+// flush하면 그 뒤에 순서대로 나갑니다.
+func registerResultHooks(pvpChessMgr *pvpchess.Manager, pvpChanMgr *pvpchan.Manager, tournaments *tournament.Manager, arenas *arena.Manager, catalog *msgcat.Catalog) *resultFollowUps {
+	f := &resultFollowUps{pending: make(map[string][]func(context.Context))}
+	// 토너먼트 대국 결과 반영: 라운드 종료 시 다음 대진/최종 순위 안내
+	pvpChessMgr.OnResult(func(ctx context.Context, g *pvpchess.Game) {
+		ev, err := tournaments.IngestResult(ctx, g)
 		if err != nil {
-			if txt, e := catalog.Render("join.error", map[string]string{"Error": err.Error()}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				obslog.L().Error("msgcat_render_error", zap.String("key", "join.error"), zap.Error(e))
-			}
-			return
-		}
-		if !jr.Started {
-			if txt, err := catalog.Render("join.waiting", nil); err == nil {
-				_ = client.SendMessage(context.Background(), extractRoomID(msg), txt)
-			} else {
-				obslog.L().Error("msgcat_render_error", zap.String("key", "join.waiting"), zap.Error(err))
-			}
-			return
-		}
-		g, _ := pvpChessMgr.GetActiveGameByUser(context.Background(), user)
-		if g == nil {
-			if txt, err := catalog.Render("game.not_found", nil); err == nil {
-				_ = client.SendMessage(context.Background(), extractRoomID(msg), txt)
-			} else {
-				obslog.L().Error("msgcat_render_error", zap.String("key", "game.not_found"), zap.Error(err))
-			}
-			return
-		}
-        wDTO, wErr := pvpChessMgr.ToDTOForViewer(context.Background(), g, g.WhiteID)
-        bDTO, bErr := pvpChessMgr.ToDTOForViewer(context.Background(), g, g.BlackID)
-        if wErr != nil || bErr != nil {
-            if txt, err := catalog.Render("render.error", nil); err == nil {
-                _ = client.SendMessage(context.Background(), extractRoomID(msg), txt)
-            } else {
-                obslog.L().Error("msgcat_render_error", zap.String("key", "render.error"), zap.Error(err))
-            }
-            return
-        }
-        rooms := fanoutRooms(context.Background(), pvpChanMgr, g, extractRoomID(msg))
-        // 현재 방 우선 순위 적용
-        rooms = prioritizeRooms(rooms, extractRoomID(msg))
-        obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", "start"))
-        text, _ := catalog.Render("pvp.start.announce", map[string]string{"WhiteName": g.WhiteName, "BlackName": g.BlackName})
-        for i, r := range rooms {
-            viewer := jr.Meta.CreatorID
-            if strings.TrimSpace(r) != strings.TrimSpace(jr.Meta.CreatorRoom) {
-                if strings.TrimSpace(viewer) == strings.TrimSpace(g.WhiteID) { viewer = g.BlackID } else { viewer = g.WhiteID }
-            }
-            vdto := wDTO
-            if strings.TrimSpace(viewer) == strings.TrimSpace(g.BlackID) { vdto = bDTO }
-        // 1) 텍스트 먼저 전송 (개별 에러 로그)
-            if strings.TrimSpace(text) != "" {
-                if err := pvpEgress.SendText(context.Background(), r, text); err != nil {
-                    obslog.L().Warn("pvp_start_text_error", zap.Error(err), zap.String("room_id", r), zap.String("game_id", g.ID))
-                }
-            }
-            // 2) 짧은 간격 후 이미지만 전송(일부 게이트웨이에서 연속 전송 드롭 방지)
-            // 환경값 START_IMAGE_DELAY_MS로 제어(기본 150ms)
-            delay := time.Duration(cfg.StartImageDelayMS) * time.Millisecond
-            if delay < 0 { delay = 0 }
-            time.Sleep(delay)
-            if err := pvpPresenter.Board(r, "", vdto); err != nil {
-                obslog.L().Warn("pvp_board_send_error",
-                    zap.Error(err),
-                    zap.String("room_id", r),
-                    zap.String("game_id", g.ID),
-                    zap.String("phase", "start"),
-                )
-                // 보드 전송 실패 시 보조 안내문 전송
-                if t, e := catalog.Render("board.send.failed", nil); e == nil {
-                    _ = pvpEgress.SendText(context.Background(), r, t)
-                } else {
-                    _ = pvpEgress.SendText(context.Background(), r, "보드 전송 실패")
-                }
-            }
-            // 방 간 지연 적용(이미지 드롭 완화)
-            if i < len(rooms)-1 {
-                d := time.Duration(cfg.FanoutImageDelayMS) * time.Millisecond
-                if d > 0 { time.Sleep(d) }
-            }
-        }
-		return
-	case "방생성":
-		user := userIDFromMessage(msg)
-		if user == "" {
-			if txt, err := catalog.Render("user.identify.error", nil); err == nil {
-				_ = client.SendMessage(context.Background(), extractRoomID(msg), txt)
-			} else {
-				obslog.L().Error("msgcat_render_error", zap.String("key", "user.identify.error"), zap.Error(err))
-			}
-			return
-		}
-		roomID := extractRoomID(msg)
-		if g, _ := pvpChessMgr.GetActiveGameByUserInRoom(context.Background(), user, roomID); g != nil {
-			if txt, err := catalog.Render("pvp.busy.in_room", nil); err == nil {
-				_ = client.SendMessage(context.Background(), roomID, txt)
-			} else {
-				obslog.L().Error("msgcat_render_error", zap.String("key", "pvp.busy.in_room"), zap.Error(err))
-			}
+			obslog.L().Warn("tournament_ingest_error", zap.String("game_id", g.ID), zap.Error(err))
 			return
 		}
-		if chess != nil {
-			meta := svcchess.SessionMeta{SessionID: sessionIDFor(msg), Room: roomID, Sender: senderName(msg)}
-			if st, err := chess.Status(context.Background(), meta); err == nil && st != nil {
-				if txt, err := catalog.Render("pvp.busy.in_room", nil); err == nil {
-					_ = client.SendMessage(context.Background(), roomID, txt)
-				} else {
-					obslog.L().Error("msgcat_render_error", zap.String("key", "pvp.busy.in_room"), zap.Error(err))
-				}
-				return
-			}
+		if ev != nil {
+			f.add(g.ID, func(context.Context) { announceTournamentEvent(pvpChessMgr, pvpChanMgr, catalog, ev) })
 		}
-mr, err := pvpChanMgr.Make(context.Background(), roomID, user, senderName(msg), pvpchan.ColorRandom)
-if err != nil {
-    if strings.Contains(strings.ToLower(err.Error()), "already has a lobby") {
-        if txt, e := catalog.Render("lobby.make.limit", nil); e == nil { _ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt) } else { _ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "이미 생성한 대기 방이 있어 새로 만들 수 없습니다.") }
-        return
-    }
-    if txt, e := catalog.Render("channel.create.error", map[string]string{"Error": err.Error()}); e == nil {
-        _ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-    } else {
-        obslog.L().Error("msgcat_render_error", zap.String("key", "channel.create.error"), zap.Error(e))
-    }
-    return
-}
-		if txt, e := catalog.Render("lobby_make.success", map[string]string{"Code": mr.Code, "Prefix": cfg.BotPrefix}); e == nil {
-			_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-		} else {
-			obslog.L().Error("msgcat_render_error", zap.String("key", "lobby_make.success"), zap.Error(e))
-		}
-		return
-	case "방리스트", "방목록":
-		metas, err := pvpChanMgr.ListLobby(context.Background())
+	})
+	// 챔피언전 타이틀전 결과 반영: 방어/타이틀 이동 안내
+	pvpChessMgr.OnResult(func(ctx context.Context, g *pvpchess.Game) {
+		ev, err := arenas.IngestResult(ctx, g)
 		if err != nil {
-			if txt, e := catalog.Render("lobby.list.error", nil); e == nil {
-				_ = client.SendMessage(context.Background(), extractRoomID(msg), txt)
-			} else {
-				obslog.L().Error("msgcat_render_error", zap.String("key", "lobby.list.error"), zap.Error(e))
-			}
+			obslog.L().Warn("arena_ingest_error", zap.String("game_id", g.ID), zap.Error(err))
 			return
 		}
-		if len(metas) == 0 {
-			if txt, e := catalog.Render("lobby.none", nil); e == nil {
-				_ = client.SendMessage(context.Background(), extractRoomID(msg), txt)
-			} else {
-				obslog.L().Error("msgcat_render_error", zap.String("key", "lobby.none"), zap.Error(e))
-			}
-			return
-		}
-		var b strings.Builder
-		if header, e := catalog.Render("lobby.list.header", nil); e == nil {
-			b.WriteString(header)
-		} else {
-			b.WriteString("대기 중인 방:")
-		}
-		b.WriteString("\n")
-		for _, m := range metas {
-			if item, e := catalog.Render("lobby.list.item", map[string]string{"Code": m.ID, "CreatorName": m.CreatorName}); e == nil {
-				b.WriteString(item)
-			} else {
-				fmt.Fprintf(&b, "• 코드: %s | 만든이: %s", m.ID, m.CreatorName)
-			}
-			b.WriteString("\n")
-		}
-		_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), b.String())
-		return
-	case "현황", "보드":
-		// 세션우선 라우팅: PvP → 레거시 → 없음
-		ctx := context.Background()
-		roomID := extractRoomID(msg)
-		user := userIDFromMessage(msg)
-
-		// 1) PvP 활성 대국 우선 (동일 Room×User)
-		if pvpChessMgr != nil {
-			if g, err := pvpChessMgr.GetActiveGameByUserInRoom(ctx, user, roomID); err == nil && g != nil {
-				obslog.L().Info("route_decision", zap.String("cmd", "status"), zap.String("mode", "pvp"), zap.String("room_id", roomID), zap.String("user", strings.TrimSpace(user)))
-				wDTO, wErr := pvpChessMgr.ToDTOForViewer(ctx, g, g.WhiteID)
-				bDTO, bErr := pvpChessMgr.ToDTOForViewer(ctx, g, g.BlackID)
-				if wErr != nil || bErr != nil {
-					if txt, e := catalog.Render("render.error", nil); e == nil { _ = client.SendMessage(ctx, roomID, txt) } else { _ = client.SendMessage(ctx, roomID, "표시 오류") }
-					return
-				}
-                		rooms := fanoutRooms(ctx, pvpChanMgr, g, roomID)
-                		// 현재 방 우선 순위 적용
-                		rooms = prioritizeRooms(rooms, roomID)
-                		obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", "status"))
-                		meta, _, _ := pvpChanMgr.MetaByGame(ctx, g)
-                	for i, r := range rooms {
-                		viewer := g.WhiteID
-                		if meta != nil {
-                			if strings.TrimSpace(r) == strings.TrimSpace(meta.CreatorRoom) {
-                				viewer = strings.TrimSpace(meta.CreatorID)
-                			} else {
-                				if strings.TrimSpace(meta.CreatorID) == strings.TrimSpace(g.WhiteID) { viewer = g.BlackID } else { viewer = g.WhiteID }
-                			}
-                		}
-                		vdto := wDTO
-                		if strings.TrimSpace(viewer) == strings.TrimSpace(g.BlackID) { vdto = bDTO }
-                		if err := pvpPresenter.Board(r, "", vdto); err != nil {
-                			obslog.L().Warn("pvp_board_send_error",
-                				zap.Error(err),
-                				zap.String("room_id", r),
-                				zap.String("game_id", g.ID),
-                				zap.String("phase", "status"),
-                			)
-                			if txt, e := catalog.Render("board.send.failed", nil); e == nil { _ = pvpEgress.SendText(ctx, r, txt) } else { _ = pvpEgress.SendText(ctx, r, "보드 전송 실패") }
-                		}
-                		// 방 간 지연 적용(이미지 드롭 완화)
-                		if i < len(rooms)-1 {
-                			d := time.Duration(cfg.FanoutImageDelayMS) * time.Millisecond
-                			if d > 0 { time.Sleep(d) }
-                		}
-                	}
-				return
-			}
+		if ev != nil {
+			f.add(g.ID, func(context.Context) { announceArenaEvent(catalog, ev) })
 		}
-		// 2) 레거시 활성 세션이 있으면 레거시 현황
-		if chess != nil {
-			meta := svcchess.SessionMeta{SessionID: sessionIDFor(msg), Room: roomID, Sender: senderName(msg)}
-			if st, err := chess.Status(ctx, meta); err == nil && st != nil {
-				obslog.L().Info("route_decision", zap.String("cmd", "status"), zap.String("mode", "legacy"), zap.String("room_id", roomID), zap.String("user", strings.TrimSpace(user)))
-				_ = presenter.Board(roomID, formatter.Status(chesspresenterAdaptState(st)), chesspresenterAdaptState(st))
-				return
-			}
-		}
-		// 3) 둘 다 없으면 안내
-		obslog.L().Info("route_decision", zap.String("cmd", "status"), zap.String("mode", "none"), zap.String("room_id", roomID), zap.String("user", strings.TrimSpace(user)))
-		if txt, err := catalog.Render("no.active.game", nil); err == nil {
-			_ = defaultEgress.SendText(ctx, roomID, txt)
-		} else {
-			_ = defaultEgress.SendText(ctx, roomID, "활성 대국이 없습니다.")
-		}
-		return
-	case "기권":
-		// 세션우선 라우팅: PvP → 레거시 → 없음
-		ctx := context.Background()
-		roomID := extractRoomID(msg)
-		user := userIDFromMessage(msg)
-
-		// 1) PvP 활성 대국이 있으면 PvP 기권
-                if pvpChessMgr != nil {
-                    if gInRoom, _ := pvpChessMgr.GetActiveGameByUserInRoom(ctx, user, roomID); gInRoom != nil {
-                        obslog.L().Info("route_decision", zap.String("cmd", "resign"), zap.String("mode", "pvp"), zap.String("room_id", roomID), zap.String("user", strings.TrimSpace(user)))
-                        preID := gInRoom.ID
-                        g, _, err := pvpChessMgr.ResignByRoom(ctx, user, roomID)
-                        if err != nil || g == nil {
-                            // 최종 상태 재조회: 이미 종료되었으면 개인화 안내만 전송
-                            if gFinal, _ := pvpChessMgr.LoadGame(ctx, preID); gFinal != nil && gFinal.Status != pvpchess.StatusActive {
-                                // 아래 공용 분기와 동일한 개인화 전송 로직 사용
-                                resignerName := gFinal.WhiteName
-                                if strings.TrimSpace(user) == strings.TrimSpace(gFinal.BlackID) { resignerName = gFinal.BlackName }
-                                if strings.TrimSpace(resignerName) == "" { resignerName = strings.TrimSpace(senderName(msg)) }
-                                winnerName := ""
-                                if strings.TrimSpace(gFinal.Winner) == strings.TrimSpace(gFinal.WhiteID) { winnerName = gFinal.WhiteName }
-                                if strings.TrimSpace(gFinal.Winner) == strings.TrimSpace(gFinal.BlackID) { winnerName = gFinal.BlackName }
-                                finishText, _ := catalog.Render("pvp.resign.announce", map[string]string{"ResignerName": strings.TrimSpace(resignerName), "WinnerName": strings.TrimSpace(winnerName)})
-                                rooms := fanoutRooms(ctx, pvpChanMgr, gFinal, roomID)
-                                // 현재 방 우선 순위 적용
-                                rooms = prioritizeRooms(rooms, roomID)
-                                obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", gFinal.ID), zap.String("phase", "resign"))
-                                meta, _, _ := pvpChanMgr.MetaByGame(ctx, gFinal)
-                                for i, r := range rooms {
-                                    viewer := strings.TrimSpace(gFinal.WhiteID)
-                                    if meta != nil {
-                                        if strings.TrimSpace(r) == strings.TrimSpace(meta.CreatorRoom) {
-                                            viewer = strings.TrimSpace(meta.CreatorID)
-                                        } else {
-                                            if strings.TrimSpace(meta.CreatorID) == strings.TrimSpace(gFinal.WhiteID) { viewer = strings.TrimSpace(gFinal.BlackID) } else { viewer = strings.TrimSpace(gFinal.WhiteID) }
-                                        }
-                                    }
-                                    var msgText string
-                                    if viewer == strings.TrimSpace(user) {
-                                        if t, e := catalog.Render("pvp.resign.loser", nil); e == nil { msgText = t }
-                                    } else {
-                                        if t, e := catalog.Render("pvp.resign.winner", nil); e == nil { msgText = t }
-                                    }
-                                    if strings.TrimSpace(msgText) == "" { msgText = finishText }
-                                    if strings.TrimSpace(msgText) == "" {
-                                        if tt, ee := catalog.Render("board.send.failed", nil); ee == nil { msgText = tt } else { msgText = "보드 전송 실패" }
-                                    }
-                                    _ = pvpEgress.SendText(ctx, r, msgText)
-                                    // 방 간 지연 적용(드롭 완화)
-                                    if i < len(rooms)-1 {
-                                        d := time.Duration(cfg.FanoutImageDelayMS) * time.Millisecond
-                                        if d > 0 { time.Sleep(d) }
-                                    }
-                                }
-                                return
-                            }
-                            // 여전히 ACTIVE → 실패 안내
-                            if t, e := catalog.Render("resign.process.error", nil); e == nil {
-                                _ = pvpEgress.SendText(ctx, roomID, t)
-                            } else {
-                                _ = pvpEgress.SendText(ctx, roomID, "기권 처리 실패")
-                            }
-                            return
-                        }
-                // YAML-only resign announce (이미지 전송 제거: 텍스트만 전송)
-                resignerName := g.WhiteName
-                if strings.TrimSpace(user) == strings.TrimSpace(g.BlackID) {
-                    resignerName = g.BlackName
-                }
-                if strings.TrimSpace(resignerName) == "" {
-                    resignerName = strings.TrimSpace(senderName(msg))
-                }
-                winnerName := ""
-                if strings.TrimSpace(g.Winner) == strings.TrimSpace(g.WhiteID) {
-                    winnerName = g.WhiteName
-                }
-                if strings.TrimSpace(g.Winner) == strings.TrimSpace(g.BlackID) {
-                    winnerName = g.BlackName
-                }
-                finishText, _ := catalog.Render("pvp.resign.announce", map[string]string{
-                    "ResignerName": strings.TrimSpace(resignerName),
-                    "WinnerName":   strings.TrimSpace(winnerName),
-                })
-                obslog.L().Info("pvp_resign_announce",
-                    zap.String("game_id", g.ID),
-                    zap.String("resigner_id", strings.TrimSpace(user)),
-                    zap.String("resigner_name", strings.TrimSpace(resignerName)),
-                    zap.String("winner_id", strings.TrimSpace(g.Winner)),
-                    zap.String("winner_name", strings.TrimSpace(winnerName)),
-                )
-                rooms := fanoutRooms(ctx, pvpChanMgr, g, roomID)
-                // 현재 방 우선 순위 적용
-                rooms = prioritizeRooms(rooms, roomID)
-                obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", "resign"))
-                meta, _, _ := pvpChanMgr.MetaByGame(ctx, g)
-                for i, r := range rooms {
-                    // 방별 뷰어 판별: 생성자 방은 생성자, 상대 방은 상대 참가자
-                    viewer := strings.TrimSpace(g.WhiteID)
-                    if meta != nil {
-                        if strings.TrimSpace(r) == strings.TrimSpace(meta.CreatorRoom) {
-                            viewer = strings.TrimSpace(meta.CreatorID)
-                        } else {
-                            if strings.TrimSpace(meta.CreatorID) == strings.TrimSpace(g.WhiteID) { viewer = strings.TrimSpace(g.BlackID) } else { viewer = strings.TrimSpace(g.WhiteID) }
-                        }
-                    }
-
-                    // 개인화 문구: 기권자(패배), 승자(승리)
-                    var msgText string
-                    if viewer == strings.TrimSpace(user) {
-                        if t, e := catalog.Render("pvp.resign.loser", nil); e == nil { msgText = t }
-                    } else {
-                        if t, e := catalog.Render("pvp.resign.winner", nil); e == nil { msgText = t }
-                    }
-                    if strings.TrimSpace(msgText) == "" {
-                        // 폴백: 공용 안내 또는 고정 문자열
-                        msgText = finishText
-                        if strings.TrimSpace(msgText) == "" {
-                            if tt, ee := catalog.Render("board.send.failed", nil); ee == nil { msgText = tt } else { msgText = "보드 전송 실패" }
-                        }
-                    }
-                    if err := pvpEgress.SendText(ctx, r, msgText); err != nil {
-                        obslog.L().Warn("pvp_resign_send_error", zap.Error(err), zap.String("room_id", r), zap.String("game_id", g.ID))
-                    }
-                    // 방 간 지연 적용(드롭 완화)
-                    if i < len(rooms)-1 {
-                        d := time.Duration(cfg.FanoutImageDelayMS) * time.Millisecond
-                        if d > 0 { time.Sleep(d) }
-                    }
-                }
-                return
-			}
-		}
-		// 2) 레거시 활성 세션이 있으면 레거시 기권
-		if chess != nil {
-			meta := svcchess.SessionMeta{SessionID: sessionIDFor(msg), Room: roomID, Sender: senderName(msg)}
-			if st, err := chess.Status(ctx, meta); err == nil && st != nil {
-				obslog.L().Info("route_decision", zap.String("cmd", "resign"), zap.String("mode", "legacy"), zap.String("room_id", roomID), zap.String("user", strings.TrimSpace(user)))
-				state, rerr := chess.Resign(ctx, meta)
-				if rerr != nil {
-					if txt, e := catalog.Render("resign.failed", map[string]string{"Error": rerr.Error()}); e == nil {
-						_ = defaultEgress.SendText(ctx, roomID, txt)
-					} else {
-						_ = defaultEgress.SendText(ctx, roomID, "기권 실패: "+rerr.Error())
-					}
-					return
-				}
-				_ = presenter.Board(roomID, formatter.Resign(chesspresenterAdaptState(state)), chesspresenterAdaptState(state))
-				return
-			}
-		}
-		obslog.L().Info("route_decision", zap.String("cmd", "resign"), zap.String("mode", "none"), zap.String("room_id", roomID), zap.String("user", strings.TrimSpace(user)))
-		if txt, err := catalog.Render("no.active.game", nil); err == nil {
-			_ = defaultEgress.SendText(ctx, roomID, txt)
-		} else {
-			_ = defaultEgress.SendText(ctx, roomID, "활성 대국이 없습니다.")
-		}
-		return
-	default:
-		// 공통 명령(이동): 세션우선 라우팅
-		ctx := context.Background()
-		roomID := extractRoomID(msg)
-		moveInput := strings.TrimSpace(raw)
-
-		// 1) PvP 이동 시도 (없으면 조용히 패스)
-    if pvpChessMgr != nil {
-            if handlePvPMove(client, cfg, pvpChessMgr, pvpChanMgr, presenter, catalog, msg, moveInput, false) {
-                obslog.L().Info("route_decision", zap.String("cmd", "move"), zap.String("mode", "pvp"), zap.String("room_id", roomID), zap.String("user", strings.TrimSpace(userIDFromMessage(msg))))
-                return
-            }
-    }
-		// 2) 레거시 세션이 활성인지 확인 후 이동
-		if chess != nil {
-			meta := svcchess.SessionMeta{SessionID: sessionIDFor(msg), Room: roomID, Sender: senderName(msg)}
-			if st, err := chess.Status(ctx, meta); err == nil && st != nil {
-				obslog.L().Info("route_decision", zap.String("cmd", "move"), zap.String("mode", "legacy"), zap.String("room_id", roomID), zap.String("user", strings.TrimSpace(userIDFromMessage(msg))))
-				summary, perr := chess.Play(ctx, meta, moveInput)
-				if perr != nil {
-					if txt, e := catalog.Render("move.failed_with_error", map[string]string{"Error": perr.Error()}); e == nil {
-						_ = defaultEgress.SendText(ctx, roomID, txt)
-					} else {
-						_ = defaultEgress.SendText(ctx, roomID, "이동 실패: "+perr.Error())
-					}
-					return
-				}
-				dto := chesspresenterAdaptSummary(summary)
-				_ = presenter.Board(roomID, formatter.Move(dto), dto.State)
-				return
-			}
-		}
-		// 3) 둘 다 없으면 안내(세션 없음)
-		obslog.L().Info("route_decision", zap.String("cmd", "move"), zap.String("mode", "none"), zap.String("room_id", roomID), zap.String("user", strings.TrimSpace(userIDFromMessage(msg))))
-		if txt, err := catalog.Render("no.active.game", nil); err == nil {
-			_ = defaultEgress.SendText(ctx, roomID, txt)
-		} else {
-			_ = defaultEgress.SendText(ctx, roomID, "활성 대국이 없습니다.")
-		}
-		return
-	}
+	})
+	return f
 }
 
-func handlePvPMove(client *irisfast.Client, cfg *appcfg.AppConfig, pvpChessMgr *pvpchess.Manager, pvpChanMgr *pvpchan.Manager, presenter *chesspresenter.Presenter, catalog *msgcat.Catalog, msg *irisfast.Message, moveInput string, strict bool) bool {
-	if pvpChessMgr == nil || pvpChanMgr == nil {
-		return false
-	}
-	ctx := context.Background()
-	roomID := extractRoomID(msg)
-	userID := strings.TrimSpace(userIDFromMessage(msg))
-	if roomID == "" {
-		return strict
-	}
-	if userID == "" {
-		if strict {
-			if txt, e := catalog.Render("user.identify.error", nil); e == nil {
-				_ = defaultEgress.SendText(ctx, roomID, txt)
-			} else {
-				_ = defaultEgress.SendText(ctx, roomID, "사용자 식별 실패")
-			}
-		}
-		return strict
-	}
-	moveInput = strings.TrimSpace(moveInput)
-	if moveInput == "" {
-		if strict {
-			if txt, e := catalog.Render("move.bad_input", nil); e == nil {
-				_ = defaultEgress.SendText(ctx, roomID, txt)
-			} else {
-				_ = defaultEgress.SendText(ctx, roomID, "이동 실패: 잘못된 입력")
-			}
-		}
-		return strict
-	}
-
-	gameInRoom, err := pvpChessMgr.GetActiveGameByUserInRoom(ctx, userID, roomID)
-	if err != nil {
-		obslog.L().Warn("pvp_lookup_error", zap.Error(err), zap.String("user_id", userID), zap.String("room_id", roomID))
-		if txt, e := catalog.Render("move.state.error", nil); e == nil {
-			_ = defaultEgress.SendText(ctx, roomID, txt)
-		} else {
-			_ = defaultEgress.SendText(ctx, roomID, "이동 실패: 대국 상태 조회 오류")
-		}
-		return true
-	}
-	if gameInRoom == nil {
-		if strict {
-			if txt, e := catalog.Render("no.active.game", nil); e == nil {
-				_ = defaultEgress.SendText(ctx, roomID, txt)
-			} else {
-				_ = defaultEgress.SendText(ctx, roomID, "활성 대국이 없습니다.")
-			}
-			return true
-		}
-		return false
-	}
-
-    oldLen := 0
-    if gameInRoom != nil { oldLen = len(gameInRoom.MovesUCI) }
-    game, resultText, playErr := pvpChessMgr.PlayMoveByRoom(ctx, userID, roomID, moveInput)
-    if playErr != nil || game == nil {
-        msgText, _ := catalog.Render("move.failed", nil)
-        if playErr != nil {
-            obslog.L().Warn("pvp_move_error", zap.Error(playErr), zap.String("user_id", userID), zap.String("game_id", gameInRoom.ID))
-            if t, e := catalog.Render("move.failed_with_error", map[string]string{"Error": playErr.Error()}); e == nil {
-                msgText = t
-            } else {
-                msgText = "이동 실패: " + playErr.Error()
-            }
-        }
-        _ = client.SendMessage(ctx, roomID, msgText)
-        return true
-    }
-
-    // 적용 여부 판별: 수가 적용되지 않았다면(차례 아님/불법수/경합) 텍스트만 전송하고 종료
-    if len(game.MovesUCI) <= oldLen {
-        msgText := strings.TrimSpace(resultText)
-        if msgText == "" {
-            if t, e := catalog.Render("move.failed", nil); e == nil { msgText = t } else { msgText = "이동 실패" }
-        }
-        _ = client.SendMessage(ctx, roomID, msgText)
-        return true
-    }
+// resultFollowUps holds the announcements result hooks produced for a game until its end message is sent.
+type resultFollowUps struct {
+	mu      sync.Mutex
+	pending map[string][]func(context.Context)
+}
 
-wDTO, wErr := pvpChessMgr.ToDTOForViewer(ctx, game, game.WhiteID)
-bDTO, bErr := pvpChessMgr.ToDTOForViewer(ctx, game, game.BlackID)
-if wErr != nil || bErr != nil {
-    obslog.L().Warn("pvp_render_error", zap.Error(func() error { if wErr != nil { return wErr }; return bErr }()), zap.String("game_id", game.ID))
-    fallback := strings.TrimSpace(resultText)
-    if fallback == "" {
-        if t, e := catalog.Render("render.board.failed", nil); e == nil { fallback = t } else { fallback = "보드 렌더링 실패" }
-    }
-    _ = defaultEgress.SendText(ctx, roomID, fallback)
-    return true
+func (f *resultFollowUps) add(gameID string, fn func(context.Context)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending[gameID] = append(f.pending[gameID], fn)
 }
 
-	moveText := ""
-	if game.Status == pvpchess.StatusFinished {
-		winner := game.WhiteName
-		if game.Outcome == "black" {
-			winner = game.BlackName
-		}
-		if t, e := catalog.Render("finish.checkmate", map[string]string{"Winner": winner}); e == nil {
-			moveText = t
-		} else {
-			moveText = "✅ 승리했습니다! 축하드립니다."
-		}
-	} else if game.Status == pvpchess.StatusDraw {
-		if t, e := catalog.Render("finish.draw", nil); e == nil {
-			moveText = t
-		} else {
-			moveText = "🤝 무승부로 종료되었습니다."
-		}
+// flush sends the follow-ups queued for gameID in hook order; call it after the game-end message went out.
+func (f *resultFollowUps) flush(ctx context.Context, gameID string) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	fns := f.pending[gameID]
+	delete(f.pending, gameID)
+	f.mu.Unlock()
+	for _, fn := range fns {
+		fn(ctx)
 	}
-
-    rooms := fanoutRooms(ctx, pvpChanMgr, game, roomID)
-    // 현재 방 우선 순위 적용
-    rooms = prioritizeRooms(rooms, roomID)
-    obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", game.ID), zap.String("phase", "move"))
-    meta, _, _ := pvpChanMgr.MetaByGame(ctx, game)
-
-fallback := strings.TrimSpace(resultText)
-    for i, r := range rooms {
-        viewer := game.WhiteID
-        if meta != nil {
-            if strings.TrimSpace(r) == strings.TrimSpace(meta.CreatorRoom) {
-                viewer = strings.TrimSpace(meta.CreatorID)
-            } else {
-                if strings.TrimSpace(meta.CreatorID) == strings.TrimSpace(game.WhiteID) { viewer = game.BlackID } else { viewer = game.WhiteID }
-            }
-        }
-        vdto := wDTO
-        if strings.TrimSpace(viewer) == strings.TrimSpace(game.BlackID) { vdto = bDTO }
-        if err := pvpPresenter.Board(r, moveText, vdto); err != nil {
-            obslog.L().Warn("pvp_board_send_error", zap.Error(err), zap.String("room_id", r), zap.String("game_id", game.ID))
-            txt := moveText
-            if strings.TrimSpace(txt) == "" {
-                txt = fallback
-            }
-            if strings.TrimSpace(txt) == "" {
-                if t, e := catalog.Render("board.send.failed", nil); e == nil {
-                    txt = t
-                } else {
-                    txt = "보드 전송 실패"
-                }
-            }
-            _ = pvpEgress.SendText(ctx, r, txt)
-        }
-        // 방 간 지연 적용(이미지 드롭 완화)
-        if i < len(rooms)-1 {
-            d := time.Duration(cfg.FanoutImageDelayMS) * time.Millisecond
-            if d > 0 { time.Sleep(d) }
-        }
-    }
-	return true
 }
 
 // helpText removed: YAML-only catalog is the single source for help content.
@@ -1148,71 +630,6 @@ func userIDFromMessage(msg *irisfast.Message) string {
 	return ""
 }
 
-// mergeFanoutRooms는 전달받은 방 목록에 게임의 원/해결 방을 합쳐 중복 제거.
-// 이유: 참가자 인덱스가 늦게 동기화되면 한쪽 방만 반환될 수 있음.
-func mergeFanoutRooms(g *pvpchess.Game, rooms []string) []string {
-	set := make(map[string]struct{})
-	add := func(s string) {
-		s = strings.TrimSpace(s)
-		if s == "" {
-			return
-		}
-		set[s] = struct{}{}
-	}
-	for _, r := range rooms {
-		add(r)
-	}
-	if g != nil {
-		add(g.OriginRoom)
-		add(g.ResolveRoom)
-	}
-	out := make([]string, 0, len(set))
-	for r := range set {
-		out = append(out, r)
-	}
-	return out
-}
-
-// fanoutRooms: 채널 저장소/사용자 인덱스/현재 방을 합쳐 안정적 팬아웃 대상 생성
-func fanoutRooms(ctx context.Context, pvpChanMgr *pvpchan.Manager, g *pvpchess.Game, extras ...string) []string {
-    base := extras
-    if pvpChanMgr != nil && g != nil {
-        if meta, code, _ := pvpChanMgr.MetaByGame(ctx, g); meta != nil || code != "" {
-            if rs, _ := pvpChanMgr.Rooms(ctx, code); len(rs) > 0 {
-                base = append(base, rs...)
-            }
-        }
-        if rs, _ := pvpChanMgr.RoomsByUserAndGame(ctx, g.WhiteID, g.ID); len(rs) > 0 {
-            base = append(base, rs...)
-        }
-        if rs, _ := pvpChanMgr.RoomsByUserAndGame(ctx, g.BlackID, g.ID); len(rs) > 0 {
-            base = append(base, rs...)
-        }
-    }
-    return mergeFanoutRooms(g, base)
-}
-
-// prioritizeRooms: 현재 방을 선두로 이동(없으면 추가) — 순회 중 드롭 완화 우선순위 확보
-func prioritizeRooms(rooms []string, current string) []string {
-    cur := strings.TrimSpace(current)
-    if cur == "" {
-        return rooms
-    }
-    seen := make(map[string]struct{})
-    out := make([]string, 0, len(rooms)+1)
-    // 현재 방 먼저
-    out = append(out, cur)
-    seen[cur] = struct{}{}
-    // 나머지 보존 + 중복 제거
-    for _, r := range rooms {
-        rr := strings.TrimSpace(r)
-        if rr == "" { continue }
-        if _, ok := seen[rr]; ok { continue }
-        out = append(out, rr)
-        seen[rr] = struct{}{}
-    }
-    return out
-}
 
 func roomAllowed(allowed []string, msg *irisfast.Message) bool {
 	if msg == nil || msg.JSON == nil {
@@ -1262,193 +679,6 @@ func extractRoomID(msg *irisfast.Message) string {
 	return ""
 }
 
-func handleChessCommand(client *irisfast.Client, cfg *appcfg.AppConfig, chess *svcchess.Service, presenter *chesspresenter.Presenter, formatter *chesspresenter.Formatter, catalog *msgcat.Catalog, msg *irisfast.Message, args []string) {
-	meta := svcchess.SessionMeta{
-		SessionID: sessionIDFor(msg),
-		Room:      extractRoomID(msg),
-		Sender:    senderName(msg),
-	}
-	if len(args) == 0 {
-		_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), formatter.Help())
-		return
-	}
-	sub := strings.ToLower(strings.TrimSpace(args[0]))
-	ctx := context.Background()
-
-	switch sub {
-	case "시작":
-		preset := ""
-		if len(args) >= 2 {
-			preset = args[1]
-		}
-		state, err := chess.StartSession(ctx, meta, preset, false)
-		resumed := false
-		if err != nil {
-			if errorsEqual(err, svcchess.ErrSessionInProgress) {
-				st, sErr := chess.Status(ctx, meta)
-				if sErr == nil {
-					state = st
-					resumed = true
-					err = nil
-				}
-			}
-		}
-		if err != nil {
-			if txt, e := catalog.Render("chess.start.failed", map[string]string{"Error": err.Error()}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "체스 시작 실패: "+err.Error())
-			}
-			return
-		}
-		_ = presenter.Board(extractRoomID(msg), formatter.Start(chesspresenterAdaptState(state), resumed), chesspresenterAdaptState(state))
-	case "현황":
-		state, err := chess.Status(ctx, meta)
-		if err != nil {
-			if txt, e := catalog.Render("chess.status.error", map[string]string{"Error": err.Error()}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "체스 현황 오류: "+err.Error())
-			}
-			return
-		}
-		_ = presenter.Board(extractRoomID(msg), formatter.Status(chesspresenterAdaptState(state)), chesspresenterAdaptState(state))
-	case "무르기":
-		state, err := chess.Undo(ctx, meta)
-		if err != nil {
-			if txt, e := catalog.Render("chess.undo.failed", map[string]string{"Error": err.Error()}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "무르기 실패: "+err.Error())
-			}
-			return
-		}
-		_ = presenter.Board(extractRoomID(msg), formatter.Undo(chesspresenterAdaptState(state)), chesspresenterAdaptState(state))
-	case "기권":
-		state, err := chess.Resign(ctx, meta)
-		if err != nil {
-			if txt, e := catalog.Render("resign.failed", map[string]string{"Error": err.Error()}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "기권 실패: "+err.Error())
-			}
-			return
-		}
-		_ = presenter.Board(extractRoomID(msg), formatter.Resign(chesspresenterAdaptState(state)), chesspresenterAdaptState(state))
-	case "기록":
-		limit := 10
-		if len(args) >= 2 {
-			if n, err := strconv.Atoi(args[1]); err == nil && n > 0 {
-				limit = n
-			}
-		}
-		games, err := chess.History(ctx, meta, limit)
-		if err != nil {
-			if txt, e := catalog.Render("chess.history.error", map[string]string{"Error": err.Error()}); e == nil {
-				_ = client.SendMessage(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = client.SendMessage(context.Background(), extractRoomID(msg), "기록 조회 실패: "+err.Error())
-			}
-			return
-		}
-		_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), formatter.History(chesspresenter.ToDTOGames(games)))
-	case "기보":
-		if len(args) < 2 {
-			if txt, e := catalog.Render("usage.game", map[string]string{"Prefix": cfg.BotPrefix}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "용법: "+cfg.BotPrefix+" 기보 <ID>")
-			}
-			return
-		}
-		id, err := strconv.ParseInt(args[1], 10, 64)
-		if err != nil {
-			if txt, e := catalog.Render("game.id.invalid", nil); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "잘못된 ID")
-			}
-			return
-		}
-		game, err := chess.Game(ctx, meta, id)
-		if err != nil {
-			if txt, e := catalog.Render("game.fetch.error", map[string]string{"Error": err.Error()}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "기보 조회 실패: "+err.Error())
-			}
-			return
-		}
-		_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), formatter.Game(chesspresenter.ToDTOGame(game)))
-	case "프로필":
-		profile, err := chess.Profile(ctx, meta)
-		if err != nil {
-			if txt, e := catalog.Render("chess.profile.error", map[string]string{"Error": err.Error()}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "프로필 조회 실패: "+err.Error())
-			}
-			return
-		}
-		_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), formatter.Profile(chesspresenterAdaptProfile(profile)))
-	case "선호":
-		if len(args) < 2 {
-			if txt, e := catalog.Render("usage.preset", map[string]string{"Prefix": cfg.BotPrefix}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "용법: "+cfg.BotPrefix+" 선호 <preset>")
-			}
-			return
-		}
-		profile, err := chess.UpdatePreferredPreset(ctx, meta, args[1])
-		if err != nil {
-			if txt, e := catalog.Render("chess.preset.update.failed", map[string]string{"Error": err.Error()}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "선호 난이도 업데이트 실패: "+err.Error())
-			}
-			return
-		}
-		_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), formatter.PreferredPresetUpdated(chesspresenterAdaptProfile(profile)))
-	case "도움":
-		suggestion, err := chess.Assist(ctx, meta)
-		if err != nil {
-			if txt, e := catalog.Render("chess.assist.failed", map[string]string{"Error": err.Error()}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "추천 수 계산 실패: "+err.Error())
-			}
-			return
-		}
-		_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), formatter.Assist(chesspresenterAdaptAssist(suggestion)))
-	default:
-		summary, err := chess.Play(ctx, meta, sub)
-		if err != nil {
-			if txt, e := catalog.Render("move.failed_with_error", map[string]string{"Error": err.Error()}); e == nil {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), txt)
-			} else {
-				_ = defaultEgress.SendText(context.Background(), extractRoomID(msg), "이동 실패: "+err.Error())
-			}
-			return
-		}
-		dto := chesspresenterAdaptSummary(summary)
-		text := formatter.Move(dto)
-		_ = presenter.Board(extractRoomID(msg), text, dto.State)
-	}
-}
-
-func chesspresenterAdaptState(s *svcchess.SessionState) *chessdto.SessionState {
-	return chesspresenter.ToDTOState(s)
-}
-func chesspresenterAdaptSummary(m *svcchess.MoveSummary) *chessdto.MoveSummary {
-	return chesspresenter.ToDTOMoveSummary(m)
-}
-func chesspresenterAdaptProfile(p *domain.ChessProfile) *chessdto.ChessProfile {
-	return chesspresenter.ToDTOProfile(p)
-}
-func chesspresenterAdaptAssist(a *svcchess.AssistSuggestion) *chessdto.AssistSuggestion {
-	return chesspresenter.ToDTOAssist(a)
-}
 
 func sessionIDFor(msg *irisfast.Message) string {
 	uid := userIDFromMessage(msg)
@@ -1543,3 +773,5 @@ func isIgnoredSender(cfg *appcfg.AppConfig, name string) bool {
 	}
 	return false
 }
+
+
@@ -0,0 +1,843 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/command"
+	appcfg "github.com/park285/Cheese-KakaoTalk-bot/internal/config"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchan"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	"go.uber.org/zap"
+)
+
+var errNoActiveGame = command.Reject("no.active.game", nil, "활성 대국이 없습니다.")
+
+// status shows the board of the user's game. 세션우선 라우팅: PvP → 레거시 → 없음
+func (b *bot) status(c *command.Context) error {
+	// 1) PvP 대국 우선 (`#N` 또는 ResolveGame으로 고른 이 방 대국)
+	g, err := b.pvpGame(c)
+	if err != nil {
+		return err
+	}
+	if g != nil {
+		logRoute(c, "status", "pvp")
+		rooms := prioritizeRooms(fanoutRooms(c.Ctx, b.chans, g, c.Room), c.Room)
+		obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", "status"))
+		sendPvPBoards(c.Ctx, b.pvp, b.chans, b.catalog, g, rooms, "", "status")
+		return nil
+	}
+	// 2) 레거시 활성 세션이 있으면 레거시 현황
+	if st := b.soloSession(c); st != nil {
+		logRoute(c, "status", "legacy")
+		return c.Board(b.formatter.Status(chesspresenterAdaptState(st)), chesspresenterAdaptState(st))
+	}
+	// 3) 둘 다 없으면 안내
+	logRoute(c, "status", "none")
+	return errNoActiveGame
+}
+
+// resign resigns the user's game. 세션우선 라우팅: PvP → 레거시 → 없음
+func (b *bot) resign(c *command.Context) error {
+	// 1) PvP 대국이 있으면 PvP 기권
+	target, err := b.pvpGame(c)
+	if err != nil {
+		return err
+	}
+	if target != nil {
+		logRoute(c, "resign", "pvp")
+		g, _, err := b.pvp.ResignInGame(c.Ctx, c.UserID, target.ID)
+		if err != nil || g == nil {
+			// 최종 상태 재조회: 이미 종료되었으면 개인화 안내만 전송
+			final, _ := b.pvp.LoadGame(c.Ctx, target.ID)
+			if final == nil || final.Status == pvpchess.StatusActive {
+				// 여전히 ACTIVE → 실패 안내
+				return command.Reject("resign.process.error", nil, "기권 처리 실패")
+			}
+			g = final
+		}
+		b.announceResign(c, g)
+		b.results.flush(c.Ctx, g.ID)
+		return nil
+	}
+	// 2) 레거시 활성 세션이 있으면 레거시 기권
+	if st := b.soloSession(c); st != nil {
+		logRoute(c, "resign", "legacy")
+		state, err := b.chess.Resign(c.Ctx, b.soloMeta(c))
+		if err != nil {
+			return command.Reject("resign.failed", map[string]string{"Error": err.Error()}, "기권 실패: "+err.Error())
+		}
+		return c.Board(b.formatter.Resign(chesspresenterAdaptState(state)), chesspresenterAdaptState(state))
+	}
+	logRoute(c, "resign", "none")
+	return errNoActiveGame
+}
+
+// pvpGame picks the PvP game a per-game command (현황/기권/무승부/무르기) applies to: `#N` 접두(c.Target)가
+// 있으면 그 대국, 없으면 ResolveGame 규칙으로 고른 이 방의 대국. 고를 수 없으면 안내 Notice를, 이 방에
+// 대국이 없으면 (nil, nil)을 반환합니다.
+func (b *bot) pvpGame(c *command.Context) (*pvpchess.Game, error) {
+	handle := 0
+	if c.Target != "" {
+		n, ok := gameHandleArg(c.Target)
+		if !ok {
+			return nil, command.RejectText(gameSelectText(c.Ctx, b.cfg, b.pvp, b.catalog, c.UserID, c.Room, 0, pvpchess.ErrNoSuchGame))
+		}
+		handle = n
+	}
+	if c.UserID == "" {
+		if handle > 0 {
+			return nil, command.Reject("user.identify.error", nil, "사용자 식별 실패")
+		}
+		return nil, nil
+	}
+	g, err := b.pvp.ResolveGame(c.Ctx, c.UserID, c.Room, handle)
+	if errors.Is(err, pvpchess.ErrAmbiguousGame) || errors.Is(err, pvpchess.ErrNoSuchGame) {
+		return nil, command.RejectText(gameSelectText(c.Ctx, b.cfg, b.pvp, b.catalog, c.UserID, c.Room, handle, err))
+	}
+	if err != nil {
+		obslog.L().Warn("pvp_lookup_error", zap.Error(err), zap.String("user_id", c.UserID), zap.String("room_id", c.Room))
+		return nil, command.Reject("move.state.error", nil, "이동 실패: 대국 상태 조회 오류")
+	}
+	return g, nil
+}
+
+// announceResign sends the resign result to every fanout room (YAML-only, 이미지 없이 텍스트만).
+// 기권자 방은 패배, 상대 방은 승리, 관전 방은 공용 안내를 받습니다.
+func (b *bot) announceResign(c *command.Context, g *pvpchess.Game) {
+	resignerName := pvpPlayerName(g, c.UserID)
+	if resignerName == "" {
+		resignerName = strings.TrimSpace(c.Sender)
+	}
+	winnerName := pvpPlayerName(g, g.Winner)
+	finishText, _ := b.catalog.Render("pvp.resign.announce", map[string]string{
+		"ResignerName": resignerName,
+		"WinnerName":   winnerName,
+	})
+	obslog.L().Info("pvp_resign_announce",
+		zap.String("game_id", g.ID),
+		zap.String("resigner_id", c.UserID),
+		zap.String("resigner_name", resignerName),
+		zap.String("winner_id", strings.TrimSpace(g.Winner)),
+		zap.String("winner_name", winnerName),
+	)
+	rooms := prioritizeRooms(fanoutRooms(c.Ctx, b.chans, g, c.Room), c.Room)
+	obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", "resign"))
+	meta, _, _ := b.chans.MetaByGame(c.Ctx, g)
+	for _, r := range rooms {
+		var text string
+		switch viewer := pvpRoomViewer(meta, g, r); viewer {
+		case "":
+			text = finishText
+		case c.UserID:
+			text, _ = b.catalog.Render("pvp.resign.loser", nil)
+		default:
+			text, _ = b.catalog.Render("pvp.resign.winner", nil)
+		}
+		if strings.TrimSpace(text) == "" {
+			// 폴백: 공용 안내 또는 고정 문자열
+			text = finishText
+			if strings.TrimSpace(text) == "" {
+				if t, e := b.catalog.Render("board.send.failed", nil); e == nil {
+					text = t
+				} else {
+					text = "보드 전송 실패"
+				}
+			}
+		}
+		text = withPvPRatingLine(b.catalog, g, text)
+		if err := pvpEgress.SendText(c.Ctx, r, text); err != nil {
+			obslog.L().Warn("pvp_resign_send_error", zap.Error(err), zap.String("room_id", r), zap.String("game_id", g.ID))
+		}
+	}
+}
+
+// takeback handles 무르기. 세션우선 라우팅: PvP(상대 동의 필요) → 레거시(즉시 무르기) → 없음
+func (b *bot) takeback(c *command.Context) error {
+	g, err := b.pvpGame(c)
+	if err != nil {
+		return err
+	}
+	if g != nil {
+		return b.pvpTakeback(c, g, c.Parsed.(string))
+	}
+	if st := b.soloSession(c); st != nil {
+		logRoute(c, "undo", "legacy")
+		state, err := b.chess.Undo(c.Ctx, b.soloMeta(c))
+		if err != nil {
+			return command.Reject("chess.undo.failed", map[string]string{"Error": err.Error()}, "무르기 실패: "+err.Error())
+		}
+		return c.Board(b.formatter.Undo(chesspresenterAdaptState(state)), chesspresenterAdaptState(state))
+	}
+	logRoute(c, "undo", "none")
+	return errNoActiveGame
+}
+
+// pvpTakeback processes takeback request/accept/decline for the user's PvP game target (pvpGame으로 고른 대국).
+// 수락 시 되돌린 보드를 팬아웃 대상 모든 방에 다시 전송합니다.
+func (b *bot) pvpTakeback(c *command.Context, target *pvpchess.Game, action string) error {
+	var (
+		g     *pvpchess.Game
+		err   error
+		plies int
+	)
+	switch action {
+	case "accept":
+		g, plies, err = b.pvp.AcceptTakebackInGame(c.Ctx, c.UserID, target.ID)
+	case "decline":
+		g, err = b.pvp.DeclineTakebackInGame(c.Ctx, c.UserID, target.ID)
+	default:
+		g, err = b.pvp.RequestTakebackInGame(c.Ctx, c.UserID, target.ID)
+	}
+	logRoute(c, "takeback_"+action, "pvp")
+	if err != nil {
+		key := ""
+		switch {
+		case errors.Is(err, pvpchess.ErrTakebackPending):
+			key = "pvp.takeback.pending"
+		case errors.Is(err, pvpchess.ErrNoTakeback):
+			key = "pvp.takeback.none"
+		case errors.Is(err, pvpchess.ErrOwnTakeback):
+			key = "pvp.takeback.own"
+		case errors.Is(err, pvpchess.ErrNothingToUndo):
+			key = "pvp.takeback.empty"
+		}
+		fallback := "무르기 처리 실패: " + err.Error()
+		if key != "" {
+			return command.Reject(key, nil, fallback)
+		}
+		obslog.L().Warn("pvp_takeback_error", zap.Error(err), zap.String("user_id", c.UserID), zap.String("room_id", c.Room), zap.String("action", action))
+		return command.Reject("pvp.takeback.failed", map[string]string{"Error": err.Error()}, fallback)
+	}
+	if g == nil {
+		return errNoActiveGame
+	}
+	if action == "offer" && g.Status == pvpchess.StatusDraw {
+		// 상대 제안에 대한 맞제안은 합의 무승부
+		action = "accept"
+	}
+
+	name := pvpPlayerName(g, c.UserID)
+	if name == "" {
+		name = c.Sender
+	}
+	rooms := prioritizeRooms(fanoutRooms(c.Ctx, b.chans, g, c.Room), c.Room)
+	obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", "takeback_"+action))
+	switch action {
+	case "accept":
+		text, e := b.catalog.Render("pvp.takeback.accepted", map[string]string{"Name": name, "Plies": strconv.Itoa(plies)})
+		if e != nil {
+			text = "↩️ 무르기가 승인되었습니다."
+		}
+		sendPvPBoards(c.Ctx, b.pvp, b.chans, b.catalog, g, rooms, text, "takeback")
+	case "decline":
+		text, e := b.catalog.Render("pvp.takeback.declined", map[string]string{"Name": name})
+		if e != nil {
+			text = name + " 님이 무르기 요청을 거절했습니다."
+		}
+		sendPvPText(c.Ctx, rooms, text, g.ID, "takeback_decline")
+	default:
+		text, e := b.catalog.Render("pvp.takeback.request", map[string]string{"Name": name, "Prefix": b.cfg.BotPrefix})
+		if e != nil {
+			text = name + " 님이 무르기를 요청했습니다."
+		}
+		sendPvPText(c.Ctx, rooms, text, g.ID, "takeback_request")
+	}
+	return nil
+}
+
+// draw processes draw offer/accept/decline for the user's PvP game (`#N` 또는 이 방의 대국, 싱글 모드에는 해당 없음).
+// 결과 안내는 팬아웃 대상 모든 방에 텍스트로 전송합니다.
+func (b *bot) draw(c *command.Context) error {
+	action := c.Parsed.(string)
+	target, err := b.pvpGame(c)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		logRoute(c, "draw_"+action, "none")
+		return errNoActiveGame
+	}
+	var g *pvpchess.Game
+	switch action {
+	case "accept":
+		g, err = b.pvp.AcceptDrawInGame(c.Ctx, c.UserID, target.ID)
+	case "decline":
+		g, err = b.pvp.DeclineDrawInGame(c.Ctx, c.UserID, target.ID)
+	default:
+		g, err = b.pvp.OfferDrawInGame(c.Ctx, c.UserID, target.ID)
+	}
+	logRoute(c, "draw_"+action, "pvp")
+	if err != nil {
+		key := ""
+		switch {
+		case errors.Is(err, pvpchess.ErrDrawOfferPending):
+			key = "pvp.draw.pending"
+		case errors.Is(err, pvpchess.ErrNoDrawOffer):
+			key = "pvp.draw.none"
+		case errors.Is(err, pvpchess.ErrOwnDrawOffer):
+			key = "pvp.draw.own"
+		}
+		fallback := "무승부 처리 실패: " + err.Error()
+		if key != "" {
+			return command.Reject(key, nil, fallback)
+		}
+		obslog.L().Warn("pvp_draw_error", zap.Error(err), zap.String("user_id", c.UserID), zap.String("room_id", c.Room), zap.String("action", action))
+		return command.Reject("pvp.draw.failed", map[string]string{"Error": err.Error()}, fallback)
+	}
+	if g == nil {
+		return errNoActiveGame
+	}
+	if action == "offer" && g.Status == pvpchess.StatusDraw {
+		// 상대 제안에 대한 맞제안은 합의 무승부
+		action = "accept"
+	}
+
+	name := pvpPlayerName(g, c.UserID)
+	if name == "" {
+		name = c.Sender
+	}
+	var text string
+	switch action {
+	case "accept":
+		if t, e := b.catalog.Render("finish.agreement", nil); e == nil {
+			text = t
+		} else {
+			text = "🤝 합의에 의해 무승부로 종료되었습니다."
+		}
+		text = withPvPRatingLine(b.catalog, g, text)
+	case "decline":
+		if t, e := b.catalog.Render("pvp.draw.declined", map[string]string{"Name": name}); e == nil {
+			text = t
+		} else {
+			text = name + " 님이 무승부 제안을 거절했습니다."
+		}
+	default:
+		if t, e := b.catalog.Render("pvp.draw.offer", map[string]string{"Name": name, "Prefix": b.cfg.BotPrefix}); e == nil {
+			text = t
+		} else {
+			text = name + " 님이 무승부를 제안했습니다."
+		}
+	}
+	rooms := prioritizeRooms(fanoutRooms(c.Ctx, b.chans, g, c.Room), c.Room)
+	obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", "draw_"+action))
+	sendPvPText(c.Ctx, rooms, text, g.ID, "draw_"+action)
+	b.results.flush(c.Ctx, g.ID)
+	return nil
+}
+
+// move is the registry fallback: 명령이 아닌 입력은 수로 취급합니다. 세션우선 라우팅
+func (b *bot) move(c *command.Context) error {
+	// 0) `#N <수>`: 번호로 지정한 PvP 대국에 수 두기 (다른 방 대국도 가능)
+	if handle, ok := gameHandleArg(c.Name); ok {
+		logRoute(c, "move", "pvp_handle", zap.Int("handle", handle))
+		_, err := b.pvpMove(c, strings.Join(c.Args, " "), handle, true)
+		return err
+	}
+	// 1) PvP 이동 시도 (없으면 조용히 패스)
+	if handled, err := b.pvpMove(c, c.Raw, 0, false); handled {
+		logRoute(c, "move", "pvp")
+		return err
+	}
+	// 2) 레거시 세션이 활성인지 확인 후 이동
+	if st := b.soloSession(c); st != nil {
+		logRoute(c, "move", "legacy")
+		summary, err := b.chess.Play(c.Ctx, b.soloMeta(c), c.Raw)
+		if err != nil {
+			return command.Reject("move.failed_with_error", map[string]string{"Error": err.Error()}, "이동 실패: "+err.Error())
+		}
+		dto := chesspresenterAdaptSummary(summary)
+		return c.Board(b.formatter.Move(dto), dto.State)
+	}
+	// 3) 관전 방에서는 수 입력 거부(읽기 전용)
+	if sg, _ := b.chans.SpectatedGame(c.Ctx, c.Room); sg != nil {
+		logRoute(c, "move", "spectator")
+		return command.Reject("pvp.spectate.readonly", nil, "관전 중인 방에서는 수를 둘 수 없습니다.")
+	}
+	// 4) 둘 다 없으면 안내(세션 없음)
+	logRoute(c, "move", "none")
+	return errNoActiveGame
+}
+
+// pvpMove plays moveInput in the user's PvP game (handle > 0: `#N`으로 지정) and fans out the board.
+// handled가 false면 대상 대국이 없어 다음 라우팅으로 넘깁니다; strict이면 항상 처리(안내)합니다.
+func (b *bot) pvpMove(c *command.Context, moveInput string, handle int, strict bool) (handled bool, err error) {
+	if c.Room == "" {
+		return strict, nil
+	}
+	if c.UserID == "" {
+		if strict {
+			return true, command.Reject("user.identify.error", nil, "사용자 식별 실패")
+		}
+		return false, nil
+	}
+	moveInput = strings.TrimSpace(moveInput)
+	if moveInput == "" {
+		if strict {
+			return true, command.Reject("move.bad_input", nil, "이동 실패: 잘못된 입력")
+		}
+		return false, nil
+	}
+
+	inRoom, err := b.pvp.ResolveGame(c.Ctx, c.UserID, c.Room, handle)
+	if errors.Is(err, pvpchess.ErrAmbiguousGame) || errors.Is(err, pvpchess.ErrNoSuchGame) {
+		return true, command.RejectText(gameSelectText(c.Ctx, b.cfg, b.pvp, b.catalog, c.UserID, c.Room, handle, err))
+	}
+	if err != nil {
+		obslog.L().Warn("pvp_lookup_error", zap.Error(err), zap.String("user_id", c.UserID), zap.String("room_id", c.Room))
+		return true, command.Reject("move.state.error", nil, "이동 실패: 대국 상태 조회 오류")
+	}
+	if inRoom == nil {
+		if strict {
+			return true, errNoActiveGame
+		}
+		return false, nil
+	}
+
+	oldLen := len(inRoom.MovesUCI)
+	game, resultText, playErr := b.pvp.PlayMoveInGame(c.Ctx, c.UserID, inRoom.ID, moveInput)
+	if playErr != nil {
+		obslog.L().Warn("pvp_move_error", zap.Error(playErr), zap.String("user_id", c.UserID), zap.String("game_id", inRoom.ID))
+		return true, command.Reject("move.failed_with_error", map[string]string{"Error": playErr.Error()}, "이동 실패: "+playErr.Error())
+	}
+	if game == nil {
+		return true, command.Reject("move.failed", nil, "")
+	}
+	// 적용 여부 판별: 수가 적용되지 않았다면(차례 아님/불법수/경합) 텍스트만 전송하고 종료
+	if len(game.MovesUCI) <= oldLen {
+		if txt := strings.TrimSpace(resultText); txt != "" {
+			return true, command.RejectText(txt)
+		}
+		return true, command.Reject("move.failed", nil, "이동 실패")
+	}
+
+	moveText := ""
+	switch game.Status {
+	case pvpchess.StatusFinished:
+		winner := game.WhiteName
+		if game.Outcome == "black" {
+			winner = game.BlackName
+		}
+		if t, e := b.catalog.Render("finish.checkmate", map[string]string{"Winner": winner}); e == nil {
+			moveText = t
+		} else {
+			moveText = "✅ 승리했습니다! 축하드립니다."
+		}
+	case pvpchess.StatusDraw:
+		if t, e := b.catalog.Render("finish.draw", nil); e == nil {
+			moveText = t
+		} else {
+			moveText = "🤝 무승부로 종료되었습니다."
+		}
+	}
+	moveText = withPvPRatingLine(b.catalog, game, moveText)
+
+	rooms := prioritizeRooms(fanoutRooms(c.Ctx, b.chans, game, c.Room), c.Room)
+	obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", game.ID), zap.String("phase", "move"))
+	// 통신 대국: 다음 차례 플레이어의 방에는 "당신 차례" 알림을 보드와 함께 전송
+	turnRoom, turnText := correspondenceTurnNotice(b.catalog, b.cfg, game)
+	sendPvPRoomBoards(c.Ctx, b.pvp, b.chans, b.catalog, game, rooms, func(r string) string {
+		if turnRoom != "" && r == turnRoom {
+			return turnText
+		}
+		return moveText
+	}, "move")
+	b.results.flush(c.Ctx, game.ID)
+	return true, nil
+}
+
+// spectate subscribes the current room to a live channel and sends it the current board.
+func (b *bot) spectate(c *command.Context) error {
+	code := c.Arg(0)
+	meta, err := b.chans.Spectate(c.Ctx, c.Room, code, b.cfg.PvpMaxSpectatorRooms)
+	logRoute(c, "spectate", "pvp", zap.String("code", code))
+	if err != nil {
+		fallback := "관전 실패: " + err.Error()
+		switch {
+		case errors.Is(err, pvpchan.ErrChannelGone), errors.Is(err, pvpchan.ErrNotInProgress):
+			return command.Reject("pvp.spectate.not_in_progress", nil, fallback)
+		case errors.Is(err, pvpchan.ErrSpectatorLimit):
+			return command.Reject("pvp.spectate.limit", map[string]string{"Limit": strconv.Itoa(b.cfg.PvpMaxSpectatorRooms)}, fallback)
+		case errors.Is(err, pvpchan.ErrRoomIsPlayerRoom):
+			return command.Reject("pvp.spectate.player_room", nil, fallback)
+		case errors.Is(err, pvpchan.ErrAlreadySpectating):
+			return command.Reject("pvp.spectate.already", nil, fallback)
+		}
+		return command.Reject("pvp.spectate.failed", map[string]string{"Error": err.Error()}, fallback)
+	}
+	g, _ := b.pvp.LoadGame(c.Ctx, meta.GameID)
+	if g == nil {
+		return command.Reject("game.not_found", nil, "")
+	}
+	text, e := b.catalog.Render("pvp.spectate.start", map[string]string{"Code": meta.ID, "WhiteName": g.WhiteName, "BlackName": g.BlackName, "Prefix": b.cfg.BotPrefix})
+	if e != nil {
+		text = "👀 관전을 시작합니다: " + g.WhiteName + " vs " + g.BlackName
+	}
+	// 관전 방에만 현재 보드 전송
+	sendPvPBoards(c.Ctx, b.pvp, b.chans, b.catalog, g, []string{c.Room}, text, "spectate")
+	return nil
+}
+
+// unspectate removes the current room from the game it is spectating.
+func (b *bot) unspectate(c *command.Context) error {
+	code, err := b.chans.Unspectate(c.Ctx, c.Room)
+	logRoute(c, "unspectate", "pvp", zap.String("code", code))
+	switch {
+	case err == nil:
+		return c.ReplyKey("pvp.spectate.stop", map[string]string{"Code": code}, "관전 종료 처리 실패")
+	case errors.Is(err, pvpchan.ErrNotSpectating):
+		return command.Reject("pvp.spectate.none", nil, "관전 종료 처리 실패")
+	}
+	return command.Reject("pvp.spectate.failed", map[string]string{"Error": err.Error()}, "관전 종료 처리 실패")
+}
+
+// challengeAction maps `도전 수락|거절|취소` to accept/decline/cancel; 그 밖의 인자는 신청 대상(create)입니다.
+func challengeAction(c *command.Context) (any, error) {
+	switch c.Arg(0) {
+	case "수락":
+		return "accept", nil
+	case "거절":
+		return "decline", nil
+	case "취소":
+		return "cancel", nil
+	}
+	return "create", nil
+}
+
+// challenge processes `도전 @이름` and the target's 수락/거절 or the challenger's 취소 in the current room.
+// 수락 시 현재 방에서 대국이 시작되며 시작 안내와 보드를 팬아웃합니다.
+func (b *bot) challenge(c *command.Context) error {
+	action := c.Parsed.(string)
+	// 신청/수락 전 싱글 대국 진행 여부 확인 (방 생성/참가와 동일 정책)
+	if action == "create" || action == "accept" {
+		if err := b.requireNoSoloSession(c); err != nil {
+			return err
+		}
+	}
+	logRoute(c, "challenge_"+action, "pvp")
+
+	var (
+		ch  *pvpchan.Challenge
+		jr  *pvpchan.JoinResult
+		err error
+	)
+	switch action {
+	case "accept":
+		jr, err = b.chans.AcceptChallenge(c.Ctx, c.Room, c.UserID, c.Sender)
+	case "decline":
+		ch, err = b.chans.DeclineChallenge(c.Ctx, c.Room, c.UserID)
+	case "cancel":
+		ch, err = b.chans.CancelChallenge(c.Ctx, c.UserID)
+	default:
+		ttl := time.Duration(b.cfg.PvpChallengeTTLSec) * time.Second
+		ch, err = b.chans.Challenge(c.Ctx, c.Room, c.UserID, c.Sender, strings.Join(c.Args, " "), ttl)
+	}
+	if err != nil {
+		fallback := "대국 신청 처리 실패: " + err.Error()
+		switch {
+		case errors.Is(err, pvpchan.ErrChallengePending):
+			return command.Reject("pvp.challenge.pending", nil, fallback)
+		case errors.Is(err, pvpchan.ErrNoChallenge):
+			return command.Reject("pvp.challenge.none", nil, fallback)
+		case errors.Is(err, pvpchan.ErrSelfChallenge):
+			return command.Reject("pvp.challenge.self", nil, fallback)
+		case errors.Is(err, pvpchan.ErrUnknownTarget):
+			target := strings.TrimPrefix(strings.TrimSpace(strings.Join(c.Args, " ")), "@")
+			return command.Reject("pvp.challenge.unknown", map[string]string{"TargetName": target}, fallback)
+		case errors.Is(err, pvpchan.ErrPlayerBusy):
+			return command.RejectText(gameLimitText(b.cfg, b.catalog))
+		}
+		obslog.L().Warn("pvp_challenge_error", zap.Error(err), zap.String("user_id", c.UserID), zap.String("room_id", c.Room), zap.String("action", action))
+		return command.Reject("pvp.challenge.failed", map[string]string{"Error": err.Error()}, fallback)
+	}
+
+	var (
+		text string
+		e    error
+	)
+	switch action {
+	case "accept":
+		g, _ := b.pvp.LoadGame(c.Ctx, jr.GameID)
+		if g == nil {
+			return command.Reject("game.not_found", nil, "")
+		}
+		b.announceStart(c, g, "", "start")
+		return nil
+	case "decline":
+		text, e = b.catalog.Render("pvp.challenge.declined", map[string]string{"ChallengerName": ch.ChallengerName, "TargetName": ch.TargetName})
+		if e != nil {
+			text = ch.TargetName + " 님이 대국 신청을 거절했습니다."
+		}
+	case "cancel":
+		text, e = b.catalog.Render("pvp.challenge.cancelled", map[string]string{"TargetName": ch.TargetName})
+		if e != nil {
+			text = "대국 신청을 취소했습니다."
+		}
+	default:
+		minutes := (b.cfg.PvpChallengeTTLSec + 59) / 60
+		text, e = b.catalog.Render("pvp.challenge.sent", map[string]string{"ChallengerName": ch.ChallengerName, "TargetName": ch.TargetName, "Minutes": strconv.Itoa(minutes), "Prefix": b.cfg.BotPrefix})
+		if e != nil {
+			text = ch.ChallengerName + " 님이 " + ch.TargetName + " 님에게 대국을 신청했습니다."
+		}
+	}
+	// 신청/거절/취소는 신청이 있는 방(현재 방)에만 안내
+	return c.Out.Egress.SendText(c.Ctx, ch.Room, text)
+}
+
+// announceStart fans out the start announcement (head가 있으면 그 아래에) and the board of a new game.
+func (b *bot) announceStart(c *command.Context, g *pvpchess.Game, head, phase string) {
+	text, e := b.catalog.Render("pvp.start.announce", map[string]string{"WhiteName": g.WhiteName, "BlackName": g.BlackName})
+	if e != nil {
+		text = "♟️ 대국 시작 — " + g.WhiteName + " vs " + g.BlackName
+	}
+	if head != "" {
+		text = head + "\n" + text
+	}
+	rooms := prioritizeRooms(fanoutRooms(c.Ctx, b.chans, g, c.Room), c.Room)
+	obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", phase))
+	sendPvPBoards(c.Ctx, b.pvp, b.chans, b.catalog, g, rooms, text, phase)
+}
+
+// match enqueues the user for random matching. 매칭되면 양쪽 방에 시작 안내와 보드를 팬아웃합니다.
+func (b *bot) match(c *command.Context) error {
+	rating := pvpchess.DefaultRating
+	if r, err := b.pvp.Rating(c.Ctx, c.UserID); err == nil {
+		rating = r.Rating
+	} else {
+		obslog.L().Warn("pvp_rating_lookup_error", zap.Error(err), zap.String("user_id", c.UserID))
+	}
+	res, err := b.chans.EnqueueMatch(c.Ctx, c.Room, c.UserID, c.Sender, rating, b.cfg.PvpMatchRatingWindow)
+	logRoute(c, "match", "pvp")
+	if err != nil {
+		fallback := "매칭 처리 실패: " + err.Error()
+		switch {
+		case errors.Is(err, pvpchan.ErrAlreadyQueued):
+			return command.Reject("pvp.match.already", map[string]string{"Prefix": b.cfg.BotPrefix}, fallback)
+		case errors.Is(err, pvpchan.ErrPlayerBusy):
+			return command.RejectText(gameLimitText(b.cfg, b.catalog))
+		case errors.Is(err, pvpchess.ErrTooManyGames):
+			return command.Reject("pvp.capacity", nil, fallback)
+		}
+		obslog.L().Warn("pvp_match_error", zap.Error(err), zap.String("user_id", c.UserID), zap.String("room_id", c.Room))
+		return command.Reject("pvp.match.failed", map[string]string{"Error": err.Error()}, fallback)
+	}
+	if !res.Matched {
+		return c.ReplyKey("pvp.match.queued", map[string]string{"Waiting": strconv.FormatInt(res.Waiting, 10), "Prefix": b.cfg.BotPrefix}, "매칭 대기열에 등록했습니다.")
+	}
+	g, _ := b.pvp.LoadGame(c.Ctx, res.GameID)
+	if g == nil {
+		return command.Reject("game.not_found", nil, "")
+	}
+	b.announceStart(c, g, "", "start")
+	return nil
+}
+
+// matchLeave removes the user from the random-match queue (`매칭 취소`).
+func (b *bot) matchLeave(c *command.Context) error {
+	err := b.chans.LeaveMatch(c.Ctx, c.UserID)
+	logRoute(c, "match_leave", "pvp")
+	switch {
+	case err == nil:
+		return c.ReplyKey("pvp.match.left", nil, "매칭 취소 처리 실패")
+	case errors.Is(err, pvpchan.ErrNotQueued):
+		return command.Reject("pvp.match.none", nil, "매칭 취소 처리 실패")
+	}
+	return command.Reject("pvp.match.failed", map[string]string{"Error": err.Error()}, "매칭 취소 처리 실패")
+}
+
+// ranking shows the PvP rating ladders: 현재 방 + 전체
+func (b *bot) ranking(c *command.Context) error {
+	roomTop, err := b.pvp.TopRatings(c.Ctx, c.Room, 10)
+	var globalTop []pvpchess.RatingEntry
+	if err == nil {
+		globalTop, err = b.pvp.TopRatings(c.Ctx, "", 10)
+	}
+	if err != nil {
+		obslog.L().Warn("pvp_ranking_error", zap.Error(err), zap.String("room_id", c.Room))
+		return command.Reject("pvp.ranking.error", map[string]string{"Error": err.Error()}, "랭킹 조회 실패: "+err.Error())
+	}
+	return c.Reply(formatPvPRanking(b.catalog, roomTop, globalTop))
+}
+
+// myGames lists every ACTIVE PvP game of the user with its #N handle (`내대국`).
+func (b *bot) myGames(c *command.Context) error {
+	games, err := b.pvp.ActiveGamesByUser(c.Ctx, c.UserID)
+	logRoute(c, "my_games", "pvp", zap.Int("games", len(games)))
+	if err != nil {
+		obslog.L().Warn("pvp_game_list_error", zap.Error(err), zap.String("user_id", c.UserID))
+		return command.Reject("move.state.error", nil, "")
+	}
+	if len(games) == 0 {
+		return c.ReplyKey("pvp.games.none", map[string]string{"Prefix": b.cfg.BotPrefix}, "진행 중인 대국이 없습니다.")
+	}
+	head, e := b.catalog.Render("pvp.games.header", map[string]string{"Count": strconv.Itoa(len(games)), "Prefix": b.cfg.BotPrefix})
+	if e != nil {
+		head = "진행 중인 대국 " + strconv.Itoa(len(games)) + "개"
+	}
+	lines := []string{head}
+	def := b.pvp.DefaultGameID(c.Ctx, c.UserID, c.Room)
+	now := time.Now()
+	for _, g := range games {
+		color, opp := pvpchess.White, g.BlackID
+		if g.BlackID == c.UserID {
+			color, opp = pvpchess.Black, g.WhiteID
+		}
+		colorLabel := "백"
+		if color == pvpchess.Black {
+			colorLabel = "흑"
+		}
+		data := map[string]string{
+			"Handle": strconv.Itoa(g.Handle), "OpponentName": pvpPlayerName(g, opp), "ColorLabel": colorLabel,
+			"Correspondence": "", "MyTurn": "", "Moves": strconv.Itoa(len(g.MovesUCI)),
+			"Age": durationLabel(now.Sub(g.UpdatedAt)), "Default": "", "Here": "",
+		}
+		if g.Correspondence {
+			data["Correspondence"] = "1"
+		}
+		if g.Turn == color {
+			data["MyTurn"] = "1"
+		}
+		if g.OriginRoom == c.Room || g.ResolveRoom == c.Room {
+			data["Here"] = "1"
+			if g.ID == def {
+				data["Default"] = "1"
+			}
+		}
+		line, e := b.catalog.Render("pvp.games.item", data)
+		if e != nil {
+			line = "#" + strconv.Itoa(g.Handle) + " vs " + pvpPlayerName(g, opp)
+		}
+		lines = append(lines, line)
+	}
+	return c.Reply(strings.Join(lines, "\n"))
+}
+
+// defaultGameArg parses `기본 #N` to the handle and `기본 해제|취소` to 0.
+func defaultGameArg(c *command.Context) (any, error) {
+	if a := c.Arg(0); a == "해제" || a == "취소" {
+		return 0, nil
+	}
+	if handle, ok := gameHandleArg(c.Arg(0)); ok {
+		return handle, nil
+	}
+	return nil, command.ErrUsage
+}
+
+// defaultGame sets or clears the user's default PvP game for this room (`기본 #N` / `기본 해제`).
+func (b *bot) defaultGame(c *command.Context) error {
+	handle := c.Parsed.(int)
+	logRoute(c, "default_game", "pvp", zap.Int("handle", handle))
+	g, err := b.pvp.SetDefaultGame(c.Ctx, c.UserID, c.Room, handle)
+	const fallback = "기본 대국 설정 실패"
+	switch {
+	case err == nil && g == nil:
+		return c.ReplyKey("pvp.games.default.cleared", nil, fallback)
+	case err == nil:
+		return c.ReplyKey("pvp.games.default.set", map[string]string{"Handle": strconv.Itoa(g.Handle), "WhiteName": g.WhiteName, "BlackName": g.BlackName}, fallback)
+	case errors.Is(err, pvpchess.ErrGameNotInRoom):
+		return command.Reject("pvp.games.default.not_in_room", map[string]string{"Handle": strconv.Itoa(handle), "Prefix": b.cfg.BotPrefix}, fallback)
+	case errors.Is(err, pvpchess.ErrNoSuchGame):
+		return command.Reject("pvp.games.no_handle", map[string]string{"Handle": strconv.Itoa(handle), "Prefix": b.cfg.BotPrefix}, fallback)
+	}
+	obslog.L().Warn("pvp_default_game_error", zap.Error(err), zap.String("user_id", c.UserID), zap.String("room_id", c.Room))
+	return command.Reject("move.state.error", nil, fallback)
+}
+
+// formatPvPRanking renders room and global rating ladders as one message.
+func formatPvPRanking(catalog *msgcat.Catalog, roomTop, globalTop []pvpchess.RatingEntry) string {
+	var b strings.Builder
+	section := func(headerKey, headerFallback string, list []pvpchess.RatingEntry) {
+		if h, e := catalog.Render(headerKey, nil); e == nil {
+			b.WriteString(h)
+		} else {
+			b.WriteString(headerFallback)
+		}
+		b.WriteString("\n")
+		if len(list) == 0 {
+			if t, e := catalog.Render("pvp.ranking.empty", nil); e == nil {
+				b.WriteString(t)
+			} else {
+				b.WriteString("기록 없음")
+			}
+			b.WriteString("\n")
+			return
+		}
+		for i, r := range list {
+			data := map[string]string{
+				"Rank":   strconv.Itoa(i + 1),
+				"Name":   strings.TrimSpace(r.Name),
+				"Rating": strconv.Itoa(r.Rating),
+				"Wins":   strconv.Itoa(r.Wins),
+				"Losses": strconv.Itoa(r.Losses),
+				"Draws":  strconv.Itoa(r.Draws),
+			}
+			if item, e := catalog.Render("pvp.ranking.item", data); e == nil {
+				b.WriteString(item)
+			} else {
+				fmt.Fprintf(&b, "%d. %s %d", i+1, r.Name, r.Rating)
+			}
+			b.WriteString("\n")
+		}
+	}
+	section("pvp.ranking.room_header", "🏆 이 방 랭킹", roomTop)
+	b.WriteString("\n")
+	section("pvp.ranking.global_header", "🌐 전체 랭킹", globalTop)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// gameHandleArg parses a `#N` game handle.
+func gameHandleArg(s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "#") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(s, "#"))
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// gameLimitText is the reply when the user already plays PVP_MAX_GAMES_PER_USER games.
+func gameLimitText(cfg *appcfg.AppConfig, catalog *msgcat.Catalog) string {
+	txt, e := catalog.Render("pvp.busy.limit", map[string]string{"Max": strconv.Itoa(cfg.PvpMaxGamesPerUser), "Prefix": cfg.BotPrefix})
+	if e != nil {
+		return "동시에 진행할 수 있는 대국 수를 모두 채웠습니다."
+	}
+	return txt
+}
+
+// gameSelectText explains why a move could not be routed to a single game (ErrAmbiguousGame / ErrNoSuchGame).
+func gameSelectText(ctx context.Context, cfg *appcfg.AppConfig, pvpChessMgr *pvpchess.Manager, catalog *msgcat.Catalog, userID, roomID string, handle int, err error) string {
+	if errors.Is(err, pvpchess.ErrNoSuchGame) {
+		txt, e := catalog.Render("pvp.games.no_handle", map[string]string{"Handle": strconv.Itoa(handle), "Prefix": cfg.BotPrefix})
+		if e != nil {
+			return "#" + strconv.Itoa(handle) + " 대국이 없습니다."
+		}
+		return txt
+	}
+	var labels []string
+	if games, _ := pvpChessMgr.ActiveGamesByUser(ctx, userID); len(games) > 0 {
+		for _, g := range games {
+			if g.OriginRoom != roomID && g.ResolveRoom != roomID {
+				continue
+			}
+			opp := g.WhiteID
+			if opp == userID {
+				opp = g.BlackID
+			}
+			labels = append(labels, "#"+strconv.Itoa(g.Handle)+" vs "+pvpPlayerName(g, opp))
+		}
+	}
+	txt, e := catalog.Render("pvp.games.ambiguous", map[string]string{"Games": strings.Join(labels, ", "), "Prefix": cfg.BotPrefix})
+	if e != nil {
+		return "진행 중인 대국이 여러 개입니다. `#번호 <수>`로 지정하세요."
+	}
+	return txt
+}
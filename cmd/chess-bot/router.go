@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/adapter/chesspresenter"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/command"
+	appcfg "github.com/park285/Cheese-KakaoTalk-bot/internal/config"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irisfast"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchan"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	svcchess "github.com/park285/Cheese-KakaoTalk-bot/internal/service/chess"
+	"go.uber.org/zap"
+)
+
+// bot holds what command handlers share. 핸들러는 (*bot) 메서드이며 commands()에서 레지스트리에 등록합니다.
+type bot struct {
+	cfg       *appcfg.AppConfig
+	pvp       *pvpchess.Manager
+	chans     *pvpchan.Manager
+	chess     *svcchess.Service // nil in PvP-only mode (싱글 엔진 없음)
+	formatter *chesspresenter.Formatter
+	catalog   *msgcat.Catalog
+	// results: 대국 종료 후속 안내(토너먼트/챔피언전), 종료 안내를 보낸 뒤 flush
+	results *resultFollowUps
+
+	out    *command.Replier
+	router *command.Registry
+}
+
+// newBot builds the command registry; replies go through defaultEgress, so set it first.
+func newBot(cfg *appcfg.AppConfig, pvpChessMgr *pvpchess.Manager, pvpChanMgr *pvpchan.Manager, chess *svcchess.Service, presenter *chesspresenter.Presenter, formatter *chesspresenter.Formatter, catalog *msgcat.Catalog) *bot {
+	b := &bot{cfg: cfg, pvp: pvpChessMgr, chans: pvpChanMgr, chess: chess, formatter: formatter, catalog: catalog}
+	b.out = &command.Replier{Egress: defaultEgress, Presenter: presenter, Catalog: catalog, Prefix: cfg.BotPrefix, Logger: obslog.L()}
+	b.router = b.commands()
+	formatter.SetHelpBody(func() string {
+		return b.router.Help(catalog, map[string]string{"Prefix": cfg.BotPrefix})
+	})
+	return b
+}
+
+// handle runs one prefixed chat message through the command registry.
+func (b *bot) handle(msg *irisfast.Message) {
+	// Parse input using sanitized text to tolerate zero-width/NBSP and JSON-only frames
+	text := sanitizeText(messageText(msg))
+	prefix := sanitizeText(b.cfg.BotPrefix)
+	c := &command.Context{
+		Ctx:    context.Background(),
+		Msg:    msg,
+		Room:   extractRoomID(msg),
+		UserID: strings.TrimSpace(userIDFromMessage(msg)),
+		Sender: senderName(msg),
+		Out:    b.out,
+	}
+	// 지정 도전의 @이름을 사용자 ID로 확정할 수 있도록 말한 사람 기록
+	_ = b.chans.NoteSpeaker(c.Ctx, c.Room, c.UserID, c.Sender)
+	b.router.Dispatch(c, strings.TrimPrefix(text, prefix))
+}
+
+// commands registers every chat command. 등록 순서가 곧 도움말 순서입니다(Help 키가 없는 명령은 숨김).
+func (b *bot) commands() *command.Registry {
+	user := []command.Check{requireUser}
+	seat := []command.Check{requireUser, b.requireGameSlot, b.requireNoSoloSession}
+	engine := []command.Check{b.requireEngine}
+
+	r := command.NewRegistry()
+	r.Empty = b.help
+	r.Fallback = b.move
+	r.Register(
+		&command.Command{Name: "방", Usage: "usage.lobby", Help: "help.lobby", Sub: []*command.Command{
+			{Name: "생성", Aliases: []string{"만들기"}, Checks: seat, Run: b.lobbyMake},
+			{Name: "리스트", Aliases: []string{"목록"}, Run: b.lobbyList},
+			{Name: "취소", Checks: user, Run: b.lobbyCancel},
+		}},
+		&command.Command{Name: "참가", Aliases: []string{"방참가"}, Usage: "usage.join", Help: "help.join", MinArgs: 1, Checks: seat, Run: b.join},
+		&command.Command{Name: "도전", Usage: "usage.challenge", Help: "help.challenge", MinArgs: 1, Checks: user, Parse: challengeAction, Run: b.challenge},
+		&command.Command{Name: "매칭", Help: "help.match", Checks: []command.Check{requireUser, b.requireRandomMatch, b.requireNoSoloSession}, Run: b.match, Sub: []*command.Command{
+			{Name: "취소", Aliases: []string{"종료"}, Checks: user, Run: b.matchLeave},
+		}},
+		&command.Command{Name: "현황", Aliases: []string{"보드"}, Help: "help.status", Target: true, Run: b.status},
+		&command.Command{Name: "기권", Target: true, Run: b.resign},
+		&command.Command{Name: "무승부", Aliases: []string{"무승부수락", "무승부거절"}, Help: "help.draw", Target: true, Checks: user, Parse: replyAction("offer"), Run: b.draw},
+		&command.Command{Name: "무르기", Aliases: []string{"무르기수락", "무르기거절"}, Help: "help.takeback", Target: true, Parse: replyAction("request"), Run: b.takeback},
+		&command.Command{Name: "관전", Usage: "usage.spectate", Help: "help.spectate", MinArgs: 1, Run: b.spectate, Sub: []*command.Command{
+			{Name: "종료", Aliases: []string{"취소"}, Run: b.unspectate},
+		}},
+		&command.Command{Name: "관전종료", Run: b.unspectate},
+		&command.Command{Name: "토너먼트", Aliases: []string{"대회"}, Usage: "usage.tournament", Help: "help.tournament", Checks: user, Run: b.tournamentOverview, Sub: []*command.Command{
+			{Name: "생성", Aliases: []string{"만들기", "개최"}, Checks: user, Run: b.tournamentCreate},
+			{Name: "참가", Aliases: []string{"참여"}, Checks: user, Run: b.tournamentJoin},
+			{Name: "탈퇴", Aliases: []string{"나가기"}, Checks: user, Run: b.tournamentLeave},
+			{Name: "시작", Checks: user, Run: b.tournamentStart},
+			{Name: "취소", Checks: user, Run: b.tournamentCancel},
+			{Name: "순위", Aliases: []string{"현황", "대진"}, Checks: user, Run: b.tournamentStandings},
+		}},
+		&command.Command{Name: "내대국", Aliases: []string{"대국목록"}, Help: "help.games", Checks: user, Run: b.myGames},
+		&command.Command{Name: "기본", Usage: "usage.default_game", Help: "help.default_game", Checks: user, Parse: defaultGameArg, Run: b.defaultGame},
+		&command.Command{Name: "챔피언전", Aliases: []string{"챔피언"}, Help: "help.arena", Checks: user, Run: b.arenaStatus, Sub: []*command.Command{
+			{Name: "도전", Checks: []command.Check{requireUser, b.requireNoSoloSession}, Run: b.arenaChallenge},
+		}},
+		&command.Command{Name: "랭킹", Aliases: []string{"순위"}, Help: "help.ranking", Run: b.ranking},
+
+		// 싱글 플레이 (PvP 전용 모드에서는 엔진 없음)
+		&command.Command{Name: "시작", Help: "help.start", Checks: engine, Run: b.soloStart},
+		&command.Command{Name: "분석", Usage: "usage.analysis", Help: "help.analysis", MinArgs: 1, Checks: engine, Parse: gameIDArg, Run: b.soloAnalysis},
+		&command.Command{Name: "기보", Usage: "usage.game", Help: "help.game", MinArgs: 1, Checks: engine, Parse: gameIDArg, Run: b.soloGame},
+
+		&command.Command{Name: "도움", Aliases: []string{"help"}, Run: b.soloAssist},
+		// 예전 붙여 쓰기 형태
+		&command.Command{Name: "방생성", Checks: seat, Run: b.lobbyMake},
+		&command.Command{Name: "방리스트", Aliases: []string{"방목록"}, Run: b.lobbyList},
+	)
+	return r
+}
+
+// catalogKeys returns the usage/help keys the registry renders, for the startup preflight.
+func (b *bot) catalogKeys() []string {
+	var keys []string
+	var walk func(cmds []*command.Command)
+	walk = func(cmds []*command.Command) {
+		for _, cmd := range cmds {
+			for _, k := range []string{cmd.Usage, cmd.Help} {
+				if k != "" {
+					keys = append(keys, k)
+				}
+			}
+			walk(cmd.Sub)
+		}
+	}
+	walk(b.router.Commands())
+	return keys
+}
+
+func (b *bot) help(c *command.Context) error {
+	return c.Reply(b.formatter.Help())
+}
+
+// requireUser rejects commands that need a stable user id.
+func requireUser(c *command.Context) error {
+	if c.UserID == "" {
+		return command.Reject("user.identify.error", nil, "사용자 식별 실패")
+	}
+	return nil
+}
+
+// requireEngine rejects single-player commands in PvP-only mode.
+func (b *bot) requireEngine(c *command.Context) error {
+	if b.chess == nil {
+		return command.Reject("chess.disabled", nil, "싱글 체스가 비활성화되어 있습니다.")
+	}
+	return nil
+}
+
+// requireGameSlot rejects new PvP games once the user plays PVP_MAX_GAMES_PER_USER games.
+func (b *bot) requireGameSlot(c *command.Context) error {
+	if errors.Is(b.pvp.CanStartGame(c.Ctx, c.UserID), pvpchess.ErrTooManyUserGames) {
+		return command.RejectText(gameLimitText(b.cfg, b.catalog))
+	}
+	return nil
+}
+
+// requireNoSoloSession rejects PvP entry while the user has a single-player game in this room.
+func (b *bot) requireNoSoloSession(c *command.Context) error {
+	if b.soloSession(c) != nil {
+		return command.Reject("pvp.busy.in_room", nil, "이미 진행 중인 대국이 있습니다. 종료 후 진행하세요.")
+	}
+	return nil
+}
+
+// requireRandomMatch rejects `매칭` unless ALLOW_RANDOM_MATCH is set.
+func (b *bot) requireRandomMatch(c *command.Context) error {
+	if !b.cfg.AllowRandomMatch {
+		return command.Reject("pvp.match.disabled", nil, "랜덤 매칭이 비활성화되어 있습니다.")
+	}
+	return nil
+}
+
+// replyAction maps `X [수락|거절]` and the joined `X수락`/`X거절` forms to accept/decline, otherwise def.
+func replyAction(def string) func(c *command.Context) (any, error) {
+	return func(c *command.Context) (any, error) {
+		switch {
+		case strings.HasSuffix(c.Name, "수락") || c.Arg(0) == "수락":
+			return "accept", nil
+		case strings.HasSuffix(c.Name, "거절") || c.Arg(0) == "거절":
+			return "decline", nil
+		}
+		return def, nil
+	}
+}
+
+// logRoute records which path a command took (pvp / legacy / none ...).
+func logRoute(c *command.Context, cmd, mode string, fields ...zap.Field) {
+	fields = append([]zap.Field{zap.String("cmd", cmd), zap.String("mode", mode), zap.String("room_id", c.Room), zap.String("user", c.UserID)}, fields...)
+	obslog.L().Info("route_decision", fields...)
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	nchess "github.com/corentings/chess/v2"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/adapter/chesspresenter"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/arena"
+	corechess "github.com/park285/Cheese-KakaoTalk-bot/internal/chess"
+	appcfg "github.com/park285/Cheese-KakaoTalk-bot/internal/config"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irisfast"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irissim"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchan"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/service/cache"
+	svcchess "github.com/park285/Cheese-KakaoTalk-bot/internal/service/chess"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/tournament"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/tournament/tournamenttest"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// 시나리오 골든 파일 갱신: go test ./cmd/chess-bot -run TestScenarios -update
+var updateGolden = flag.Bool("update", false, "rewrite testdata/scenarios/*.golden from the current bot replies")
+
+// scenario is one conversation in testdata/scenarios/<name>.yaml; the bot's replies are compared
+// against <name>.golden.
+type scenario struct {
+	// Engine enables single-player commands backed by a deterministic fake engine (기본: PvP 전용).
+	Engine bool `yaml:"engine"`
+	// Env overrides config environment variables (e.g. PVP_MAX_GAMES_PER_USER).
+	Env   map[string]string `yaml:"env"`
+	Steps []scenarioStep    `yaml:"steps"`
+}
+
+type scenarioStep struct {
+	Room   string `yaml:"room"`
+	User   string `yaml:"user"`
+	Sender string `yaml:"sender"`
+	Say    string `yaml:"say"`
+	// Capture stores the first group of each regexp (matched against this step's replies) as ${name}
+	// for later steps; 골든 파일에는 값 대신 ${name}으로 기록되어 랜덤 코드가 diff를 만들지 않습니다.
+	Capture map[string]string `yaml:"capture"`
+}
+
+func TestScenarios(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "scenarios", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no scenarios found")
+	}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sc scenario
+			if err := yaml.Unmarshal(raw, &sc); err != nil {
+				t.Fatalf("parse %s: %v", path, err)
+			}
+			got := runScenario(t, &sc)
+			golden := strings.TrimSuffix(path, ".yaml") + ".golden"
+			if *updateGolden {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden (run with -update to create it): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("transcript mismatch for %s (run with -update to accept)\n%s", name, transcriptDiff(string(want), got))
+			}
+		})
+	}
+}
+
+// botHarness wires the bot to miniredis, in-memory repositories and an Iris simulator
+// the same way main does, so every reply goes through the real egress path.
+type botHarness struct {
+	t   *testing.T
+	sim *irissim.TestServer
+	bot *bot
+}
+
+func newBotHarness(t *testing.T, sc *scenario) *botHarness {
+	t.Helper()
+	sim := irissim.StartTest(t)
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	t.Setenv("IRIS_BASE_URL", sim.URL)
+	t.Setenv("IRIS_WS_URL", sim.WSURL)
+	t.Setenv("BOT_PREFIX", "!체스")
+	t.Setenv("REDIS_URL", fmt.Sprintf("redis://%s/0", mr.Addr()))
+	for k, v := range sc.Env {
+		t.Setenv(k, v)
+	}
+	cfg, err := appcfg.Load()
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	pvp, err := pvpchess.NewManager(cfg.RedisURL)
+	if err != nil {
+		t.Fatalf("pvpchess.NewManager: %v", err)
+	}
+	t.Cleanup(func() { _ = pvp.Close() })
+	pvp.SetMaxActiveGames(cfg.MaxConcurrentGames)
+	pvp.SetMaxGamesPerUser(cfg.PvpMaxGamesPerUser)
+	// 랜덤 색 배정은 항상 신청자(생성자) 백으로 고정
+	pvp.SetCoinFlip(func() bool { return false })
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	lobby := pvpchan.NewManager(rdb, pvp)
+
+	catalog, err := msgcat.New("")
+	if err != nil {
+		t.Fatalf("msgcat: %v", err)
+	}
+	chesspresenter.SetCatalog(catalog)
+	formatter := chesspresenter.NewFormatter(prefixProvider{prefix: cfg.BotPrefix})
+	formatter.SetCatalog(catalog)
+
+	client := irisfast.NewClient(sim.URL)
+	// 간격 없이 main과 같은 송신 큐를 거침
+	egress := irisfast.NewQueue(irisfast.QueueOptions{MaxAttempts: cfg.EgressRetryMax}).Wrap(irisfast.NewEgress("http", false, client, nil, nil))
+	presenter := chesspresenter.NewEgressPresenter(egress)
+
+	// main과 같은 전역 배선 (테스트는 순차 실행)
+	defaultEgress = egress
+	pvpEgress = egress
+	pvpPresenter = presenter
+	tournamentMgr = tournament.NewManager(tournamenttest.NewMemoryStore(), pvp)
+	arenaMgr = arena.NewManager(rdb, pvp)
+	results := registerResultHooks(pvp, lobby, tournamentMgr, arenaMgr, catalog)
+
+	var chess *svcchess.Service
+	if sc.Engine {
+		cacheSvc, err := cache.NewCacheService(cache.CacheConfig{Host: mr.Host(), Port: atoiPort(t, mr.Port())}, zap.NewNop())
+		if err != nil {
+			t.Fatalf("cache: %v", err)
+		}
+		t.Cleanup(func() { _ = cacheSvc.Close() })
+		svc, err := svcchess.NewService(firstMoveEngine{}, cacheSvc, svcchess.NewMemoryRepository(), svcchess.NewSVGBoardRenderer(), svcchess.Config{
+			DefaultPreset: cfg.ChessDefaultPreset,
+			SessionTTL:    time.Duration(cfg.ChessSessionTTLSec) * time.Second,
+			HistoryLimit:  cfg.ChessHistoryLimit,
+		}, zap.NewNop())
+		if err != nil {
+			t.Fatalf("chess service: %v", err)
+		}
+		chess = svc
+	}
+	b := newBot(cfg, pvp, lobby, chess, presenter, formatter, catalog)
+	b.results = results
+	return &botHarness{t: t, sim: sim, bot: b}
+}
+
+// say delivers one chat line to the bot and returns the replies it produced. handle은 후속 안내
+// (토너먼트/챔피언전 결과, resultFollowUps)까지 보낸 뒤 돌아오고 송신은 Iris 응답을 기다리므로, 반환
+// 시점에 모든 응답이 시뮬레이터에 기록되어 있습니다. 방 간 전송 순서는 고정되어 있지 않으므로
+// 말한 방을 먼저, 나머지는 방 번호 순으로 묶습니다(방 안의 순서는 유지).
+func (h *botHarness) say(room, userID, sender, text string) []irissim.Reply {
+	h.t.Helper()
+	before := len(h.sim.Replies())
+	msg := irissim.Message(room, userID, sender, text)
+	h.bot.handle(&msg)
+	out := h.sim.Replies()[before:]
+	sort.SliceStable(out, func(i, j int) bool {
+		if (out[i].Room == room) != (out[j].Room == room) {
+			return out[i].Room == room
+		}
+		return out[i].Room < out[j].Room
+	})
+	return out
+}
+
+func runScenario(t *testing.T, sc *scenario) string {
+	t.Helper()
+	h := newBotHarness(t, sc)
+	vars := map[string]string{}
+	var out strings.Builder
+	for i, st := range sc.Steps {
+		room, user := strings.TrimSpace(st.Room), strings.TrimSpace(st.User)
+		if room == "" || user == "" || strings.TrimSpace(st.Say) == "" {
+			t.Fatalf("step %d: room, user and say are required", i+1)
+		}
+		text := expandVars(st.Say, vars)
+		replies := h.say(room, user, st.Sender, text)
+
+		var joined strings.Builder
+		for _, r := range replies {
+			if r.Type == "text" {
+				joined.WriteString(r.Data)
+				joined.WriteString("\n")
+			}
+		}
+		for _, name := range sortedKeys(st.Capture) {
+			re, err := regexp.Compile(st.Capture[name])
+			if err != nil {
+				t.Fatalf("step %d: capture %s: %v", i+1, name, err)
+			}
+			m := re.FindStringSubmatch(joined.String())
+			if len(m) < 2 || m[1] == "" {
+				t.Fatalf("step %d: capture %s (%s) did not match replies:\n%s", i+1, name, st.Capture[name], joined.String())
+			}
+			vars[name] = m[1]
+		}
+
+		sender := st.Sender
+		if strings.TrimSpace(sender) == "" {
+			sender = user
+		}
+		fmt.Fprintf(&out, "> %s %s(%s): %s\n", room, sender, user, st.Say)
+		for _, r := range replies {
+			if r.Type != "text" {
+				fmt.Fprintf(&out, "< %s %s\n", r.Room, r.Type)
+				continue
+			}
+			fmt.Fprintf(&out, "< %s text\n", r.Room)
+			for _, line := range strings.Split(strings.TrimRight(maskVars(r.Data, vars), "\n"), "\n") {
+				fmt.Fprintf(&out, "  | %s\n", line)
+			}
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+func expandVars(s string, vars map[string]string) string {
+	for k, v := range vars {
+		s = strings.ReplaceAll(s, "${"+k+"}", v)
+	}
+	return s
+}
+
+// 실행 시각에 따라 달라지는 값: 기보의 시작/종료 시각과 소요 시간
+var (
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2} \d{2}:\d{2}`)
+	durationPattern  = regexp.MustCompile(`\b(?:\d+h)?(?:\d+m)?\d+(?:\.\d+)?(?:ms|µs|s)\b`)
+)
+
+// maskVars replaces captured values with ${name} and times with <time>/<duration> so transcripts are stable.
+func maskVars(s string, vars map[string]string) string {
+	for _, k := range sortedKeys(vars) {
+		s = strings.ReplaceAll(s, vars[k], "${"+k+"}")
+	}
+	s = timestampPattern.ReplaceAllString(s, "<time>")
+	return durationPattern.ReplaceAllString(s, "<duration>")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// transcriptDiff reports the first differing line with a little context.
+func transcriptDiff(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(w) || i < len(g); i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl {
+			from := i - 3
+			if from < 0 {
+				from = 0
+			}
+			var b strings.Builder
+			for j := from; j < i && j < len(g); j++ {
+				fmt.Fprintf(&b, "  %s\n", g[j])
+			}
+			fmt.Fprintf(&b, "- %s\n+ %s\n", wl, gl)
+			return fmt.Sprintf("line %d:\n%s", i+1, b.String())
+		}
+	}
+	return ""
+}
+
+func atoiPort(t *testing.T, s string) int {
+	t.Helper()
+	var n int
+	if _, err := fmt.Sscanf(s, "%d", &n); err != nil {
+		t.Fatalf("port %q: %v", s, err)
+	}
+	return n
+}
+
+// firstMoveEngine is a deterministic Evaluator/Analyzer: it always plays the first legal move in
+// UCI order and evaluates every position as equal.
+type firstMoveEngine struct{}
+
+func (firstMoveEngine) Evaluate(_ context.Context, req corechess.EvaluateRequest) (corechess.EvaluateResult, error) {
+	pos, err := replayPosition(req.FEN, req.Moves)
+	if err != nil {
+		return corechess.EvaluateResult{}, err
+	}
+	best := firstLegalMove(pos)
+	c := corechess.Candidate{Move: best}
+	return corechess.EvaluateResult{Candidates: []corechess.Candidate{c}, Chosen: c, EngineBestMove: best}, nil
+}
+
+func (firstMoveEngine) AnalyzeGame(_ context.Context, startFEN string, moves []string, _ int) ([]corechess.PositionEval, error) {
+	out := make([]corechess.PositionEval, 0, len(moves)+1)
+	for i := 0; i <= len(moves); i++ {
+		pos, err := replayPosition(startFEN, moves[:i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, corechess.PositionEval{BestMove: firstLegalMove(pos)})
+	}
+	return out, nil
+}
+
+func replayPosition(fen string, moves []string) (*nchess.Position, error) {
+	game := nchess.NewGame()
+	if f := strings.TrimSpace(fen); f != "" && f != "startpos" {
+		opt, err := nchess.FEN(f)
+		if err != nil {
+			return nil, err
+		}
+		game = nchess.NewGame(opt)
+	}
+	notation := nchess.UCINotation{}
+	for _, mv := range moves {
+		m, err := notation.Decode(game.Position(), mv)
+		if err != nil {
+			return nil, err
+		}
+		if err := game.Move(m, nil); err != nil {
+			return nil, err
+		}
+	}
+	return game.Position(), nil
+}
+
+func firstLegalMove(pos *nchess.Position) string {
+	notation := nchess.UCINotation{}
+	var moves []string
+	for _, m := range pos.ValidMoves() {
+		moves = append(moves, notation.Encode(pos, &m))
+	}
+	if len(moves) == 0 {
+		return ""
+	}
+	sort.Strings(moves)
+	return moves[0]
+}
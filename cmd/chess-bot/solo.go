@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/adapter/chesspresenter"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/command"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	svcchess "github.com/park285/Cheese-KakaoTalk-bot/internal/service/chess"
+	"github.com/park285/Cheese-KakaoTalk-bot/pkg/chessdto"
+	"go.uber.org/zap"
+)
+
+// soloMeta identifies the single-player session of the sender in this room.
+func (b *bot) soloMeta(c *command.Context) svcchess.SessionMeta {
+	return svcchess.SessionMeta{SessionID: sessionIDFor(c.Msg), Room: c.Room, Sender: c.Sender}
+}
+
+// soloSession returns the sender's active single-player session, or nil (엔진 없음 포함).
+func (b *bot) soloSession(c *command.Context) *svcchess.SessionState {
+	if b.chess == nil {
+		return nil
+	}
+	st, err := b.chess.Status(c.Ctx, b.soloMeta(c))
+	if err != nil {
+		return nil
+	}
+	return st
+}
+
+// soloStart starts (or resumes) a game against the engine.
+// 인자 순서 무관: 난이도(level1~level8)와 진영(백|흑|랜덤); `fen <FEN>` / `pgn <PGN>` 이후의 나머지는 포지션으로 취급
+func (b *bot) soloStart(c *command.Context) error {
+	preset, color, source, position := "", "", "", ""
+	for i, a := range c.Args {
+		if kw := strings.ToLower(a); kw == "fen" || kw == "pgn" {
+			source = kw
+			position = strings.Join(c.Args[i+1:], " ")
+			break
+		}
+		if side, ok := svcchess.ParsePlayerColor(a); ok {
+			color = side
+			continue
+		}
+		preset = a
+	}
+	meta := b.soloMeta(c)
+	var (
+		state *svcchess.SessionState
+		err   error
+	)
+	switch source {
+	case "fen":
+		state, err = b.chess.StartSessionFromFEN(c.Ctx, meta, preset, color, position)
+	case "pgn":
+		state, err = b.chess.StartSessionFromPGN(c.Ctx, meta, preset, color, position)
+	default:
+		state, err = b.chess.StartSession(c.Ctx, meta, preset, color, false)
+	}
+	resumed := false
+	if errorsEqual(err, svcchess.ErrSessionInProgress) {
+		if st, sErr := b.chess.Status(c.Ctx, meta); sErr == nil {
+			state, resumed, err = st, true, nil
+		}
+	}
+	if errors.Is(err, svcchess.ErrInvalidPosition) {
+		return command.Reject("chess.start.invalid_position", map[string]string{"Error": err.Error()}, "포지션을 불러올 수 없습니다: "+err.Error())
+	}
+	if err != nil {
+		return command.Reject("chess.start.failed", map[string]string{"Error": err.Error()}, "체스 시작 실패: "+err.Error())
+	}
+	return c.Board(b.formatter.Start(chesspresenterAdaptState(state), resumed), chesspresenterAdaptState(state))
+}
+
+// gameIDArg parses the numeric game id of `분석 <ID>` / `기보 <ID>`.
+func gameIDArg(c *command.Context) (any, error) {
+	id, err := strconv.ParseInt(c.Arg(0), 10, 64)
+	if err != nil {
+		return nil, command.Reject("game.id.invalid", nil, "잘못된 ID")
+	}
+	return id, nil
+}
+
+// soloAnalysis runs the post-game engine analysis of a recorded game.
+func (b *bot) soloAnalysis(c *command.Context) error {
+	report, err := b.chess.Analyze(c.Ctx, b.soloMeta(c), c.Parsed.(int64))
+	if err != nil {
+		key, fallback := "chess.analysis.failed", "분석 실패: "+err.Error()
+		switch {
+		case errors.Is(err, svcchess.ErrGameNotFound):
+			key, fallback = "game.not_found", "대국 정보를 찾을 수 없습니다."
+		case errors.Is(err, svcchess.ErrAnalysisUnavailable):
+			key, fallback = "chess.analysis.unavailable", "분석 엔진을 사용할 수 없습니다."
+		}
+		return command.Reject(key, map[string]string{"Error": err.Error()}, fallback)
+	}
+	dto := chesspresenter.ToDTOAnalysis(report)
+	return c.Board(b.formatter.Analysis(dto), &chessdto.SessionState{BoardImage: dto.BoardImage})
+}
+
+// soloGame shows a recorded game: `기보 <ID> [gif]`.
+func (b *bot) soloGame(c *command.Context) error {
+	game, err := b.chess.Game(c.Ctx, b.soloMeta(c), c.Parsed.(int64))
+	if err != nil {
+		return command.Reject("game.fetch.error", map[string]string{"Error": err.Error()}, "기보 조회 실패: "+err.Error())
+	}
+	text := b.formatter.Game(chesspresenter.ToDTOGame(game))
+	if isReplayOption(c.Arg(1)) {
+		anim, rErr := b.chess.Replay(c.Ctx, game)
+		if rErr != nil {
+			_ = c.Reply(text)
+			if errors.Is(rErr, svcchess.ErrReplayTooLarge) {
+				return command.Reject("chess.replay.too_large", nil, "기보가 길어 GIF 크기 제한을 넘었습니다.")
+			}
+			return command.Reject("chess.replay.failed", map[string]string{"Error": rErr.Error()}, "기보 GIF 생성 실패: "+rErr.Error())
+		}
+		return c.Image(text, anim)
+	}
+	// 분석된 게임이면 평가 그래프를 함께 전송
+	graph, gErr := b.chess.EvalGraph(c.Ctx, game)
+	if gErr != nil {
+		obslog.L().Warn("chess_eval_graph_error", zap.Int64("game_id", game.ID), zap.Error(gErr))
+	}
+	return c.Image(text, graph)
+}
+
+// soloAssist answers `도움` with the engine's suggested move while the sender plays a single-player
+// game in this room; otherwise it shows help.
+func (b *bot) soloAssist(c *command.Context) error {
+	if b.soloSession(c) == nil {
+		return b.help(c)
+	}
+	suggestion, err := b.chess.Assist(c.Ctx, b.soloMeta(c))
+	if err != nil {
+		return command.Reject("chess.assist.failed", map[string]string{"Error": err.Error()}, "추천 수 계산 실패: "+err.Error())
+	}
+	return c.Reply(b.formatter.Assist(chesspresenterAdaptAssist(suggestion)))
+}
+
+// isReplayOption reports whether a 기보 argument requests the animated replay.
+func isReplayOption(token string) bool {
+	switch strings.ToLower(strings.TrimSpace(token)) {
+	case "gif", "움짤", "재생":
+		return true
+	}
+	return false
+}
+
+func chesspresenterAdaptState(s *svcchess.SessionState) *chessdto.SessionState {
+	return chesspresenter.ToDTOState(s)
+}
+
+func chesspresenterAdaptSummary(m *svcchess.MoveSummary) *chessdto.MoveSummary {
+	return chesspresenter.ToDTOMoveSummary(m)
+}
+
+func chesspresenterAdaptAssist(a *svcchess.AssistSuggestion) *chessdto.AssistSuggestion {
+	return chesspresenter.ToDTOAssist(a)
+}
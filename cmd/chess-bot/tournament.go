@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/command"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchan"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/tournament"
+	"go.uber.org/zap"
+)
+
+// tournamentFallback is sent when a tournament template fails to render.
+const tournamentFallback = "토너먼트 처리 실패"
+
+// tournamentOverview handles a bare `토너먼트`: 현재(또는 마지막) 대회의 순위표와 이번 라운드 대진.
+// 알 수 없는 하위 명령이면 사용법을 보여줍니다.
+func (b *bot) tournamentOverview(c *command.Context) error {
+	if len(c.Args) > 0 {
+		return command.ErrUsage
+	}
+	return b.tournamentStandings(c)
+}
+
+// tournamentCreate handles `토너먼트 생성 [풀리그|스위스] [라운드 수] [이름]`.
+func (b *bot) tournamentCreate(c *command.Context) error {
+	logRoute(c, "tournament", "pvp", zap.String("sub", "생성"))
+	format, rounds, rest := tournament.FormatRoundRobin, 0, c.Args
+	if len(rest) > 0 {
+		if f, ok := tournament.ParseFormat(strings.ToLower(rest[0])); ok {
+			format, rest = f, rest[1:]
+		}
+	}
+	if len(rest) > 0 {
+		if n, err := strconv.Atoi(rest[0]); err == nil {
+			rounds, rest = n, rest[1:]
+		}
+	}
+	t, err := tournamentMgr.Create(c.Ctx, c.Room, c.UserID, c.Sender, strings.TrimSpace(strings.Join(rest, " ")), format, rounds)
+	if err != nil {
+		return b.tournamentReject(c, "생성", err)
+	}
+	data := map[string]string{"Name": t.Name, "FormatLabel": tournamentFormatLabel(t.Format), "Rounds": "", "Prefix": b.cfg.BotPrefix}
+	if t.Rounds > 0 {
+		data["Rounds"] = strconv.Itoa(t.Rounds)
+	}
+	return c.ReplyKey("tournament.created", data, tournamentFallback)
+}
+
+// tournamentJoin handles `토너먼트 참가`.
+func (b *bot) tournamentJoin(c *command.Context) error {
+	logRoute(c, "tournament", "pvp", zap.String("sub", "참가"))
+	t, n, err := tournamentMgr.Join(c.Ctx, c.Room, c.UserID, c.Sender)
+	if err != nil {
+		return b.tournamentReject(c, "참가", err)
+	}
+	return c.ReplyKey("tournament.joined", map[string]string{"PlayerName": c.Sender, "Name": t.Name, "Count": strconv.Itoa(n)}, tournamentFallback)
+}
+
+// tournamentLeave handles `토너먼트 탈퇴`.
+func (b *bot) tournamentLeave(c *command.Context) error {
+	logRoute(c, "tournament", "pvp", zap.String("sub", "탈퇴"))
+	t, n, err := tournamentMgr.Leave(c.Ctx, c.Room, c.UserID)
+	if err != nil {
+		return b.tournamentReject(c, "탈퇴", err)
+	}
+	return c.ReplyKey("tournament.left", map[string]string{"PlayerName": c.Sender, "Name": t.Name, "Count": strconv.Itoa(n)}, tournamentFallback)
+}
+
+// tournamentStart handles `토너먼트 시작` and announces the first round.
+func (b *bot) tournamentStart(c *command.Context) error {
+	logRoute(c, "tournament", "pvp", zap.String("sub", "시작"))
+	ev, err := tournamentMgr.Start(c.Ctx, c.Room, c.UserID)
+	if err != nil {
+		return b.tournamentReject(c, "시작", err)
+	}
+	announceTournamentEvent(b.pvp, b.chans, b.catalog, ev)
+	return nil
+}
+
+// tournamentCancel handles `토너먼트 취소`.
+func (b *bot) tournamentCancel(c *command.Context) error {
+	logRoute(c, "tournament", "pvp", zap.String("sub", "취소"))
+	t, err := tournamentMgr.Cancel(c.Ctx, c.Room, c.UserID)
+	if err != nil {
+		return b.tournamentReject(c, "취소", err)
+	}
+	return c.ReplyKey("tournament.cancelled", map[string]string{"Name": t.Name}, tournamentFallback)
+}
+
+// tournamentStandings shows the standings and, while running, the current round pairings.
+func (b *bot) tournamentStandings(c *command.Context) error {
+	logRoute(c, "tournament", "pvp", zap.String("sub", "순위"))
+	t, players, standings, err := tournamentMgr.Standings(c.Ctx, c.Room)
+	if err != nil {
+		return b.tournamentReject(c, "순위", err)
+	}
+	txt := renderTournamentStandings(b.catalog, t, standings)
+	if t.Status == tournament.StatusRunning {
+		if pairings, pErr := tournamentMgr.RoundPairings(c.Ctx, t); pErr == nil && len(pairings) > 0 {
+			names := make(map[string]string, len(players))
+			for _, p := range players {
+				names[p.UserID] = p.Name
+			}
+			txt += "\n\n" + renderTournamentRound(b.catalog, t, t.CurrentRound, pairings, names)
+		}
+	}
+	return c.Reply(txt)
+}
+
+// tournamentReject maps a tournament manager error to its notice.
+func (b *bot) tournamentReject(c *command.Context, sub string, err error) error {
+	key := tournamentErrorKey(err)
+	if key == "tournament.error.failed" {
+		obslog.L().Warn("tournament_cmd_error", zap.Error(err), zap.String("sub", sub), zap.String("room_id", c.Room), zap.String("user_id", c.UserID))
+	}
+	return command.Reject(key, map[string]string{"Prefix": b.cfg.BotPrefix, "Error": err.Error()}, tournamentFallback)
+}
+
+// tournamentErrorKey maps tournament manager errors to catalog keys.
+func tournamentErrorKey(err error) string {
+	switch {
+	case errors.Is(err, tournament.ErrTournamentExists):
+		return "tournament.error.exists"
+	case errors.Is(err, tournament.ErrNoTournament):
+		return "tournament.error.none"
+	case errors.Is(err, tournament.ErrRegistrationClosed):
+		return "tournament.error.closed"
+	case errors.Is(err, tournament.ErrAlreadyRegistered):
+		return "tournament.error.already"
+	case errors.Is(err, tournament.ErrNotRegistered):
+		return "tournament.error.not_registered"
+	case errors.Is(err, tournament.ErrNotOrganizer):
+		return "tournament.error.not_organizer"
+	case errors.Is(err, tournament.ErrTooFewPlayers):
+		return "tournament.error.too_few"
+	case errors.Is(err, tournament.ErrInvalidRounds):
+		return "tournament.error.invalid_rounds"
+	case errors.Is(err, pvpchess.ErrTooManyGames):
+		return "pvp.capacity"
+	}
+	return "tournament.error.failed"
+}
+
+func tournamentFormatLabel(f tournament.Format) string {
+	if f == tournament.FormatSwiss {
+		return "스위스"
+	}
+	return "풀리그"
+}
+
+func tournamentStatusLabel(s tournament.Status) string {
+	switch s {
+	case tournament.StatusRegistering:
+		return "참가 접수 중"
+	case tournament.StatusRunning:
+		return "진행 중"
+	case tournament.StatusFinished:
+		return "종료"
+	case tournament.StatusCancelled:
+		return "취소됨"
+	}
+	return string(s)
+}
+
+func formatPoints(p float64) string { return strconv.FormatFloat(p, 'f', -1, 64) }
+
+// renderTournamentStandings renders the header and one row per player.
+func renderTournamentStandings(catalog *msgcat.Catalog, t *tournament.Tournament, standings []tournament.Standing) string {
+	head, e := catalog.Render("tournament.standings.header", map[string]string{
+		"Name": t.Name, "FormatLabel": tournamentFormatLabel(t.Format), "Status": tournamentStatusLabel(t.Status),
+		"Round": strconv.Itoa(t.CurrentRound), "Rounds": strconv.Itoa(t.Rounds),
+	})
+	if e != nil {
+		head = "🏆 " + t.Name
+	}
+	lines := []string{head}
+	if len(standings) == 0 {
+		if txt, e := catalog.Render("tournament.standings.empty", nil); e == nil {
+			lines = append(lines, txt)
+		}
+	}
+	for _, s := range standings {
+		row, e := catalog.Render("tournament.standings.row", map[string]string{
+			"Rank": strconv.Itoa(s.Rank), "Name": s.Name, "Points": formatPoints(s.Points),
+			"Buchholz": formatPoints(s.Buchholz), "SB": formatPoints(s.SonnebornBerger), "Games": strconv.Itoa(s.Games),
+		})
+		if e != nil {
+			row = strconv.Itoa(s.Rank) + ". " + s.Name + " " + formatPoints(s.Points)
+		}
+		lines = append(lines, row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderTournamentRound renders the pairings of one round; 대국 id가 없는 판은 보류(대기)로 표시합니다.
+func renderTournamentRound(catalog *msgcat.Catalog, t *tournament.Tournament, round int, pairings []tournament.Pairing, names map[string]string) string {
+	head, e := catalog.Render("tournament.round.header", map[string]string{"Name": t.Name, "Round": strconv.Itoa(round), "Rounds": strconv.Itoa(t.Rounds)})
+	if e != nil {
+		head = "🏆 " + t.Name + " " + strconv.Itoa(round) + "라운드"
+	}
+	lines := []string{head}
+	for _, p := range pairings {
+		var line string
+		if p.IsBye() {
+			line, e = catalog.Render("tournament.round.bye", map[string]string{"Name": names[p.WhiteID]})
+		} else {
+			pending := ""
+			if p.GameID == "" && p.Result == tournament.ResultPending {
+				pending = "1"
+			}
+			line, e = catalog.Render("tournament.round.pairing", map[string]string{
+				"Board": strconv.Itoa(p.Board), "White": names[p.WhiteID], "Black": names[p.BlackID],
+				"Result": string(p.Result), "Pending": pending,
+			})
+		}
+		if e != nil {
+			line = names[p.WhiteID] + " vs " + names[p.BlackID]
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// announceTournamentEvent posts a new round (pairings + board of each started game) or the final standings.
+func announceTournamentEvent(pvpChessMgr *pvpchess.Manager, pvpChanMgr *pvpchan.Manager, catalog *msgcat.Catalog, ev *tournament.Event) {
+	if ev == nil || ev.Tournament == nil {
+		return
+	}
+	ctx := context.Background()
+	t := ev.Tournament
+	switch ev.Kind {
+	case tournament.EventFinished:
+		winner := ""
+		if len(ev.Standings) > 0 {
+			winner = ev.Standings[0].Name
+		}
+		head, e := catalog.Render("tournament.finished", map[string]string{"Name": t.Name, "Winner": winner})
+		if e != nil {
+			head = "🏁 " + t.Name + " 종료"
+		}
+		_ = defaultEgress.SendText(ctx, t.Room, head+"\n\n"+renderTournamentStandings(catalog, t, ev.Standings))
+	default:
+		_ = defaultEgress.SendText(ctx, t.Room, renderTournamentRound(catalog, t, ev.Round, ev.Pairings, ev.Players))
+		for _, g := range ev.Games {
+			text, e := catalog.Render("pvp.start.announce", map[string]string{"WhiteName": g.WhiteName, "BlackName": g.BlackName})
+			if e != nil {
+				text = "♟️ 대국 시작 — " + g.WhiteName + " vs " + g.BlackName
+			}
+			rooms := prioritizeRooms(fanoutRooms(ctx, pvpChanMgr, g, t.Room), t.Room)
+			obslog.L().Info("pvp_fanout_targets", zap.Strings("rooms", rooms), zap.String("game_id", g.ID), zap.String("phase", "tournament"))
+			sendPvPBoards(ctx, pvpChessMgr, pvpChanMgr, catalog, g, rooms, text, "tournament")
+		}
+	}
+}
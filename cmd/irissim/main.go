@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irissim"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"go.uber.org/zap"
+)
+
+// irissim serves a local Iris stand-in for end-to-end runs of chess-bot:
+//
+//	IRISSIM_ADDR=:3000 go run ./cmd/irissim
+//	IRIS_BASE_URL=http://127.0.0.1:3000 IRIS_WS_URL=ws://127.0.0.1:3000/ws go run ./cmd/chess-bot
+//
+// IRISSIM_SCRIPT가 있으면 첫 WebSocket 연결 후 JSON-lines 스크립트를 실행합니다.
+func main() {
+	_ = obslog.InitFromEnv()
+	logger := obslog.L()
+
+	addr := strings.TrimSpace(os.Getenv("IRISSIM_ADDR"))
+	if addr == "" {
+		addr = ":3000"
+	}
+	sim := irissim.NewServer()
+
+	mux := http.NewServeMux()
+	mux.Handle("/", sim.Handler())
+	mux.Handle(irissim.ControlPrefix, sim.ControlHandler())
+	srv := &http.Server{Addr: addr, Handler: logRequests(logger, mux), ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		logger.Info("irissim_listen", zap.String("addr", addr))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("irissim_listen_error", zap.Error(err))
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if path := strings.TrimSpace(os.Getenv("IRISSIM_SCRIPT")); path != "" {
+		go runScript(ctx, logger, sim, path)
+	}
+
+	<-ctx.Done()
+	sctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sim.DropSockets()
+	_ = srv.Shutdown(sctx)
+	for _, r := range sim.Replies() {
+		logger.Info("irissim_reply", zap.String("via", r.Via), zap.String("type", r.Type), zap.String("room", r.Room), zap.Int("bytes", len(r.Data)))
+	}
+}
+
+func runScript(ctx context.Context, logger *zap.Logger, sim *irissim.Server, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		logger.Error("irissim_script_open_error", zap.String("path", path), zap.Error(err))
+		return
+	}
+	defer f.Close()
+	if err := sim.WaitConnections(ctx, 1); err != nil {
+		return
+	}
+	logger.Info("irissim_script_start", zap.String("path", path))
+	if err := sim.RunScript(ctx, f); err != nil {
+		logger.Error("irissim_script_error", zap.Error(err))
+		return
+	}
+	logger.Info("irissim_script_done", zap.Int("replies", len(sim.Replies())))
+}
+
+// logRequests logs each Iris API call without bodies (이미지 base64가 크므로 본문은 남기지 않음).
+func logRequests(logger *zap.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		if r.URL.Path != irissim.PathWS {
+			logger.Debug("irissim_request", zap.String("method", r.Method), zap.String("path", r.URL.Path), zap.Duration("took", time.Since(start)))
+		}
+	})
+}
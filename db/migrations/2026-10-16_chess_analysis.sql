@@ -0,0 +1,18 @@
+-- Single-player side (white|black) and per-ply post-game engine analysis
+ALTER TABLE IF EXISTS chess_games
+    ADD COLUMN IF NOT EXISTS player_color TEXT NOT NULL DEFAULT 'white';
+ALTER TABLE IF EXISTS chess_games
+    ADD COLUMN IF NOT EXISTS accuracy REAL;
+ALTER TABLE IF EXISTS chess_games
+    ADD COLUMN IF NOT EXISTS analysis_depth INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS chess_game_analysis (
+  game_id BIGINT NOT NULL REFERENCES chess_games(id) ON DELETE CASCADE,
+  ply INT NOT NULL,
+  move_uci TEXT NOT NULL,
+  best_move TEXT NOT NULL DEFAULT '',
+  eval_cp INT NOT NULL DEFAULT 0,
+  cp_loss INT NOT NULL DEFAULT 0,
+  class TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (game_id, ply)
+);
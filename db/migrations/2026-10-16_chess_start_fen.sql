@@ -0,0 +1,5 @@
+-- Single-player games started from a custom position (FEN/PGN import) are stored unrated
+ALTER TABLE IF EXISTS chess_games
+    ADD COLUMN IF NOT EXISTS start_fen TEXT;
+ALTER TABLE IF EXISTS chess_games
+    ADD COLUMN IF NOT EXISTS rated BOOLEAN NOT NULL DEFAULT TRUE;
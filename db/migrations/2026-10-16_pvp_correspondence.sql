@@ -0,0 +1,11 @@
+-- Correspondence PvP games: ACTIVE game state survives Redis restarts (row removed when the game ends)
+CREATE TABLE IF NOT EXISTS pvp_correspondence_games (
+  game_id TEXT PRIMARY KEY,
+  white_id TEXT NOT NULL,
+  black_id TEXT NOT NULL,
+  state JSONB NOT NULL,          -- pvpchess.Game JSON
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pvp_correspondence_white ON pvp_correspondence_games(white_id);
+CREATE INDEX IF NOT EXISTS idx_pvp_correspondence_black ON pvp_correspondence_games(black_id);
@@ -0,0 +1,18 @@
+-- PvP Elo ladder: per-user ratings and a guard flag so each result is rated once
+ALTER TABLE IF EXISTS pvp_games
+    ADD COLUMN IF NOT EXISTS rated BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS pvp_ratings (
+  user_id TEXT PRIMARY KEY,
+  name TEXT NOT NULL DEFAULT '',
+  rating INT NOT NULL DEFAULT 1200,
+  games INT NOT NULL DEFAULT 0,
+  wins INT NOT NULL DEFAULT 0,
+  losses INT NOT NULL DEFAULT 0,
+  draws INT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pvp_ratings_rating ON pvp_ratings(rating DESC);
+CREATE INDEX IF NOT EXISTS idx_pvp_games_origin_room ON pvp_games(origin_room);
+CREATE INDEX IF NOT EXISTS idx_pvp_games_resolve_room ON pvp_games(resolve_room);
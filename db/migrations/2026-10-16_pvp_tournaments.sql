@@ -0,0 +1,39 @@
+-- Room tournaments (round robin / Swiss): events, registrations and per-round pairings
+CREATE TABLE IF NOT EXISTS pvp_tournaments (
+  id BIGSERIAL PRIMARY KEY,
+  room TEXT NOT NULL,
+  name TEXT NOT NULL,
+  format TEXT NOT NULL,          -- 'roundrobin' | 'swiss'
+  status TEXT NOT NULL,          -- 'REGISTERING' | 'RUNNING' | 'FINISHED' | 'CANCELLED'
+  rounds INT NOT NULL DEFAULT 0,
+  current_round INT NOT NULL DEFAULT 0,
+  creator_id TEXT NOT NULL,
+  creator_name TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  started_at TIMESTAMP NULL,
+  finished_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pvp_tournaments_room ON pvp_tournaments(room, id DESC);
+
+CREATE TABLE IF NOT EXISTS pvp_tournament_players (
+  tournament_id BIGINT NOT NULL REFERENCES pvp_tournaments(id) ON DELETE CASCADE,
+  user_id TEXT NOT NULL,
+  name TEXT NOT NULL DEFAULT '',
+  rating INT NOT NULL DEFAULT 1200,
+  joined_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (tournament_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS pvp_tournament_pairings (
+  tournament_id BIGINT NOT NULL REFERENCES pvp_tournaments(id) ON DELETE CASCADE,
+  round INT NOT NULL,
+  board INT NOT NULL,
+  white_id TEXT NOT NULL,
+  black_id TEXT NOT NULL DEFAULT '',  -- '' = bye
+  game_id TEXT NOT NULL DEFAULT '',   -- pvp_games.game_id once the board started
+  result TEXT NOT NULL DEFAULT '',    -- '' | '1-0' | '0-1' | '1/2-1/2' | 'bye'
+  PRIMARY KEY (tournament_id, round, board)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pvp_tournament_pairings_game ON pvp_tournament_pairings(game_id) WHERE game_id <> '';
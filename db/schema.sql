@@ -40,6 +40,25 @@ CREATE TABLE IF NOT EXISTS chess_games (
 
 CREATE INDEX IF NOT EXISTS idx_chess_games_player ON chess_games(player_hash);
 
+-- Single-player side and post-game analysis (accuracy is NULL until analyzed)
+ALTER TABLE chess_games ADD COLUMN IF NOT EXISTS player_color TEXT NOT NULL DEFAULT 'white';
+ALTER TABLE chess_games ADD COLUMN IF NOT EXISTS accuracy REAL;
+ALTER TABLE chess_games ADD COLUMN IF NOT EXISTS analysis_depth INT NOT NULL DEFAULT 0;
+-- Custom start position (FEN/PGN import); such games are unrated
+ALTER TABLE chess_games ADD COLUMN IF NOT EXISTS start_fen TEXT;
+ALTER TABLE chess_games ADD COLUMN IF NOT EXISTS rated BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE IF NOT EXISTS chess_game_analysis (
+  game_id BIGINT NOT NULL REFERENCES chess_games(id) ON DELETE CASCADE,
+  ply INT NOT NULL,
+  move_uci TEXT NOT NULL,
+  best_move TEXT NOT NULL DEFAULT '',
+  eval_cp INT NOT NULL DEFAULT 0,
+  cp_loss INT NOT NULL DEFAULT 0,
+  class TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (game_id, ply)
+);
+
 -- PvP chess results
 CREATE TABLE IF NOT EXISTS pvp_games (
   game_id TEXT PRIMARY KEY,
@@ -62,3 +81,73 @@ CREATE TABLE IF NOT EXISTS pvp_games (
 
 CREATE INDEX IF NOT EXISTS idx_pvp_games_white ON pvp_games(white_id);
 CREATE INDEX IF NOT EXISTS idx_pvp_games_black ON pvp_games(black_id);
+
+-- PvP Elo ladder (keyed by Kakao user ID)
+ALTER TABLE pvp_games ADD COLUMN IF NOT EXISTS rated BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS pvp_ratings (
+  user_id TEXT PRIMARY KEY,
+  name TEXT NOT NULL DEFAULT '',
+  rating INT NOT NULL DEFAULT 1200,
+  games INT NOT NULL DEFAULT 0,
+  wins INT NOT NULL DEFAULT 0,
+  losses INT NOT NULL DEFAULT 0,
+  draws INT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pvp_ratings_rating ON pvp_ratings(rating DESC);
+CREATE INDEX IF NOT EXISTS idx_pvp_games_origin_room ON pvp_games(origin_room);
+CREATE INDEX IF NOT EXISTS idx_pvp_games_resolve_room ON pvp_games(resolve_room);
+
+-- PvP room tournaments (round robin / Swiss)
+CREATE TABLE IF NOT EXISTS pvp_tournaments (
+  id BIGSERIAL PRIMARY KEY,
+  room TEXT NOT NULL,
+  name TEXT NOT NULL,
+  format TEXT NOT NULL,          -- 'roundrobin' | 'swiss'
+  status TEXT NOT NULL,          -- 'REGISTERING' | 'RUNNING' | 'FINISHED' | 'CANCELLED'
+  rounds INT NOT NULL DEFAULT 0,
+  current_round INT NOT NULL DEFAULT 0,
+  creator_id TEXT NOT NULL,
+  creator_name TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  started_at TIMESTAMP NULL,
+  finished_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pvp_tournaments_room ON pvp_tournaments(room, id DESC);
+
+CREATE TABLE IF NOT EXISTS pvp_tournament_players (
+  tournament_id BIGINT NOT NULL REFERENCES pvp_tournaments(id) ON DELETE CASCADE,
+  user_id TEXT NOT NULL,
+  name TEXT NOT NULL DEFAULT '',
+  rating INT NOT NULL DEFAULT 1200,
+  joined_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (tournament_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS pvp_tournament_pairings (
+  tournament_id BIGINT NOT NULL REFERENCES pvp_tournaments(id) ON DELETE CASCADE,
+  round INT NOT NULL,
+  board INT NOT NULL,
+  white_id TEXT NOT NULL,
+  black_id TEXT NOT NULL DEFAULT '',  -- '' = bye
+  game_id TEXT NOT NULL DEFAULT '',   -- pvp_games.game_id once the board started
+  result TEXT NOT NULL DEFAULT '',    -- '' | '1-0' | '0-1' | '1/2-1/2' | 'bye'
+  PRIMARY KEY (tournament_id, round, board)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pvp_tournament_pairings_game ON pvp_tournament_pairings(game_id) WHERE game_id <> '';
+
+-- Correspondence PvP games: ACTIVE game state survives Redis restarts (row removed when the game ends)
+CREATE TABLE IF NOT EXISTS pvp_correspondence_games (
+  game_id TEXT PRIMARY KEY,
+  white_id TEXT NOT NULL,
+  black_id TEXT NOT NULL,
+  state JSONB NOT NULL,          -- pvpchess.Game JSON
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pvp_correspondence_white ON pvp_correspondence_games(white_id);
+CREATE INDEX IF NOT EXISTS idx_pvp_correspondence_black ON pvp_correspondence_games(black_id);
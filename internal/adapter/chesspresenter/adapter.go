@@ -27,6 +27,8 @@ func ToDTOState(s *svc.SessionState) *chessdto.SessionState {
         Outcome:     s.Outcome.String(),
         OutcomeMeta: s.OutcomeMethod.String(),
         GameID:      0,
+        PlayerColor: s.PlayerColor,
+        StartFEN:    s.StartFEN,
     }
 }
 
@@ -145,6 +147,8 @@ func ToDTOGames(list []*domain.ChessGame) []*chessdto.ChessGame {
             Duration:      gg.Duration,
             Blunders:      gg.Blunders,
             EngineLatency: gg.EngineLatency,
+            StartFEN:      gg.StartFEN,
+            Rated:         gg.Rated,
         })
     }
     return out
@@ -170,5 +174,43 @@ func ToDTOGame(g *domain.ChessGame) *chessdto.ChessGame {
         Duration:      gg.Duration,
         Blunders:      gg.Blunders,
         EngineLatency: gg.EngineLatency,
+        StartFEN:      gg.StartFEN,
+        Rated:         gg.Rated,
     }
 }
+
+func ToDTOAnalysis(r *svc.AnalysisReport) *chessdto.GameAnalysis {
+    if r == nil || r.Analysis == nil {
+        return nil
+    }
+    out := &chessdto.GameAnalysis{
+        GameID:      r.Analysis.GameID,
+        Depth:       r.Analysis.Depth,
+        Accuracy:    r.Analysis.Accuracy,
+        PlayerColor: r.PlayerColor,
+        Counts:      make(map[string]int, len(r.Counts)),
+        Moves:       make([]chessdto.MoveEvaluation, 0, len(r.Analysis.Moves)),
+        WorstSAN:    r.WorstSAN,
+        BestSAN:     r.BestSAN,
+        BoardImage:  append([]byte(nil), r.BoardImage...),
+    }
+    for k, v := range r.Counts {
+        out.Counts[k] = v
+    }
+    for _, mv := range r.Analysis.Moves {
+        out.Moves = append(out.Moves, chessdto.MoveEvaluation{
+            Ply:      mv.Ply,
+            MoveUCI:  mv.MoveUCI,
+            BestMove: mv.BestMove,
+            EvalCP:   mv.EvalCP,
+            CPLoss:   mv.CPLoss,
+            Class:    mv.Class,
+        })
+    }
+    if w := r.Worst; w != nil {
+        out.WorstPly = w.Ply
+        out.WorstCPLoss = w.CPLoss
+        out.WorstClass = w.Class
+    }
+    return out
+}
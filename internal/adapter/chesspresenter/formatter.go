@@ -34,8 +34,8 @@ const (
 		"• %s 기권\n" +
 		"  PvP 대국에서 기권\n" +
 		"\n" +
-		"• %s 시작 [난이도]\n" +
-		"  새 게임 시작 (난이도: level1~level8)\n" +
+		"• %s 시작 [난이도] [백|흑|랜덤]\n" +
+		"  새 게임 시작 (난이도: level1~level8, 진영 기본: 백)\n" +
 		"• %s <수> (예: e2e4)\n" +
 		"  SAN/UCI 모두 입력 가능\n" +
 		"• %s 기권\n" +
@@ -46,6 +46,7 @@ const (
 		"  최근 기보 확인 (기본 10개)\n" +
 		"• %s 기보 <ID>\n" +
 		"  특정 기보 상세 보기\n" +
+
 		"• %s 프로필\n" +
 		"  개인 승률·레이팅 확인"
 )
@@ -59,6 +60,7 @@ type PrefixProvider interface {
 type Formatter struct {
 	prefixProvider PrefixProvider
 	catalog        *msgcat.Catalog
+	helpBody       func() string
 }
 
 func NewFormatter(provider PrefixProvider) *Formatter {
@@ -75,6 +77,9 @@ func (f *Formatter) Prefix() string {
 // SetCatalog sets a per-formatter message catalog (optional).
 func (f *Formatter) SetCatalog(cat *msgcat.Catalog) { f.catalog = cat }
 
+// SetHelpBody sets where the help body comes from (명령 레지스트리가 등록된 명령으로 생성).
+func (f *Formatter) SetHelpBody(fn func() string) { f.helpBody = fn }
+
 // defaultCatalog is used when a formatter doesn't have a catalog assigned.
 var defaultCatalog *msgcat.Catalog
 
@@ -116,6 +121,8 @@ func (f *Formatter) Start(state *chessdto.SessionState, resumed bool) string {
 		"Preset":            formatPreset(state.Preset),
 		"ProfileRatingLine": ratingLine,
 		"ProfileRecordLine": recordLine,
+		"PlayerSide":        formatPlayerSide(state.PlayerColor),
+		"CustomStart":       state.StartFEN != "",
 		"Prefix":            prefix,
 	}); err == nil && strings.TrimSpace(body) != "" {
 		return body
@@ -134,7 +141,11 @@ func (f *Formatter) Start(state *chessdto.SessionState, resumed bool) string {
 	if recordLine != "" {
 		sb.WriteString(recordLine + "\n")
 	}
-	sb.WriteString("• 플레이어는 백으로 시작합니다.\n\n")
+	sb.WriteString("• 플레이어는 " + formatPlayerSide(state.PlayerColor) + "으로 시작합니다.\n")
+	if state.StartFEN != "" {
+		sb.WriteString("• 지정 포지션에서 시작 (레이팅 미반영)\n")
+	}
+	sb.WriteString("\n")
 	sb.WriteString("이동 방법: `" + prefix + " <수>`.\n")
 	sb.WriteString("무르기 기능: `" + prefix + " 무르기`.\n")
 	sb.WriteString("난이도 선택: level1~level8.")
@@ -161,7 +172,7 @@ func (f *Formatter) Move(summary *chessdto.MoveSummary) string {
 		return ""
 	}
 	state := summary.State
-	outcomeText := formatOutcome(state.Outcome, state.OutcomeMeta)
+	outcomeText := formatOutcome(state.Outcome, state.OutcomeMeta, state.PlayerColor)
 	preset := formatPreset(state.Preset)
 	var ratingLine, recordLine, gameIDLine string
 	if profile := summary.Profile; profile != nil {
@@ -272,7 +283,7 @@ func (f *Formatter) Resign(state *chessdto.SessionState) string {
 	outcome := ""
 	profileInfo := ""
 	if state != nil {
-		outcome = formatOutcome(state.Outcome, state.OutcomeMeta)
+		outcome = formatOutcome(state.Outcome, state.OutcomeMeta, state.PlayerColor)
 		profileInfo = formatProfileSummary(state.Profile, state.RatingDelta)
 	}
 	cat := f.catalog
@@ -300,14 +311,12 @@ func (f *Formatter) Resign(state *chessdto.SessionState) string {
 }
 
 func (f *Formatter) Help() string {
-	// YAML-only: must come from catalog. No hardcoded fallback.
-	prefix := f.Prefix()
-	cat := f.catalog
-	if cat == nil {
-		cat = defaultCatalog
+	// 본문은 명령별 help.* 항목을 모은 것(SetHelpBody). No hardcoded fallback.
+	if f.helpBody == nil {
+		return ""
 	}
-	body, err := cat.Render("help.korean", map[string]string{"Prefix": prefix})
-	if err != nil {
+	body := f.helpBody()
+	if strings.TrimSpace(body) == "" {
 		return ""
 	}
 	return util.ApplySeeMoreWithHeader(body, chessHelpInstruction, chessSeeMoreInstructionFallback, chessSeeMoreInstructionSuffix)
@@ -376,6 +385,9 @@ func (f *Formatter) Game(game *chessdto.ChessGame) string {
 	if game.Blunders > 0 {
 		sb.WriteString(fmt.Sprintf("• 블런더: %d회\n", game.Blunders))
 	}
+	if game.StartFEN != "" {
+		sb.WriteString("• 지정 포지션에서 시작 (레이팅 미반영)\n")
+	}
 	if game.PGN != "" {
 		sb.WriteString("\n```pgn\n")
 		sb.WriteString(strings.TrimSpace(game.PGN))
@@ -384,6 +396,64 @@ func (f *Formatter) Game(game *chessdto.ChessGame) string {
 	return sb.String()
 }
 
+func (f *Formatter) Analysis(a *chessdto.GameAnalysis) string {
+	if a == nil {
+		return "분석 정보를 불러오지 못했습니다."
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔍 대국 분석 #%d (깊이 %d)\n", a.GameID, a.Depth))
+	sb.WriteString(fmt.Sprintf("• 진영: %s\n", formatPlayerSide(a.PlayerColor)))
+	sb.WriteString(fmt.Sprintf("• 정확도: %.1f%%\n", a.Accuracy))
+	sb.WriteString(fmt.Sprintf("• 최선 %d | 좋음 %d | 부정확 %d | 실수 %d | 블런더 %d\n",
+		a.Counts["best"], a.Counts["good"], a.Counts["inaccuracy"], a.Counts["mistake"], a.Counts["blunder"]))
+	if a.WorstPly > 0 && a.WorstCPLoss > 0 {
+		move := a.WorstSAN
+		if move == "" {
+			move = findMoveUCI(a.Moves, a.WorstPly)
+		}
+		sb.WriteString(fmt.Sprintf("• 최악의 수: %s%s (%s, -%.1f)\n", formatMoveNumber(a.WorstPly), move, formatMoveClass(a.WorstClass), float64(a.WorstCPLoss)/100))
+		if a.BestSAN != "" {
+			sb.WriteString(fmt.Sprintf("  더 나은 수: %s (보드의 초록 화살표)\n", a.BestSAN))
+		}
+	} else {
+		sb.WriteString("• 큰 실수 없이 두었습니다. 👏\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func formatMoveNumber(ply int) string {
+	if ply%2 == 1 {
+		return fmt.Sprintf("%d. ", (ply+1)/2)
+	}
+	return fmt.Sprintf("%d... ", ply/2)
+}
+
+func formatMoveClass(class string) string {
+	switch strings.ToLower(strings.TrimSpace(class)) {
+	case "best":
+		return "최선"
+	case "good":
+		return "좋음"
+	case "inaccuracy":
+		return "부정확"
+	case "mistake":
+		return "실수"
+	case "blunder":
+		return "블런더"
+	default:
+		return "-"
+	}
+}
+
+func findMoveUCI(moves []chessdto.MoveEvaluation, ply int) string {
+	for _, mv := range moves {
+		if mv.Ply == ply {
+			return mv.MoveUCI
+		}
+	}
+	return ""
+}
+
 func (f *Formatter) Profile(profile *chessdto.ChessProfile) string {
 	if profile == nil {
 		return "저장된 체스 프로필이 없습니다."
@@ -554,20 +624,37 @@ func formatRecentMoves(moves []string) string {
 	return "… " + strings.Join(moves[len(moves)-limit:], " ")
 }
 
-func formatOutcome(outcome, method string) string {
+// formatOutcome renders the result from the player's side (playerColor 비어 있으면 백).
+func formatOutcome(outcome, method, playerColor string) string {
+	winner := ""
 	switch strings.ToLower(strings.TrimSpace(outcome)) {
-	case "white_won":
-		return "✅ 승리했습니다! 축하드립니다."
-	case "black_won":
-		if strings.ToLower(strings.TrimSpace(method)) == "resignation" {
-			return "🛑 기권하여 패배로 기록되었습니다."
-		}
-		return "❌ 패배했습니다. 다음엔 더 나은 수를 준비해봐요."
-	case "draw":
+	case "white_won", "1-0":
+		winner = "white"
+	case "black_won", "0-1":
+		winner = "black"
+	case "draw", "1/2-1/2":
 		return "🤝 무승부로 종료되었습니다."
 	default:
 		return "게임이 종료되었습니다."
 	}
+	player := strings.ToLower(strings.TrimSpace(playerColor))
+	if player == "" {
+		player = "white"
+	}
+	if winner == player {
+		return "✅ 승리했습니다! 축하드립니다."
+	}
+	if strings.ToLower(strings.TrimSpace(method)) == "resignation" {
+		return "🛑 기권하여 패배로 기록되었습니다."
+	}
+	return "❌ 패배했습니다. 다음엔 더 나은 수를 준비해봐요."
+}
+
+func formatPlayerSide(playerColor string) string {
+	if strings.ToLower(strings.TrimSpace(playerColor)) == "black" {
+		return "흑"
+	}
+	return "백"
 }
 
 func formatResultBadge(result string) string {
@@ -1,42 +1,306 @@
 package chesspresenter
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irisfast"
 	"github.com/park285/Cheese-KakaoTalk-bot/pkg/chessdto"
+	xdraw "golang.org/x/image/draw"
 )
 
+// boardRetryScales are tried in order when a board image fails to send; after them comes the text board.
+var boardRetryScales = []float64{0.75, 0.5}
+
+// boardLedgerMax bounds how many delivered boards are remembered (게임 × 시점).
+const boardLedgerMax = 256
+
+// BoardVia tells how a board reached the room.
+type BoardVia string
+
+const (
+	BoardViaNone  BoardVia = ""      // 보드 없음 또는 전달 실패
+	BoardViaImage BoardVia = "image" // 원본 이미지
+	BoardViaSmall BoardVia = "small" // 축소 재인코딩 이미지
+	BoardViaText  BoardVia = "text"  // 텍스트 보드
+)
+
+// BoardDelivery is the outcome of DeliverBoard. Sends lists every send in order, fallbacks included.
+// Err is set when the message failed or the board could not be delivered in any form.
+type BoardDelivery struct {
+	Room  string
+	Via   BoardVia
+	Sends []irisfast.SendResult
+	Err   error
+}
+
+// Fallback reports whether the board arrived in a degraded form.
+func (d BoardDelivery) Fallback() bool { return d.Via == BoardViaSmall || d.Via == BoardViaText }
+
 // Presenter delivers formatted messages and board images without coupling to the command layer.
 type Presenter struct {
-	sendMessage func(room, message string) error
-	sendImage   func(room, imageBase64 string) error
+	send   func(ctx context.Context, room, kind, data string) irisfast.SendResult
+	ledger *boardLedger
 }
 
 func NewPresenter(sendMessage func(room, message string) error, sendImage func(room, imageBase64 string) error) *Presenter {
-	return &Presenter{
-		sendMessage: sendMessage,
-		sendImage:   sendImage,
-	}
+	return newPresenter(func(_ context.Context, room, kind, data string) irisfast.SendResult {
+		fn := sendMessage
+		if kind == "image" {
+			fn = sendImage
+		}
+		res := irisfast.SendResult{Room: room, Kind: kind, Bytes: len(data)}
+		if fn != nil {
+			res.Attempts = 1
+			res.Err = fn(room, data)
+		}
+		return res
+	})
+}
+
+// NewEgressPresenter sends through eg, keeping the egress's own results (재시도 횟수 등) in BoardDelivery.
+func NewEgressPresenter(eg irisfast.Egress) *Presenter {
+	return newPresenter(func(ctx context.Context, room, kind, data string) irisfast.SendResult {
+		return irisfast.Deliver(ctx, eg, room, kind, data)
+	})
+}
+
+func newPresenter(send func(ctx context.Context, room, kind, data string) irisfast.SendResult) *Presenter {
+	return &Presenter{send: send, ledger: &boardLedger{entries: make(map[string]*ledgerEntry)}}
 }
 
 func (p *Presenter) Board(room, message string, state *chessdto.SessionState) error {
+	return p.DeliverBoard(room, message, state).Err
+}
+
+// DeliverBoard sends an optional message followed by the board image. 이미지 전송이 (큐 재시도 후에도)
+// 실패하면 더 작게 다시 인코딩해 보내고, 그래도 안 되면 FEN으로 만든 텍스트 보드를 보냅니다.
+// 대체 전송은 실패가 미전달이 확실하거나 크기 초과 거절일 때만 하며, 그 밖의 오류는 이미지가 이미
+// 올라갔을 수 있으므로 그대로 돌려줍니다. 이미지가 전달되면 LastBoard로 다시 보낼 수 있도록 기록합니다.
+func (p *Presenter) DeliverBoard(room, message string, state *chessdto.SessionState) BoardDelivery {
+	d := BoardDelivery{Room: room}
 	if p == nil {
-		return nil
+		return d
+	}
+	ctx := context.Background()
+	if strings.TrimSpace(message) != "" {
+		res := p.send(ctx, room, "text", message)
+		d.Sends = append(d.Sends, res)
+		if res.Err != nil {
+			d.Err = res.Err
+			return d
+		}
+	}
+	if state == nil || len(state.BoardImage) == 0 {
+		return d
 	}
 
-	if text := strings.TrimSpace(message); text != "" && p.sendMessage != nil {
-		if err := p.sendMessage(room, message); err != nil {
-			return err
+	img, via := state.BoardImage, BoardViaImage
+	for i := 0; ; i++ {
+		res := p.send(ctx, room, "image", base64.StdEncoding.EncodeToString(img))
+		d.Sends = append(d.Sends, res)
+		if res.Err == nil {
+			d.Via, d.Err = via, nil
+			p.ledger.record(state, img)
+			return d
+		}
+		d.Err = res.Err
+		if !safeToResend(res.Err) {
+			return d
+		}
+		if i >= len(boardRetryScales) {
+			break
+		}
+		small, err := shrinkPNG(state.BoardImage, boardRetryScales[i])
+		if err != nil {
+			break
 		}
+		img, via = small, BoardViaSmall
 	}
 
-	if state != nil && len(state.BoardImage) > 0 && p.sendImage != nil {
-		encoded := base64.StdEncoding.EncodeToString(state.BoardImage)
-		if err := p.sendImage(room, encoded); err != nil {
-			return err
+	if board := TextBoard(state); board != "" {
+		res := p.send(ctx, room, "text", textBoardMessage(board))
+		d.Sends = append(d.Sends, res)
+		if res.Err == nil {
+			d.Via, d.Err = BoardViaText, nil
 		}
 	}
+	return d
+}
+
+// safeToResend reports whether a failed image send can be followed by a smaller image or the text
+// board without posting the board twice (same classification as the egress queue's retries).
+func safeToResend(err error) bool {
+	return irisfast.Undelivered(err) || irisfast.TooLarge(err)
+}
 
+// Image sends an optional message followed by an arbitrary PNG (e.g. an evaluation chart).
+func (p *Presenter) Image(room, message string, image []byte) error {
+	if p == nil {
+		return nil
+	}
+	ctx := context.Background()
+	if strings.TrimSpace(message) != "" {
+		if res := p.send(ctx, room, "text", message); res.Err != nil {
+			return res.Err
+		}
+	}
+	if len(image) > 0 {
+		if res := p.send(ctx, room, "image", base64.StdEncoding.EncodeToString(image)); res.Err != nil {
+			return res.Err
+		}
+	}
 	return nil
 }
+
+// LastBoard returns the last board image delivered for a game/session seen from color ("white"|"black")
+// if it still shows the position after moves, so the board can be resent without re-rendering.
+func (p *Presenter) LastBoard(id, color string, moves []string) ([]byte, bool) {
+	if p == nil {
+		return nil, false
+	}
+	return p.ledger.lookup(id, color, moves)
+}
+
+// TextBoard draws state's position (FEN) as an 8x8 text grid from the player's side; "" if FEN is unusable.
+// 대문자 백, 소문자 흑, 빈칸은 점.
+func TextBoard(state *chessdto.SessionState) string {
+	if state == nil {
+		return ""
+	}
+	placement, _, _ := strings.Cut(strings.TrimSpace(state.FEN), " ")
+	ranks := strings.Split(placement, "/")
+	if len(ranks) != 8 {
+		return ""
+	}
+	var grid [8][8]byte
+	for r, rank := range ranks {
+		f := 0
+		for i := 0; i < len(rank); i++ {
+			ch := rank[i]
+			if ch >= '1' && ch <= '8' {
+				for n := 0; n < int(ch-'0') && f < 8; n++ {
+					grid[r][f] = '.'
+					f++
+				}
+				continue
+			}
+			if f >= 8 || !strings.ContainsRune("prnbqkPRNBQK", rune(ch)) {
+				return ""
+			}
+			grid[r][f] = ch
+			f++
+		}
+		if f != 8 {
+			return ""
+		}
+	}
+
+	flip := strings.EqualFold(strings.TrimSpace(state.PlayerColor), "black")
+	files := "a b c d e f g h"
+	if flip {
+		files = "h g f e d c b a"
+	}
+	lines := make([]string, 0, 9)
+	for i := 0; i < 8; i++ {
+		r := i
+		if flip {
+			r = 7 - i
+		}
+		cells := make([]string, 8)
+		for j := 0; j < 8; j++ {
+			f := j
+			if flip {
+				f = 7 - j
+			}
+			cells[j] = string(grid[r][f])
+		}
+		lines = append(lines, fmt.Sprintf("%d %s", 8-r, strings.Join(cells, " ")))
+	}
+	lines = append(lines, "  "+files)
+	return strings.Join(lines, "\n")
+}
+
+func textBoardMessage(board string) string {
+	if defaultCatalog != nil {
+		if txt, err := defaultCatalog.Render("board.text_fallback", map[string]string{"Board": board}); err == nil {
+			return txt
+		}
+	}
+	return "보드 이미지를 보내지 못해 텍스트로 보여드립니다.\n" + board
+}
+
+// shrinkPNG re-encodes a PNG at scale with maximum compression.
+func shrinkPNG(data []byte, scale float64) ([]byte, error) {
+	src, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode board png: %w", err)
+	}
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, int(float64(b.Dx())*scale), int(float64(b.Dy())*scale)))
+	xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), src, b, xdraw.Src, nil)
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := enc.Encode(&buf, dst); err != nil {
+		return nil, fmt.Errorf("encode board png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// boardLedger remembers the last delivered board image per game and side (프로세스 메모리, 재시작 시 비움).
+type boardLedger struct {
+	mu      sync.Mutex
+	entries map[string]*ledgerEntry
+}
+
+type ledgerEntry struct {
+	moves string
+	image []byte
+	at    time.Time
+}
+
+func ledgerKey(id, color string) string {
+	if strings.TrimSpace(color) == "" {
+		color = "white"
+	}
+	return strings.TrimSpace(id) + "|" + strings.ToLower(strings.TrimSpace(color))
+}
+
+func (l *boardLedger) record(state *chessdto.SessionState, image []byte) {
+	if strings.TrimSpace(state.SessionUUID) == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[ledgerKey(state.SessionUUID, state.PlayerColor)] = &ledgerEntry{
+		moves: strings.Join(state.MovesUCI, " "),
+		image: append([]byte(nil), image...),
+		at:    time.Now(),
+	}
+	for len(l.entries) > boardLedgerMax {
+		oldest := ""
+		for k, e := range l.entries {
+			if oldest == "" || e.at.Before(l.entries[oldest].at) {
+				oldest = k
+			}
+		}
+		delete(l.entries, oldest)
+	}
+}
+
+func (l *boardLedger) lookup(id, color string, moves []string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := l.entries[ledgerKey(id, color)]
+	if e == nil || e.moves != strings.Join(moves, " ") {
+		return nil, false
+	}
+	return e.image, true
+}
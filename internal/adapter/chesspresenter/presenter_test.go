@@ -0,0 +1,124 @@
+package chesspresenter
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irisfast"
+	"github.com/park285/Cheese-KakaoTalk-bot/pkg/chessdto"
+)
+
+const afterE4FEN = "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 64, 64))); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// recorder fails the first failImages image sends (or all of them when failImages < 0) with failErr,
+// a size rejection by default.
+type recorder struct {
+	failImages int
+	failErr    error
+	texts      []string
+	images     int
+}
+
+func (r *recorder) presenter() *Presenter {
+	return NewPresenter(
+		func(_, message string) error {
+			r.texts = append(r.texts, message)
+			return nil
+		},
+		func(_, _ string) error {
+			r.images++
+			if r.failImages < 0 || r.images <= r.failImages {
+				if r.failErr != nil {
+					return r.failErr
+				}
+				return &irisfast.StatusError{Code: http.StatusRequestEntityTooLarge}
+			}
+			return nil
+		},
+	)
+}
+
+func TestDeliverBoardShrinksThenRecords(t *testing.T) {
+	rec := &recorder{failImages: 1}
+	p := rec.presenter()
+	state := &chessdto.SessionState{SessionUUID: "g1", FEN: afterE4FEN, MovesUCI: []string{"e2e4"}, BoardImage: testPNG(t), PlayerColor: "black"}
+
+	d := p.DeliverBoard("r1", "♟️ 현황", state)
+	if d.Err != nil || d.Via != BoardViaSmall || len(d.Sends) != 3 {
+		t.Fatalf("delivery = %+v", d)
+	}
+	img, ok := p.LastBoard("g1", "black", []string{"e2e4"})
+	if !ok || bytes.Equal(img, state.BoardImage) {
+		t.Fatalf("ledger should hold the shrunk image (ok=%v)", ok)
+	}
+	if _, ok := p.LastBoard("g1", "black", []string{"e2e4", "e7e5"}); ok {
+		t.Fatal("stale position must not be reused")
+	}
+	if _, ok := p.LastBoard("g1", "white", []string{"e2e4"}); ok {
+		t.Fatal("other side must not be reused")
+	}
+}
+
+func TestDeliverBoardFallsBackToText(t *testing.T) {
+	rec := &recorder{failImages: -1}
+	p := rec.presenter()
+	state := &chessdto.SessionState{SessionUUID: "g1", FEN: afterE4FEN, BoardImage: testPNG(t)}
+
+	d := p.DeliverBoard("r1", "", state)
+	if d.Err != nil || d.Via != BoardViaText || rec.images != 1+len(boardRetryScales) {
+		t.Fatalf("delivery = %+v images = %d", d, rec.images)
+	}
+	if len(rec.texts) != 1 || !strings.Contains(rec.texts[0], "4 . . . . P . . .") {
+		t.Fatalf("texts = %q", rec.texts)
+	}
+	if _, ok := p.LastBoard("g1", "white", nil); ok {
+		t.Fatal("text fallback must not be recorded as a delivered image")
+	}
+}
+
+func TestDeliverBoardFallsBackOnlyWhenUndelivered(t *testing.T) {
+	state := &chessdto.SessionState{SessionUUID: "g1", FEN: afterE4FEN, BoardImage: testPNG(t)}
+
+	rec := &recorder{failImages: 1, failErr: &irisfast.StatusError{Code: http.StatusServiceUnavailable}}
+	if d := rec.presenter().DeliverBoard("r1", "", state); d.Err != nil || d.Via != BoardViaSmall {
+		t.Fatalf("undelivered image should be resent smaller: %+v", d)
+	}
+
+	// 타임아웃 등은 이미 올라갔을 수 있으므로 축소·텍스트 대체 없이 오류를 돌려줌
+	timeout := errors.New("request failed: timeout")
+	rec = &recorder{failImages: -1, failErr: timeout}
+	d := rec.presenter().DeliverBoard("r1", "", state)
+	if !errors.Is(d.Err, timeout) || d.Via != BoardViaNone || rec.images != 1 || len(rec.texts) != 0 {
+		t.Fatalf("delivery = %+v images = %d texts = %q", d, rec.images, rec.texts)
+	}
+}
+
+func TestTextBoard(t *testing.T) {
+	white := TextBoard(&chessdto.SessionState{FEN: afterE4FEN})
+	lines := strings.Split(white, "\n")
+	if len(lines) != 9 || lines[0] != "8 r n b q k b n r" || lines[8] != "  a b c d e f g h" {
+		t.Fatalf("white board:\n%s", white)
+	}
+	black := TextBoard(&chessdto.SessionState{FEN: afterE4FEN, PlayerColor: "black"})
+	lines = strings.Split(black, "\n")
+	if lines[0] != "1 R N B K Q B N R" || lines[3] != "4 . . . P . . . ." || lines[8] != "  h g f e d c b a" {
+		t.Fatalf("black board:\n%s", black)
+	}
+	if TextBoard(&chessdto.SessionState{FEN: "garbage"}) != "" {
+		t.Fatal("invalid FEN should yield no board")
+	}
+}
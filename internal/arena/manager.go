@@ -0,0 +1,221 @@
+package arena
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ttlChallengeLock: 동시에 두 도전이 같은 타이틀전을 만들지 않도록 잡는 짧은 잠금
+const ttlChallengeLock = 15 * time.Second
+
+// Manager keeps one champion per room ("챔피언전").
+// 타이틀전은 일반 pvpchess 대국이며, 결과는 pvpchess.Manager.OnResult 훅에서 IngestResult로 반영됩니다.
+type Manager struct {
+	rdb *redis.Client
+	pvp *pvpchess.Manager
+}
+
+func NewManager(rdb *redis.Client, pvp *pvpchess.Manager) *Manager {
+	return &Manager{rdb: rdb, pvp: pvp}
+}
+
+func keyReign(room string) string  { return "arena:" + strings.TrimSpace(room) }
+func keyRecord(room string) string { return keyReign(room) + ":best" }
+func keyLock(room string) string   { return keyReign(room) + ":lock" }
+
+// Status returns the room's reign (nil when the title is vacant) and best record (nil when none).
+func (m *Manager) Status(ctx context.Context, room string) (*Reign, *Record, error) {
+	if strings.TrimSpace(room) == "" {
+		return nil, nil, ErrInvalidArgs
+	}
+	r, err := m.loadReign(ctx, m.rdb, room)
+	if err != nil {
+		return nil, nil, err
+	}
+	rec, err := m.loadRecord(ctx, room)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, rec, nil
+}
+
+// Challenge claims the vacant title or starts a title game against the reigning champion.
+// 챔피언과 도전자 모두 이 방에서 다른 대국 중이 아니어야 하며, 타이틀전은 한 번에 하나만 진행됩니다.
+func (m *Manager) Challenge(ctx context.Context, room, userID, userName string) (*ChallengeResult, error) {
+	room, userID, userName = strings.TrimSpace(room), strings.TrimSpace(userID), strings.TrimSpace(userName)
+	if room == "" || userID == "" {
+		return nil, ErrInvalidArgs
+	}
+	ok, err := m.rdb.SetNX(ctx, keyLock(room), userID, ttlChallengeLock).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTitleGamePending
+	}
+	defer m.rdb.Del(context.Background(), keyLock(room))
+
+	r, err := m.loadReign(ctx, m.rdb, room)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		r = &Reign{Room: room, ChampionID: userID, ChampionName: userName, Since: time.Now()}
+		if err := m.saveReign(ctx, r); err != nil {
+			return nil, err
+		}
+		obslog.L().Info("arena_claim", zap.String("room", room), zap.String("champion_id", userID))
+		return &ChallengeResult{Claimed: true, Reign: r}, nil
+	}
+	if r.ChampionID == userID {
+		return nil, ErrAlreadyChampion
+	}
+	if r.GameID != "" {
+		// 결과 반영 전에 대국이 사라졌거나 끝났다면 타이틀전 표시만 정리
+		if g, _ := m.pvp.LoadGame(ctx, r.GameID); g != nil && g.Status == pvpchess.StatusActive {
+			return nil, ErrTitleGamePending
+		}
+		r.GameID, r.ChallengerID, r.ChallengerName = "", "", ""
+	}
+	if g, _ := m.pvp.GetActiveGameByUserInRoom(ctx, userID, room); g != nil {
+		return nil, ErrPlayerBusy
+	}
+	if g, _ := m.pvp.GetActiveGameByUserInRoom(ctx, r.ChampionID, room); g != nil {
+		return nil, ErrChampionBusy
+	}
+	g, err := m.pvp.CreateGameFromChallenge(ctx, room, room, userID, userName, r.ChampionID, r.ChampionName, "random", "")
+	if err != nil {
+		return nil, err
+	}
+	r.GameID, r.ChallengerID, r.ChallengerName = g.ID, userID, userName
+	if err := m.saveReign(ctx, r); err != nil {
+		return nil, err
+	}
+	obslog.L().Info("arena_title_game", zap.String("room", room), zap.String("game_id", g.ID), zap.String("champion_id", r.ChampionID), zap.String("challenger_id", userID))
+	return &ChallengeResult{Reign: r, Game: g}, nil
+}
+
+// IngestResult applies a final pvpchess game to the room's title if it is the current title game.
+// 챔피언 승리·무승부는 방어, 도전자 승리는 타이틀 이동, 2수 미만 취소(ABORTED)는 무효입니다.
+// 타이틀전이 아니면 nil을 반환합니다.
+func (m *Manager) IngestResult(ctx context.Context, g *pvpchess.Game) (*Event, error) {
+	if g == nil || strings.TrimSpace(g.OriginRoom) == "" {
+		return nil, nil
+	}
+	room := g.OriginRoom
+	var ev *Event
+	err := m.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		ev = nil
+		r, err := m.loadReign(ctx, tx, room)
+		if err != nil || r == nil || r.GameID != g.ID {
+			return err
+		}
+		challengerID, challengerName := r.ChallengerID, r.ChallengerName
+		r.GameID, r.ChallengerID, r.ChallengerName = "", "", ""
+		switch winner := titleWinner(g); {
+		case g.Status == pvpchess.StatusAborted:
+			ev = &Event{Kind: EventVoided, Reign: r, Game: g}
+		case winner == challengerID:
+			prev := *r
+			r = &Reign{Room: room, ChampionID: challengerID, ChampionName: challengerName, Since: time.Now()}
+			ev = &Event{Kind: EventNewChampion, Reign: r, Previous: &prev, Game: g}
+		default:
+			r.Defenses++
+			ev = &Event{Kind: EventDefended, Draw: winner == "", Reign: r, Game: g}
+		}
+		raw, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, keyReign(room), raw, 0)
+			return nil
+		})
+		return err
+	}, keyReign(room))
+	if err != nil || ev == nil {
+		return nil, err
+	}
+	if ev.Kind == EventDefended {
+		if err := m.updateRecord(ctx, ev.Reign); err != nil {
+			obslog.L().Warn("arena_record_error", zap.String("room", room), zap.Error(err))
+		}
+	}
+	obslog.L().Info("arena_result", zap.String("room", room), zap.String("game_id", g.ID), zap.String("kind", string(ev.Kind)), zap.String("champion_id", ev.Reign.ChampionID), zap.Int("defenses", ev.Reign.Defenses))
+	return ev, nil
+}
+
+// titleWinner returns the winning user id, or "" for a draw.
+func titleWinner(g *pvpchess.Game) string {
+	switch {
+	case g.Winner != "":
+		return g.Winner
+	case g.Outcome == "white":
+		return g.WhiteID
+	case g.Outcome == "black":
+		return g.BlackID
+	}
+	return ""
+}
+
+// updateRecord replaces the room's best record when r has more defenses.
+func (m *Manager) updateRecord(ctx context.Context, r *Reign) error {
+	best, err := m.loadRecord(ctx, r.Room)
+	if err != nil {
+		return err
+	}
+	if best != nil && best.Defenses >= r.Defenses {
+		return nil
+	}
+	raw, err := json.Marshal(&Record{ChampionID: r.ChampionID, ChampionName: r.ChampionName, Defenses: r.Defenses, Since: r.Since})
+	if err != nil {
+		return err
+	}
+	return m.rdb.Set(ctx, keyRecord(r.Room), raw, 0).Err()
+}
+
+func (m *Manager) loadReign(ctx context.Context, c redis.Cmdable, room string) (*Reign, error) {
+	raw, err := c.Get(ctx, keyReign(room)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r Reign
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (m *Manager) saveReign(ctx context.Context, r *Reign) error {
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return m.rdb.Set(ctx, keyReign(r.Room), raw, 0).Err()
+}
+
+func (m *Manager) loadRecord(ctx context.Context, room string) (*Record, error) {
+	raw, err := m.rdb.Get(ctx, keyRecord(room)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec Record
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
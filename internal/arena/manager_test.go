@@ -0,0 +1,108 @@
+package arena
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestManager(t *testing.T) (*Manager, *pvpchess.Manager) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis: %v", err)
+	}
+	t.Cleanup(func() { mr.Close() })
+	pvp, err := pvpchess.NewManager(fmt.Sprintf("redis://%s/0", mr.Addr()))
+	if err != nil {
+		t.Fatalf("pvpchess.NewManager: %v", err)
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return NewManager(rdb, pvp), pvp
+}
+
+func TestArenaTitleChangesHands(t *testing.T) {
+	m, pvp := newTestManager(t)
+	ctx := context.Background()
+
+	res, err := m.Challenge(ctx, "room", "u1", "U1")
+	if err != nil || !res.Claimed || res.Reign.ChampionID != "u1" {
+		t.Fatalf("expected u1 to claim the vacant title: %+v %v", res, err)
+	}
+	if _, err := m.Challenge(ctx, "room", "u1", "U1"); err != ErrAlreadyChampion {
+		t.Fatalf("expected ErrAlreadyChampion, got %v", err)
+	}
+
+	// 1차 도전: 도전자 기권 → 방어 1회
+	res, err = m.Challenge(ctx, "room", "u2", "U2")
+	if err != nil || res.Claimed || res.Game == nil {
+		t.Fatalf("expected title game: %+v %v", res, err)
+	}
+	if _, err := m.Challenge(ctx, "room", "u3", "U3"); err != ErrTitleGamePending {
+		t.Fatalf("expected ErrTitleGamePending, got %v", err)
+	}
+	g, _, err := pvp.ResignByRoom(ctx, "u2", "room")
+	if err != nil {
+		t.Fatalf("resign: %v", err)
+	}
+	ev, err := m.IngestResult(ctx, g)
+	if err != nil || ev == nil || ev.Kind != EventDefended || ev.Reign.Defenses != 1 {
+		t.Fatalf("expected defense, got %+v %v", ev, err)
+	}
+	if again, _ := m.IngestResult(ctx, g); again != nil {
+		t.Fatalf("result applied twice: %+v", again)
+	}
+
+	// 2차 도전: 챔피언 기권 → 타이틀 이동
+	res, err = m.Challenge(ctx, "room", "u3", "U3")
+	if err != nil {
+		t.Fatalf("challenge: %v", err)
+	}
+	g, _, err = pvp.ResignByRoom(ctx, "u1", "room")
+	if err != nil {
+		t.Fatalf("resign: %v", err)
+	}
+	ev, err = m.IngestResult(ctx, g)
+	if err != nil || ev.Kind != EventNewChampion || ev.Reign.ChampionID != "u3" || ev.Previous.ChampionID != "u1" || ev.Previous.Defenses != 1 {
+		t.Fatalf("expected u3 to take the title from u1, got %+v %v", ev, err)
+	}
+
+	reign, rec, err := m.Status(ctx, "room")
+	if err != nil || reign.ChampionID != "u3" || reign.Defenses != 0 || reign.GameID != "" {
+		t.Fatalf("unexpected reign: %+v %v", reign, err)
+	}
+	if rec == nil || rec.ChampionID != "u1" || rec.Defenses != 1 {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+}
+
+func TestArenaIgnoresOtherGamesAndBusyPlayers(t *testing.T) {
+	m, pvp := newTestManager(t)
+	ctx := context.Background()
+
+	if _, err := m.Challenge(ctx, "room", "u1", "U1"); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	side, err := pvp.CreateGameFromChallenge(ctx, "room", "room", "u1", "U1", "x", "X", "white", "")
+	if err != nil {
+		t.Fatalf("side game: %v", err)
+	}
+	if _, err := m.Challenge(ctx, "room", "u2", "U2"); err != ErrChampionBusy {
+		t.Fatalf("expected ErrChampionBusy, got %v", err)
+	}
+	g, _, err := pvp.ResignByRoom(ctx, "u1", "room")
+	if err != nil || g.ID != side.ID {
+		t.Fatalf("resign side game: %v", err)
+	}
+	if ev, err := m.IngestResult(ctx, g); err != nil || ev != nil {
+		t.Fatalf("non-title game should be ignored: %+v %v", ev, err)
+	}
+	if reign, _, _ := m.Status(ctx, "room"); reign.ChampionID != "u1" || reign.Defenses != 0 {
+		t.Fatalf("title changed by a non-title game: %+v", reign)
+	}
+}
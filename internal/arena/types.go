@@ -0,0 +1,68 @@
+package arena
+
+import (
+	"errors"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+)
+
+// Reign is the current champion of a room (arena:<room>).
+type Reign struct {
+	Room         string    `json:"room"`
+	ChampionID   string    `json:"champion_id"`
+	ChampionName string    `json:"champion_name"`
+	Since        time.Time `json:"since"`
+	Defenses     int       `json:"defenses"`
+
+	// 진행 중인 타이틀전 (없으면 빈 값)
+	GameID         string `json:"game_id,omitempty"`
+	ChallengerID   string `json:"challenger_id,omitempty"`
+	ChallengerName string `json:"challenger_name,omitempty"`
+}
+
+// Record is the room's best reign by number of defenses (arena:<room>:best).
+type Record struct {
+	ChampionID   string    `json:"champion_id"`
+	ChampionName string    `json:"champion_name"`
+	Defenses     int       `json:"defenses"`
+	Since        time.Time `json:"since"`
+}
+
+// ChallengeResult is returned by Challenge: either the vacant title was claimed or a title game started.
+type ChallengeResult struct {
+	Claimed bool
+	Reign   *Reign
+	Game    *pvpchess.Game
+}
+
+// EventKind describes how a title game ended.
+type EventKind string
+
+const (
+	// EventDefended: 챔피언 승리 또는 무승부 → 방어 횟수 +1
+	EventDefended EventKind = "defended"
+	// EventNewChampion: 도전자 승리 → 타이틀 이동
+	EventNewChampion EventKind = "new_champion"
+	// EventVoided: 2수 미만으로 취소된 대국 → 타이틀 변화 없음
+	EventVoided EventKind = "voided"
+)
+
+// Event is produced by IngestResult for a finished title game.
+type Event struct {
+	Kind  EventKind
+	Draw  bool
+	Reign *Reign
+	// Previous is the dethroned reign (EventNewChampion only), with its final defense count.
+	Previous *Reign
+	Game     *pvpchess.Game
+}
+
+// Errors surfaced to the command layer.
+var (
+	ErrInvalidArgs      = errors.New("invalid arguments")
+	ErrAlreadyChampion  = errors.New("already the champion")
+	ErrTitleGamePending = errors.New("a title game is already in progress")
+	ErrChampionBusy     = errors.New("champion is busy in another game")
+	ErrPlayerBusy       = errors.New("player is busy in another game")
+)
@@ -0,0 +1,93 @@
+package chess
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/chess/uci"
+)
+
+const (
+	defaultAnalysisDepth  = 14
+	analysisHashMB        = 64
+	analysisMaxSkillLevel = 20
+)
+
+// MateScoreCP is the centipawn value the engine reports for forced mates.
+const MateScoreCP = uci.MateScore
+
+// PositionEval is the engine's verdict on a single position.
+// EvalCP는 항상 백 기준(양수=백 우세)으로 정규화됩니다.
+type PositionEval struct {
+	BestMove string
+	EvalCP   int
+}
+
+// AnalyzeGame replays moves from startFEN ("" or "startpos" for the initial position) and
+// evaluates the starting position and the position after every ply at a fixed depth and full strength.
+// 반환 길이는 len(moves)+1 이며, 종국 포지션(수 없음)은 BestMove가 비고 EvalCP가 0입니다.
+func (e *Engine) AnalyzeGame(ctx context.Context, startFEN string, moves []string, depth int) ([]PositionEval, error) {
+	if e == nil || e.pool == nil {
+		return nil, fmt.Errorf("engine not initialized")
+	}
+	if depth <= 0 {
+		depth = defaultAnalysisDepth
+	}
+	session, err := e.pool.Acquire(ctx, analysisOptions())
+	if err != nil {
+		return nil, err
+	}
+	var releaseErr error
+	defer func() {
+		e.pool.Release(session, releaseErr)
+	}()
+
+	if err := session.NewGame(ctx); err != nil {
+		releaseErr = err
+		return nil, err
+	}
+
+	fen := strings.TrimSpace(startFEN)
+	if fen == "" {
+		fen = "startpos"
+	}
+	// 흑 차례로 시작하는 FEN이면 짝수 ply가 흑 차례
+	blackFirst := fen != "startpos" && len(strings.Fields(fen)) > 1 && strings.Fields(fen)[1] == "b"
+
+	out := make([]PositionEval, 0, len(moves)+1)
+	for ply := 0; ply <= len(moves); ply++ {
+		resp, err := session.Search(ctx, uci.SearchRequest{
+			FEN:    fen,
+			Moves:  moves[:ply],
+			Limits: uci.Limits{Depth: depth},
+		})
+		if err != nil {
+			releaseErr = err
+			return nil, fmt.Errorf("analyze ply %d: %w", ply, err)
+		}
+		pe := PositionEval{}
+		if best := strings.ToLower(strings.TrimSpace(resp.BestMove)); best != "" && best != "(none)" {
+			pe.BestMove = best
+		}
+		if len(resp.Candidates) > 0 {
+			// UCI 점수는 둘 차례 기준 → 흑 차례면 부호 반전
+			pe.EvalCP = resp.Candidates[0].EvalCP
+			if (ply%2 == 1) != blackFirst {
+				pe.EvalCP = -pe.EvalCP
+			}
+		}
+		out = append(out, pe)
+	}
+	return out, nil
+}
+
+func analysisOptions() uci.Options {
+	return uci.Options{
+		Threads:      1,
+		SkillLevel:   analysisMaxSkillLevel,
+		HashMB:       analysisHashMB,
+		MultiPV:      1,
+		FullStrength: true,
+	}
+}
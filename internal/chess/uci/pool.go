@@ -255,12 +255,13 @@ func (b *sessionBucket) decrement() {
 }
 
 func optionsKey(opt Options) string {
-	return fmt.Sprintf("thr=%d|skill=%d|hash=%d|multipv=%d|elo=%d",
+	return fmt.Sprintf("thr=%d|skill=%d|hash=%d|multipv=%d|elo=%d|full=%t",
 		opt.Threads,
 		opt.SkillLevel,
 		opt.HashMB,
 		opt.MultiPV,
-		opt.Elo)
+		opt.Elo,
+		opt.FullStrength)
 }
 
 func defaultPerPresetCapacity() int {
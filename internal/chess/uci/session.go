@@ -21,12 +21,17 @@ const (
 	newGameRetryDelay    = 150 * time.Millisecond
 )
 
+// MateScore is the centipawn value reported for "score mate N" lines.
+const MateScore = 30000
+
 type Options struct {
 	Threads    int
 	SkillLevel int
 	HashMB     int
 	MultiPV    int
 	Elo        int
+	// FullStrength disables UCI_LimitStrength (post-game analysis).
+	FullStrength bool
 }
 
 type Limits struct {
@@ -254,11 +259,10 @@ func parseInfo(line string) (int, Candidate, bool) {
 					}
 				case "mate":
 					if v, err := strconv.Atoi(val); err == nil {
-						const mateValue = 30000
 						if v >= 0 {
-							evalCP = mateValue
+							evalCP = MateScore
 						} else {
-							evalCP = -mateValue
+							evalCP = -MateScore
 						}
 						evalSet = true
 					}
@@ -398,8 +402,14 @@ func (s *Session) applyOptions(opt Options) error {
 		fmt.Sprintf("setoption name MultiPV value %d\n", opt.MultiPV),
 		"setoption name Minimum Thinking Time value 10\n",
 		"setoption name Move Overhead value 100\n",
-		"setoption name UCI_LimitStrength value true\n",
-		fmt.Sprintf("setoption name UCI_Elo value %d\n", opt.Elo),
+	}
+	if opt.FullStrength {
+		cmds = append(cmds, "setoption name UCI_LimitStrength value false\n")
+	} else {
+		cmds = append(cmds,
+			"setoption name UCI_LimitStrength value true\n",
+			fmt.Sprintf("setoption name UCI_Elo value %d\n", opt.Elo),
+		)
 	}
 	for _, cmd := range cmds {
 		if err := s.send(cmd); err != nil {
@@ -92,6 +92,10 @@ func New(cfg *config.AppConfig, logger *zap.Logger) (*Deps, error) {
         HistoryLimit:        cfg.ChessHistoryLimit,
         AllowedRooms:        append([]string(nil), allowed...),
         DefaultOpeningStyle: strings.TrimSpace(cfg.ChessOpeningStyle),
+        AnalysisDepth:       cfg.ChessAnalysisDepth,
+        ReplayFrameDelay:    time.Duration(cfg.ChessReplayFrameDelayMS) * time.Millisecond,
+        ReplayMaxBytes:      cfg.ChessReplayMaxBytes,
+        ReplayMaxFrames:     cfg.ChessReplayMaxFrames,
     }
 
     service, err := svcchess.NewService(engine, cacheSvc, repo, svcchess.NewSVGBoardRenderer(), svcCfg, logger)
@@ -0,0 +1,225 @@
+// Package command routes "<prefix> <명령> [인자...]" chat messages to registered handlers.
+//
+// 명령은 이름·별칭·사용법(msgcat 키)·도움말(msgcat 키)·권한 검사·인자 파서를 한 곳에 선언하고,
+// 도움말은 등록 순서대로 레지스트리에서 생성합니다.
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
+	"go.uber.org/zap"
+)
+
+// ErrUsage makes Dispatch reply with the command's usage text.
+var ErrUsage = errors.New("command: usage")
+
+// Handler runs a command. Errors are reported by Dispatch (see Notice, ErrUsage).
+type Handler func(c *Context) error
+
+// Check guards a command before it runs (사용자 식별, 엔진 필요, 동시 대국 한도 등).
+// 거절할 때는 Reject로 만든 Notice를 반환합니다.
+type Check func(c *Context) error
+
+// Command describes one chat command.
+type Command struct {
+	Name    string
+	Aliases []string
+	Usage   string // msgcat key sent when args are missing or the handler returns ErrUsage
+	Help    string // msgcat key of the help entry; empty hides the command from help
+	MinArgs int
+	// Target lets the command take a leading target token ("#2 기권"); Dispatch puts it in Context.Target.
+	// 대상 토큰을 받지 않는 명령 앞에 붙으면 전체 입력이 Fallback으로 갑니다.
+	Target bool
+	Checks []Check
+	// Parse turns c.Args into c.Parsed before Run; returning ErrUsage or a Notice stops the command.
+	Parse func(c *Context) (any, error)
+	Run   Handler
+	// Sub holds subcommands chosen by the first argument (예: `방 생성`, `관전 종료`).
+	// 일치하는 하위 명령이 없으면 Run이, Run도 없으면 사용법이 실행됩니다. Usage가 비어 있으면 상위 명령의 것을 씁니다.
+	Sub []*Command
+}
+
+func (cmd *Command) names() []string {
+	return append([]string{cmd.Name}, cmd.Aliases...)
+}
+
+func (cmd *Command) sub(token string) *Command {
+	token = strings.ToLower(strings.TrimSpace(token))
+	for _, s := range cmd.Sub {
+		for _, n := range s.names() {
+			if strings.ToLower(n) == token {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// Notice is an error that carries the reply for the user; Dispatch sends it instead of logging.
+type Notice struct {
+	Key      string
+	Data     any
+	Fallback string
+}
+
+func (n *Notice) Error() string {
+	if n.Key == "" {
+		return "command: notice: " + n.Fallback
+	}
+	return "command: notice " + n.Key
+}
+
+// Reject returns a Notice rendered from key (fallback when rendering fails).
+func Reject(key string, data any, fallback string) error {
+	return &Notice{Key: key, Data: data, Fallback: fallback}
+}
+
+// RejectText returns a Notice with a ready-made text.
+func RejectText(text string) error {
+	return &Notice{Fallback: text}
+}
+
+// Registry maps command names and aliases to commands.
+type Registry struct {
+	cmds  []*Command
+	index map[string]*Command
+
+	// Empty runs when the message is only the prefix.
+	Empty Handler
+	// Fallback runs for unknown command names (수 입력 등).
+	Fallback Handler
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{index: make(map[string]*Command)}
+}
+
+// Register adds commands in help order. Duplicate names are programming errors and panic.
+func (r *Registry) Register(cmds ...*Command) {
+	for _, cmd := range cmds {
+		for _, n := range cmd.names() {
+			key := strings.ToLower(strings.TrimSpace(n))
+			if key == "" {
+				panic("command: empty command name")
+			}
+			if _, dup := r.index[key]; dup {
+				panic(fmt.Sprintf("command: duplicate name %q", n))
+			}
+			r.index[key] = cmd
+		}
+		r.cmds = append(r.cmds, cmd)
+	}
+}
+
+// Lookup returns the command registered under name (case-insensitive), or nil.
+func (r *Registry) Lookup(name string) *Command {
+	return r.index[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// Commands returns the registered commands in registration order.
+func (r *Registry) Commands() []*Command {
+	return append([]*Command(nil), r.cmds...)
+}
+
+// Help renders the help entries of all commands in registration order.
+func (r *Registry) Help(cat *msgcat.Catalog, data any) string {
+	var lines []string
+	for _, cmd := range r.cmds {
+		if cmd.Help == "" {
+			continue
+		}
+		txt, err := cat.Render(cmd.Help, data)
+		if err != nil {
+			continue
+		}
+		if txt = strings.TrimRight(txt, "\n"); strings.TrimSpace(txt) != "" {
+			lines = append(lines, txt)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Dispatch parses raw (the message without the prefix) into c.Name/c.Args (and c.Target, see Command.Target)
+// and runs the matching command.
+func (r *Registry) Dispatch(c *Context, raw string) {
+	c.Raw = strings.TrimSpace(raw)
+	parts := strings.Fields(c.Raw)
+	if len(parts) == 0 {
+		r.run(c, r.Empty)
+		return
+	}
+	if len(parts) > 1 && strings.HasPrefix(parts[0], "#") {
+		if cmd := r.Lookup(parts[1]); cmd != nil && cmd.Target {
+			c.Target, parts = parts[0], parts[1:]
+		}
+	}
+	c.Name = strings.ToLower(parts[0])
+	c.Args = parts[1:]
+	cmd := r.Lookup(c.Name)
+	if cmd == nil {
+		r.run(c, r.Fallback)
+		return
+	}
+	c.usage = cmd.Usage
+	for len(c.Args) > 0 {
+		sub := cmd.sub(c.Args[0])
+		if sub == nil {
+			break
+		}
+		cmd, c.Args = sub, c.Args[1:]
+		if cmd.Usage != "" {
+			c.usage = cmd.Usage
+		}
+	}
+	c.Command = cmd
+	r.run(c, func(c *Context) error { return invoke(cmd, c) })
+}
+
+func invoke(cmd *Command, c *Context) error {
+	if cmd.Run == nil {
+		return ErrUsage
+	}
+	// 권한 검사가 먼저: 쓸 수 없는 명령의 사용법은 보여주지 않음
+	for _, check := range cmd.Checks {
+		if err := check(c); err != nil {
+			return err
+		}
+	}
+	if len(c.Args) < cmd.MinArgs {
+		return ErrUsage
+	}
+	if cmd.Parse != nil {
+		v, err := cmd.Parse(c)
+		if err != nil {
+			return err
+		}
+		c.Parsed = v
+	}
+	return cmd.Run(c)
+}
+
+func (r *Registry) run(c *Context, h Handler) {
+	if h == nil {
+		return
+	}
+	err := h(c)
+	if err == nil {
+		return
+	}
+	var n *Notice
+	switch {
+	case errors.As(err, &n):
+		_ = c.ReplyKey(n.Key, n.Data, n.Fallback)
+	case errors.Is(err, ErrUsage):
+		if c.usage == "" {
+			return
+		}
+		_ = c.ReplyKey(c.usage, map[string]string{"Prefix": c.Prefix()}, "")
+	default:
+		c.logger().Warn("command_error", zap.String("cmd", c.Name), zap.String("room_id", c.Room), zap.Error(err))
+	}
+}
@@ -0,0 +1,175 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
+)
+
+type recordEgress struct {
+	mu    sync.Mutex
+	texts []string
+}
+
+func (e *recordEgress) SendText(_ context.Context, room, message string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.texts = append(e.texts, room+": "+message)
+	return nil
+}
+
+func (e *recordEgress) SendImage(context.Context, string, string) error { return nil }
+
+func newTestContext(t *testing.T) (*Context, *recordEgress) {
+	t.Helper()
+	cat, err := msgcat.New("")
+	if err != nil {
+		t.Fatalf("msgcat.New: %v", err)
+	}
+	eg := &recordEgress{}
+	return &Context{Ctx: context.Background(), Room: "100", UserID: "u1", Sender: "철수", Out: &Replier{Egress: eg, Catalog: cat, Prefix: "!체스"}}, eg
+}
+
+func TestDispatch_AliasSubcommandAndFallback(t *testing.T) {
+	var got []string
+	record := func(c *Context) error {
+		got = append(got, c.Command.Name+"|"+strings.Join(c.Args, ","))
+		return nil
+	}
+	r := NewRegistry()
+	r.Register(&Command{
+		Name: "방",
+		Sub: []*Command{
+			{Name: "생성", Aliases: []string{"만들기"}, Run: record},
+			{Name: "리스트", Aliases: []string{"목록"}, Run: record},
+		},
+	})
+	r.Register(&Command{Name: "랭킹", Aliases: []string{"순위"}, Run: record})
+	r.Fallback = func(c *Context) error { got = append(got, "fallback|"+c.Raw); return nil }
+	r.Empty = func(c *Context) error { got = append(got, "empty"); return nil }
+
+	for _, raw := range []string{"방 만들기 흑 비공개", "방 목록 2", "순위", "Nf3", "  "} {
+		c, _ := newTestContext(t)
+		r.Dispatch(c, raw)
+	}
+	want := []string{"생성|흑,비공개", "리스트|2", "랭킹|", "fallback|Nf3", "empty"}
+	if strings.Join(got, " / ") != strings.Join(want, " / ") {
+		t.Fatalf("dispatch = %q, want %q", got, want)
+	}
+}
+
+func TestDispatch_TargetPrefix(t *testing.T) {
+	var got []string
+	record := func(c *Context) error {
+		got = append(got, c.Target+"|"+c.Command.Name+"|"+strings.Join(c.Args, ","))
+		return nil
+	}
+	r := NewRegistry()
+	r.Register(&Command{Name: "기권", Target: true, Run: record})
+	r.Register(&Command{Name: "무승부", Aliases: []string{"무승부수락"}, Target: true, Run: record})
+	r.Register(&Command{Name: "랭킹", Run: record})
+	r.Fallback = func(c *Context) error { got = append(got, "fallback|"+c.Name+"|"+c.Raw); return nil }
+
+	for _, raw := range []string{"#2 기권", "#3 무승부수락", "기권", "#2 랭킹", "#2 e4"} {
+		c, _ := newTestContext(t)
+		r.Dispatch(c, raw)
+	}
+	want := []string{"#2|기권|", "#3|무승부|", "|기권|", "fallback|#2|#2 랭킹", "fallback|#2|#2 e4"}
+	if strings.Join(got, " / ") != strings.Join(want, " / ") {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDispatch_UsageOnMissingArgsAndUnknownSub(t *testing.T) {
+	r := NewRegistry()
+	ran := false
+	r.Register(
+		&Command{Name: "참가", Usage: "usage.join", MinArgs: 1, Run: func(*Context) error { ran = true; return nil }},
+		&Command{Name: "방", Usage: "usage.lobby", Sub: []*Command{{Name: "취소", Run: func(*Context) error { return ErrUsage }}}},
+	)
+	for _, raw := range []string{"참가", "방 없는명령", "방 취소"} {
+		c, eg := newTestContext(t)
+		r.Dispatch(c, raw)
+		if len(eg.texts) != 1 || !strings.Contains(eg.texts[0], "사용방법: !체스") {
+			t.Fatalf("%q: replies = %q, want one usage text", raw, eg.texts)
+		}
+	}
+	if ran {
+		t.Fatalf("handler ran without required args")
+	}
+}
+
+func TestDispatch_ChecksParseAndErrors(t *testing.T) {
+	r := NewRegistry()
+	var parsed any
+	denied := func(c *Context) error {
+		if c.UserID == "" {
+			return Reject("user.identify.error", nil, "사용자 식별 실패")
+		}
+		return nil
+	}
+	r.Register(
+		&Command{Name: "기보", Checks: []Check{denied}, Parse: func(c *Context) (any, error) {
+			if c.Arg(0) == "x" {
+				return nil, RejectText("잘못된 ID")
+			}
+			return c.Arg(0), nil
+		}, Run: func(c *Context) error { parsed = c.Parsed; return nil }},
+		&Command{Name: "고장", Run: func(*Context) error { return errors.New("boom") }},
+	)
+
+	c, eg := newTestContext(t)
+	c.UserID = ""
+	r.Dispatch(c, "기보 7")
+	if len(eg.texts) != 1 || parsed != nil {
+		t.Fatalf("check did not reject: replies=%q parsed=%v", eg.texts, parsed)
+	}
+
+	c, eg = newTestContext(t)
+	r.Dispatch(c, "기보 x")
+	if len(eg.texts) != 1 || eg.texts[0] != "100: 잘못된 ID" {
+		t.Fatalf("parse notice = %q", eg.texts)
+	}
+
+	c, _ = newTestContext(t)
+	r.Dispatch(c, "기보 7")
+	if parsed != "7" {
+		t.Fatalf("parsed = %v, want 7", parsed)
+	}
+
+	c, eg = newTestContext(t)
+	r.Dispatch(c, "고장")
+	if len(eg.texts) != 0 {
+		t.Fatalf("plain errors must only be logged, got %q", eg.texts)
+	}
+}
+
+func TestRegister_DuplicateNamePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic on duplicate alias")
+		}
+	}()
+	r := NewRegistry()
+	r.Register(&Command{Name: "랭킹", Aliases: []string{"순위"}})
+	r.Register(&Command{Name: "순위"})
+}
+
+func TestHelp_RegistrationOrderSkipsHidden(t *testing.T) {
+	c, _ := newTestContext(t)
+	r := NewRegistry()
+	r.Register(
+		&Command{Name: "랭킹", Help: "help.ranking"},
+		&Command{Name: "방생성"},
+		&Command{Name: "참가", Help: "help.join"},
+	)
+	help := r.Help(c.Out.Catalog, map[string]string{"Prefix": "!체스"})
+	rank, join := strings.Index(help, "!체스 랭킹"), strings.Index(help, "!체스 참가")
+	if rank < 0 || join < 0 || rank > join {
+		t.Fatalf("help order wrong:\n%s", help)
+	}
+}
@@ -0,0 +1,101 @@
+package command
+
+import (
+	"context"
+	"strings"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/adapter/chesspresenter"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irisfast"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/msgcat"
+	"github.com/park285/Cheese-KakaoTalk-bot/pkg/chessdto"
+	"go.uber.org/zap"
+)
+
+// Replier is the shared reply path for every command: text through Egress, boards and images through Presenter.
+type Replier struct {
+	Egress    irisfast.Egress
+	Presenter *chesspresenter.Presenter
+	Catalog   *msgcat.Catalog
+	Prefix    string
+	Logger    *zap.Logger
+}
+
+// Context is one command invocation. 응답은 항상 요청이 들어온 방(Room)으로 갑니다.
+type Context struct {
+	Ctx    context.Context
+	Msg    *irisfast.Message
+	Room   string
+	UserID string
+	Sender string
+
+	Name    string   // 입력된 명령 토큰(소문자, 별칭 그대로)
+	Args    []string // 명령(및 하위 명령) 뒤의 인자, 원래 대소문자 유지
+	Target  string   // 명령 앞의 대상 토큰(예: "#2"), Command.Target인 명령에만 채워짐
+	Raw     string   // 프리픽스를 뗀 전체 입력
+	Parsed  any      // Command.Parse 결과
+	Command *Command // 실행 중인 (하위) 명령; Empty/Fallback이면 nil
+
+	Out *Replier
+
+	usage string
+}
+
+// Arg returns the trimmed i-th argument, or "".
+func (c *Context) Arg(i int) string {
+	if i < 0 || i >= len(c.Args) {
+		return ""
+	}
+	return strings.TrimSpace(c.Args[i])
+}
+
+// Prefix returns the bot prefix (예: "!체스").
+func (c *Context) Prefix() string {
+	if c.Out == nil {
+		return ""
+	}
+	return c.Out.Prefix
+}
+
+// Render renders a catalog template.
+func (c *Context) Render(key string, data any) (string, error) {
+	return c.Out.Catalog.Render(key, data)
+}
+
+// Reply sends text to the requesting room.
+func (c *Context) Reply(text string) error {
+	return c.Out.Egress.SendText(c.Ctx, c.Room, text)
+}
+
+// ReplyKey renders key and sends it; on a render error it logs and sends fallback instead (nothing when empty).
+func (c *Context) ReplyKey(key string, data any, fallback string) error {
+	txt := fallback
+	if key != "" {
+		t, err := c.Render(key, data)
+		if err == nil {
+			txt = t
+		} else {
+			c.logger().Error("msgcat_render_error", zap.String("key", key), zap.Error(err))
+		}
+	}
+	if strings.TrimSpace(txt) == "" {
+		return nil
+	}
+	return c.Reply(txt)
+}
+
+// Board sends text (optional) followed by the board image of state.
+func (c *Context) Board(text string, state *chessdto.SessionState) error {
+	return c.Out.Presenter.Board(c.Room, text, state)
+}
+
+// Image sends text (optional) followed by an arbitrary image.
+func (c *Context) Image(text string, image []byte) error {
+	return c.Out.Presenter.Image(c.Room, text, image)
+}
+
+func (c *Context) logger() *zap.Logger {
+	if c.Out == nil || c.Out.Logger == nil {
+		return zap.NewNop()
+	}
+	return c.Out.Logger
+}
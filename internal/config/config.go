@@ -20,8 +20,14 @@ type AppConfig struct {
 	RedisURL    string
 	DatabaseURL string
 
+    // ALLOW_RANDOM_MATCH: `매칭` 대기열 사용 여부
     AllowRandomMatch   bool
+    // MAX_CONCURRENT_GAMES: 인스턴스 전체 동시 PvP 대국 상한
     MaxConcurrentGames int
+    // PVP_MAX_GAMES_PER_USER: 사용자 1명이 동시에 진행할 수 있는 PvP 대국 수, 0이면 제한 없음 (기본 3)
+    PvpMaxGamesPerUser int
+    // PVP_MATCH_RATING_WINDOW: 매칭 시 허용 레이팅 차이, 0이면 제한 없음 (기본 200)
+    PvpMatchRatingWindow int
 
 	AllowedRooms []string
 
@@ -32,6 +38,14 @@ type AppConfig struct {
 	ChessOpeningMaxPly    int
     ChessOpeningMinWeight int
     ChessOpeningStyle     string
+    // CHESS_ANALYSIS_DEPTH: 대국 후 분석(`분석 <ID>`) 고정 탐색 깊이. 기본 14
+    ChessAnalysisDepth    int
+    // CHESS_REPLAY_FRAME_DELAY_MS: 기보 GIF(`기보 <ID> gif`) 프레임 간격. 기본 800
+    ChessReplayFrameDelayMS int
+    // CHESS_REPLAY_MAX_BYTES: 기보 GIF 최대 크기(초과 시 축소). 기본 5MB
+    ChessReplayMaxBytes     int
+    // CHESS_REPLAY_MAX_FRAMES: 기보 GIF 최대 프레임 수(더 긴 대국은 수를 건너뜀). 기본 80
+    ChessReplayMaxFrames    int
 
     // When true, run in PvP-only mode: do not initialize single-player engine/service
     PvpOnly bool
@@ -39,13 +53,18 @@ type AppConfig struct {
     // IgnoreSenders: 표시명이 이 목록에 포함되면 무시
     IgnoreSenders []string
 
-    // START_IMAGE_DELAY_MS: PvP 시작 안내에서 텍스트 후 이미지 전송 전 대기(ms)
-    // 기본 150ms
-    StartImageDelayMS int
-
-    // FANOUT_IMAGE_DELAY_MS: 방 간 전송 간격(ms) — 이미지 드롭 완화용
-    // 기본 200ms
-    FanoutImageDelayMS int
+    // 송신 큐: 방별 순서 보장 + 전송 간격 (이미지 드롭 완화)
+    // EGRESS_ROOM_INTERVAL_MS: 같은 방 연속 전송 간 최소 간격(ms), 기본 150ms
+    EgressRoomIntervalMS int
+    // EGRESS_RATE_MS: 전체 전송 간 최소 간격(ms). 음수(기본)면 Iris /config의 messageRate를 따름
+    EgressRateMS int
+    // EGRESS_RETRY_MAX: 전송 실패 시 재시도 포함 최대 시도 횟수, 기본 3
+    EgressRetryMax int
+    // EGRESS_QUEUE_MAX: 방별 대기열 상한, 기본 100 (0이면 무제한)
+    EgressQueueMax int
+    // DeprecatedEnv: 설정되어 있던 구 환경변수 → 대체 변수 (시작 시 경고 로그용)
+    // START_IMAGE_DELAY_MS는 EGRESS_ROOM_INTERVAL_MS, FANOUT_IMAGE_DELAY_MS는 EGRESS_RATE_MS의 별칭
+    DeprecatedEnv map[string]string
 
     // EGRESS 설정: 송신 경로 선택 및 옵션
     // EGRESS_TRANSPORT: http|ws|auto (기본 http)
@@ -58,18 +77,54 @@ type AppConfig struct {
     PvpEgressTransport string
     // PVP_WS_EGRESS_DRYRUN: PvP WS 드라이런
     PvpWSEgressDryRun bool
+
+    // PVP_IDLE_TIMEOUT_MIN: 마지막 갱신 이후 이 시간(분)이 지난 ACTIVE 대국은 방치로 보고 자동 종료
+    // 기본 60분, 0이면 비활성 (시간제가 아닌 방치 대국 정리용)
+    PvpIdleTimeoutMin int
+    // PVP_SWEEP_INTERVAL_SEC: 방치 대국 검사 주기(초), 기본 60초
+    PvpSweepIntervalSec int
+    // PVP_CORRESPONDENCE_IDLE_DAYS: 통신 대국 방치 종료 기준(일), 기본 7일, 0이면 자동 종료 없음
+    PvpCorrespondenceIdleDays int
+
+    // PVP_LOBBY_TTL_MIN: 상대 없이 이 시간(분)이 지난 대기방은 만료 후 생성자 방에 안내, 기본 30분 (0이면 비활성)
+    PvpLobbyTTLMin int
+
+    // PVP_LOBBY_SCOPE: 방 리스트 기본·최대 범위 room|allowed|global (기본 room, 요청 방에서 만든 대기방만)
+    PvpLobbyScope string
+
+    // PVP_MAX_SPECTATOR_ROOMS: 대국당 관전 방 최대 수, 기본 5 (0이면 제한 없음)
+    PvpMaxSpectatorRooms int
+
+    // PVP_CHALLENGE_TTL_SEC: 지정 도전(`도전 @이름`) 응답 기한(초), 기본 300초
+    PvpChallengeTTLSec int
 }
 
 func Load() (*AppConfig, error) {
     cfg := &AppConfig{
 		AllowRandomMatch:   false,
 		MaxConcurrentGames: 200,
+		PvpMaxGamesPerUser: 3,
+		PvpMatchRatingWindow: 200,
 		ChessDefaultPreset: "level3",
 		ChessSessionTTLSec: 3600,
         ChessHistoryLimit:   10,
-        StartImageDelayMS:   150,
-        FanoutImageDelayMS:  200,
+        ChessAnalysisDepth:  14,
+        ChessReplayFrameDelayMS: 800,
+        ChessReplayMaxBytes:     5 << 20,
+        ChessReplayMaxFrames:    80,
+        EgressRoomIntervalMS: 150,
+        EgressRateMS:        -1,
+        EgressRetryMax:      3,
+        EgressQueueMax:      100,
+        DeprecatedEnv:       map[string]string{},
         EgressTransport:     "http",
+        PvpIdleTimeoutMin:   60,
+        PvpSweepIntervalSec: 60,
+        PvpCorrespondenceIdleDays: 7,
+        PvpLobbyTTLMin:      30,
+        PvpLobbyScope:       "room",
+        PvpMaxSpectatorRooms: 5,
+        PvpChallengeTTLSec:   300,
     }
 
 	cfg.IrisBaseURL = strings.TrimSpace(os.Getenv("IRIS_BASE_URL"))
@@ -104,6 +159,16 @@ func Load() (*AppConfig, error) {
 			cfg.MaxConcurrentGames = n
 		}
 	}
+	if v := strings.TrimSpace(os.Getenv("PVP_MAX_GAMES_PER_USER")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.PvpMaxGamesPerUser = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("PVP_MATCH_RATING_WINDOW")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.PvpMatchRatingWindow = n
+		}
+	}
     // time control removed
 
 	// Chess specific
@@ -132,6 +197,26 @@ func Load() (*AppConfig, error) {
 		}
 	}
     cfg.ChessOpeningStyle = strings.TrimSpace(os.Getenv("CHESS_OPENING_DEFAULT_STYLE"))
+    if v := strings.TrimSpace(os.Getenv("CHESS_ANALYSIS_DEPTH")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n > 0 {
+            cfg.ChessAnalysisDepth = n
+        }
+    }
+    if v := strings.TrimSpace(os.Getenv("CHESS_REPLAY_FRAME_DELAY_MS")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n > 0 {
+            cfg.ChessReplayFrameDelayMS = n
+        }
+    }
+    if v := strings.TrimSpace(os.Getenv("CHESS_REPLAY_MAX_BYTES")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n > 0 {
+            cfg.ChessReplayMaxBytes = n
+        }
+    }
+    if v := strings.TrimSpace(os.Getenv("CHESS_REPLAY_MAX_FRAMES")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n > 1 {
+            cfg.ChessReplayMaxFrames = n
+        }
+    }
 
     // PvP-only mode (disables single-player engine)
     if v := strings.TrimSpace(os.Getenv("CHESS_PVP_ONLY")); v != "" {
@@ -166,17 +251,39 @@ func Load() (*AppConfig, error) {
         cfg.IgnoreSenders = []string{"Iris"}
     }
 
-    // START_IMAGE_DELAY_MS (milliseconds)
-    if v := strings.TrimSpace(os.Getenv("START_IMAGE_DELAY_MS")); v != "" {
+    // EGRESS_ROOM_INTERVAL_MS / EGRESS_RATE_MS (milliseconds)
+    if v := strings.TrimSpace(os.Getenv("EGRESS_ROOM_INTERVAL_MS")); v != "" {
         if n, err := strconv.Atoi(v); err == nil && n >= 0 {
-            cfg.StartImageDelayMS = n
+            cfg.EgressRoomIntervalMS = n
+        }
+    }
+    if v := strings.TrimSpace(os.Getenv("EGRESS_RATE_MS")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil {
+            cfg.EgressRateMS = n
+        }
+    }
+    // 구 이미지 지연 설정: 새 변수가 없을 때만 같은 의미의 간격으로 사용 (deprecated)
+    if v := strings.TrimSpace(os.Getenv("START_IMAGE_DELAY_MS")); v != "" {
+        cfg.DeprecatedEnv["START_IMAGE_DELAY_MS"] = "EGRESS_ROOM_INTERVAL_MS"
+        if n, err := strconv.Atoi(v); err == nil && n >= 0 && strings.TrimSpace(os.Getenv("EGRESS_ROOM_INTERVAL_MS")) == "" {
+            cfg.EgressRoomIntervalMS = n
         }
     }
-
-    // FANOUT_IMAGE_DELAY_MS (milliseconds)
     if v := strings.TrimSpace(os.Getenv("FANOUT_IMAGE_DELAY_MS")); v != "" {
+        cfg.DeprecatedEnv["FANOUT_IMAGE_DELAY_MS"] = "EGRESS_RATE_MS"
+        if n, err := strconv.Atoi(v); err == nil && n >= 0 && strings.TrimSpace(os.Getenv("EGRESS_RATE_MS")) == "" {
+            cfg.EgressRateMS = n
+        }
+    }
+    // EGRESS_RETRY_MAX / EGRESS_QUEUE_MAX
+    if v := strings.TrimSpace(os.Getenv("EGRESS_RETRY_MAX")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n > 0 {
+            cfg.EgressRetryMax = n
+        }
+    }
+    if v := strings.TrimSpace(os.Getenv("EGRESS_QUEUE_MAX")); v != "" {
         if n, err := strconv.Atoi(v); err == nil && n >= 0 {
-            cfg.FanoutImageDelayMS = n
+            cfg.EgressQueueMax = n
         }
     }
 
@@ -210,6 +317,50 @@ func Load() (*AppConfig, error) {
         }
     }
 
+    // PVP_IDLE_TIMEOUT_MIN (minutes, 0 disables)
+    if v := strings.TrimSpace(os.Getenv("PVP_IDLE_TIMEOUT_MIN")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+            cfg.PvpIdleTimeoutMin = n
+        }
+    }
+    // PVP_CORRESPONDENCE_IDLE_DAYS (days, 0 disables)
+    if v := strings.TrimSpace(os.Getenv("PVP_CORRESPONDENCE_IDLE_DAYS")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+            cfg.PvpCorrespondenceIdleDays = n
+        }
+    }
+    // PVP_SWEEP_INTERVAL_SEC (seconds)
+    if v := strings.TrimSpace(os.Getenv("PVP_SWEEP_INTERVAL_SEC")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n > 0 {
+            cfg.PvpSweepIntervalSec = n
+        }
+    }
+    // PVP_LOBBY_TTL_MIN (minutes, 0 disables)
+    if v := strings.TrimSpace(os.Getenv("PVP_LOBBY_TTL_MIN")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+            cfg.PvpLobbyTTLMin = n
+        }
+    }
+    // PVP_LOBBY_SCOPE (room|allowed|global)
+    if v := strings.ToLower(strings.TrimSpace(os.Getenv("PVP_LOBBY_SCOPE"))); v != "" {
+        switch v {
+        case "room", "allowed", "global":
+            cfg.PvpLobbyScope = v
+        }
+    }
+    // PVP_MAX_SPECTATOR_ROOMS (0 = unlimited)
+    if v := strings.TrimSpace(os.Getenv("PVP_MAX_SPECTATOR_ROOMS")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+            cfg.PvpMaxSpectatorRooms = n
+        }
+    }
+    // PVP_CHALLENGE_TTL_SEC (seconds)
+    if v := strings.TrimSpace(os.Getenv("PVP_CHALLENGE_TTL_SEC")); v != "" {
+        if n, err := strconv.Atoi(v); err == nil && n > 0 {
+            cfg.PvpChallengeTTLSec = n
+        }
+    }
+
 	if len(cfg.AllowedRooms) == 0 {
 		if v := strings.TrimSpace(os.Getenv("CHESS_ALLOWED_ROOMS")); v != "" {
 			parts := strings.Split(v, ",")
@@ -19,6 +19,30 @@ type ChessGame struct {
 	Duration      time.Duration
 	Blunders      int
 	EngineLatency time.Duration
+	PlayerColor   string
+	// StartFEN is empty for games from the standard initial position.
+	StartFEN string
+	Rated    bool
+}
+
+// MoveEvaluation is the post-game engine verdict for a single ply.
+type MoveEvaluation struct {
+	Ply      int
+	MoveUCI  string
+	BestMove string
+	// EvalCP is the white-relative evaluation after the move.
+	EvalCP int
+	CPLoss int
+	// Class is best|good|inaccuracy|mistake|blunder for the player's moves, empty for the engine's.
+	Class string
+}
+
+// GameAnalysis holds per-ply evaluations and the player's accuracy for a stored game.
+type GameAnalysis struct {
+	GameID   int64
+	Depth    int
+	Accuracy float64
+	Moves    []MoveEvaluation
 }
 
 type ChessProfile struct {
@@ -14,6 +14,16 @@ import (
 // HeaderProvider allows injecting per-request headers
 type HeaderProvider func() map[string]string
 
+// StatusError is a non-2xx response from the Iris API.
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("iris api error: status=%d body=%s", e.Code, e.Body)
+}
+
 type Client struct {
 	baseURL string
 	http    *fasthttp.Client
@@ -71,11 +81,14 @@ func (c *Client) Decrypt(ctx context.Context, data string) (string, error) {
 	return resp.Decrypted, nil
 }
 
+// SendMessage posts a text reply once. /reply is not idempotent, so it is never retried here;
+// Queue owns retries for sends.
 func (c *Client) SendMessage(ctx context.Context, room, message string) error {
 	req := ReplyRequest{Type: "text", Room: room, Data: message}
 	return c.doJSON(ctx, fasthttp.MethodPost, "/reply", req, nil, false)
 }
 
+// SendImage posts an image reply once (see SendMessage).
 func (c *Client) SendImage(ctx context.Context, room, imageBase64 string) error {
     req := ReplyRequest{Type: "image", Room: room, Data: imageBase64}
     return c.doJSON(ctx, fasthttp.MethodPost, "/reply", req, nil, false)
@@ -136,7 +149,7 @@ func (c *Client) doJSON(ctx context.Context, method, path string, in any, out an
 		status := resp.StatusCode()
 		if status < 200 || status >= 300 {
 			body := string(resp.Body())
-			err := fmt.Errorf("iris api error: status=%d body=%s", status, truncate(body, 512))
+			err := &StatusError{Code: status, Body: truncate(body, 512)}
 			if attempt == attempts || !retry || !shouldRetryStatus(status) {
 				return err
 			}
@@ -9,12 +9,56 @@ import (
     "nhooyr.io/websocket/wsjson"
 )
 
+// ErrNotConnected is returned by the WS egress when there is no live connection to write to.
+var ErrNotConnected = errors.New("ws not connected")
+
 // Egress abstracts message/image sending over HTTP or WebSocket.
 type Egress interface {
     SendText(ctx context.Context, room, message string) error
     SendImage(ctx context.Context, room, imageBase64 string) error
 }
 
+// SendResult reports how a single text/image send went.
+type SendResult struct {
+    Room string
+    Kind string // "text" | "image"
+    // Bytes: 전송한 페이로드 길이 (이미지는 base64 기준)
+    Bytes int
+    // Attempts: 재시도를 포함한 시도 횟수. 큐에서 거부/취소되어 보내지 못했으면 0
+    Attempts int
+    // Waited: 큐 대기와 전송 간격으로 기다린 시간
+    Waited time.Duration
+    Err    error
+}
+
+// Delivered reports whether the payload reached Iris.
+func (r SendResult) Delivered() bool { return r.Err == nil && r.Attempts > 0 }
+
+// ResultEgress is an Egress that reports a SendResult per send (Queue.Wrap이 반환하는 Egress가 구현).
+type ResultEgress interface {
+    Egress
+    Send(ctx context.Context, room, kind, data string) SendResult
+}
+
+// Deliver sends data through eg and returns a structured result. Egresses without
+// ResultEgress get a single-attempt result built from their error.
+func Deliver(ctx context.Context, eg Egress, room, kind, data string) SendResult {
+    if re, ok := eg.(ResultEgress); ok {
+        return re.Send(ctx, room, kind, data)
+    }
+    res := SendResult{Room: room, Kind: kind, Bytes: len(data), Attempts: 1}
+    if eg == nil {
+        res.Attempts, res.Err = 0, errors.New("egress not available")
+        return res
+    }
+    if kind == "image" {
+        res.Err = eg.SendImage(ctx, room, data)
+    } else {
+        res.Err = eg.SendText(ctx, room, data)
+    }
+    return res
+}
+
 type transportMode string
 
 const (
@@ -24,7 +68,8 @@ const (
 )
 
 // NewEgress creates an Egress based on mode. When mode is auto, WS is preferred when connected;
-// on WS failure, it falls back to HTTP once.
+// it falls back to HTTP only when nothing was written to WS (ErrNotConnected). Retrying any other
+// failure is left to the caller (Queue), so a send is never retried by two layers.
 func NewEgress(mode string, dryrun bool, c *Client, ws *WebSocket, logger *zap.Logger) Egress {
     if logger == nil {
         logger = zap.NewNop()
@@ -81,7 +126,7 @@ func (w *wsEgress) SendImage(ctx context.Context, room, imageBase64 string) erro
 func (w *wsEgress) writeJSON(ctx context.Context, v any) error {
     // Quick state/conn check to avoid write on nil
     if w.ws.conn == nil || w.ws.state != WSStateConnected {
-        return errors.New("ws not connected")
+        return ErrNotConnected
     }
     // Serialize write via the WebSocket's connection; this method is called sequentially by callers per message
     // and wsjson.Write is not concurrency-safe across goroutines.
@@ -97,7 +142,7 @@ func (w *wsEgress) writeJSON(ctx context.Context, v any) error {
     return wsjsonWrite(dctx, w.ws, v)
 }
 
-// autoEgress prefers WS if available, with single fallback to HTTP.
+// autoEgress prefers WS if available and falls back to HTTP when WS had no connection to write to.
 type autoEgress struct{
     ws   *wsEgress
     http *httpEgress
@@ -107,14 +152,16 @@ type autoEgress struct{
 func (a *autoEgress) SendText(ctx context.Context, room, message string) error {
     // Try WS first when connected
     if a.ws != nil && a.ws.ws != nil && a.ws.ws.conn != nil && a.ws.ws.state == WSStateConnected {
-        if err := a.ws.SendText(ctx, room, message); err == nil { return nil }
+        err := a.ws.SendText(ctx, room, message)
+        if !errors.Is(err, ErrNotConnected) { return err }
         a.logger.Warn("egress_fallback", zap.String("type", "text"), zap.String("room", room))
     }
     return a.http.SendText(ctx, room, message)
 }
 func (a *autoEgress) SendImage(ctx context.Context, room, imageBase64 string) error {
     if a.ws != nil && a.ws.ws != nil && a.ws.ws.conn != nil && a.ws.ws.state == WSStateConnected {
-        if err := a.ws.SendImage(ctx, room, imageBase64); err == nil { return nil }
+        err := a.ws.SendImage(ctx, room, imageBase64)
+        if !errors.Is(err, ErrNotConnected) { return err }
         a.logger.Warn("egress_fallback", zap.String("type", "image"), zap.String("room", room))
     }
     return a.http.SendImage(ctx, room, imageBase64)
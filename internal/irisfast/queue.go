@@ -0,0 +1,321 @@
+package irisfast
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// ErrQueueFull is returned when a room already has QueueOptions.MaxDepth sends waiting.
+var ErrQueueFull = errors.New("egress queue full")
+
+// QueueOptions tunes a Queue. Zero values disable the corresponding limit.
+type QueueOptions struct {
+	// RoomInterval: 같은 방으로 가는 연속 전송의 최소 간격 (텍스트 직후 이미지 드롭 완화)
+	RoomInterval time.Duration
+	// GlobalInterval: 방과 무관한 전체 전송 간격 (Iris /config의 messageRate)
+	GlobalInterval time.Duration
+	// MaxAttempts: 재시도를 포함한 최대 시도 횟수 (0이면 1회). 전달되지 않은 것이 확실한 실패만 재시도.
+	// 송신 재시도는 여기서만 함 (Client의 /reply와 auto Egress는 재시도하지 않음)
+	MaxAttempts int
+	// MaxDepth: 방별 대기열 상한, 넘치면 ErrQueueFull (0이면 무제한)
+	MaxDepth int
+	Logger   *zap.Logger
+}
+
+// QueueStats is a snapshot of queue depth and delivery counters.
+type QueueStats struct {
+	// Depth counts queued sends including the one in flight; Rooms breaks it down per room (비어 있는 방 제외).
+	Depth int
+	Rooms map[string]int
+	// Sent/Failed count finished sends, Retried counts extra attempts, Dropped counts sends
+	// rejected by MaxDepth or abandoned because the caller's context ended first.
+	Sent    uint64
+	Retried uint64
+	Failed  uint64
+	Dropped uint64
+}
+
+// Queue serialises outbound sends per room. Each room has one FIFO drained by its own worker,
+// so a caller that sends text and then an image always gets them in that order, even when the
+// text needs a retry. Sends from different rooms interleave but share GlobalInterval.
+type Queue struct {
+	opts   QueueOptions
+	logger *zap.Logger
+
+	mu         sync.Mutex
+	rooms      map[string]*roomQueue
+	nextGlobal time.Time
+
+	sent    atomic.Uint64
+	retried atomic.Uint64
+	failed  atomic.Uint64
+	dropped atomic.Uint64
+}
+
+type roomQueue struct {
+	jobs    []*sendJob // jobs[0] is in flight while running
+	running bool
+	next    time.Time // earliest start of the next send to this room
+}
+
+type sendJob struct {
+	ctx   context.Context
+	kind  string
+	send  func(ctx context.Context) error
+	queue time.Time
+	res   SendResult
+	done  chan struct{}
+}
+
+// NewQueue creates an empty queue; wrap transports with Wrap.
+func NewQueue(opts QueueOptions) *Queue {
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Queue{opts: opts, logger: logger, rooms: make(map[string]*roomQueue)}
+}
+
+// Wrap returns an Egress that sends through inner via this queue. Several wrapped transports
+// (예: 전역/PvP Egress) share the same per-room order and rate limits.
+// SendText/SendImage block until delivered, failed after all attempts, or ctx ends.
+func (q *Queue) Wrap(inner Egress) Egress {
+	return &queuedEgress{q: q, inner: inner}
+}
+
+// Depth returns the number of queued sends (in flight included) for room.
+func (q *Queue) Depth(room string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if rq := q.rooms[room]; rq != nil {
+		return len(rq.jobs)
+	}
+	return 0
+}
+
+// Stats returns a snapshot of the queue.
+func (q *Queue) Stats() QueueStats {
+	st := QueueStats{
+		Rooms:   make(map[string]int),
+		Sent:    q.sent.Load(),
+		Retried: q.retried.Load(),
+		Failed:  q.failed.Load(),
+		Dropped: q.dropped.Load(),
+	}
+	q.mu.Lock()
+	for room, rq := range q.rooms {
+		if n := len(rq.jobs); n > 0 {
+			st.Rooms[room] = n
+			st.Depth += n
+		}
+	}
+	q.mu.Unlock()
+	return st
+}
+
+// BusyRooms lists rooms with queued sends, deepest first.
+func (s QueueStats) BusyRooms() []string {
+	rooms := make([]string, 0, len(s.Rooms))
+	for r := range s.Rooms {
+		rooms = append(rooms, r)
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		if s.Rooms[rooms[i]] != s.Rooms[rooms[j]] {
+			return s.Rooms[rooms[i]] > s.Rooms[rooms[j]]
+		}
+		return rooms[i] < rooms[j]
+	})
+	return rooms
+}
+
+func (q *Queue) enqueue(ctx context.Context, room, kind string, size int, send func(ctx context.Context) error) SendResult {
+	job := &sendJob{ctx: ctx, kind: kind, send: send, queue: time.Now(), done: make(chan struct{})}
+	job.res = SendResult{Room: room, Kind: kind, Bytes: size}
+	q.mu.Lock()
+	rq := q.rooms[room]
+	if rq == nil {
+		rq = &roomQueue{}
+		q.rooms[room] = rq
+	}
+	if q.opts.MaxDepth > 0 && len(rq.jobs) >= q.opts.MaxDepth {
+		q.mu.Unlock()
+		q.dropped.Add(1)
+		q.logger.Warn("egress_queue_full", zap.String("room", room), zap.String("type", kind), zap.Int("depth", q.opts.MaxDepth))
+		job.res.Err = ErrQueueFull
+		return job.res
+	}
+	rq.jobs = append(rq.jobs, job)
+	if !rq.running {
+		rq.running = true
+		go q.drain(room, rq)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-job.done:
+		return job.res
+	case <-ctx.Done():
+		// 워커가 아직 꺼내지 않았다면 deliver에서 건너뜀
+		return SendResult{Room: room, Kind: kind, Bytes: size, Waited: time.Since(job.queue), Err: ctx.Err()}
+	}
+}
+
+// drain delivers the room's jobs in order, then removes the room once it is empty and its
+// RoomInterval has passed.
+func (q *Queue) drain(room string, rq *roomQueue) {
+	for {
+		q.mu.Lock()
+		if len(rq.jobs) == 0 {
+			// 방 간격이 남아 있으면 그때까지 워커를 유지해 다음 전송도 간격을 지키게 함
+			if d := time.Until(rq.next); d > 0 {
+				q.mu.Unlock()
+				time.Sleep(d)
+				continue
+			}
+			rq.running = false
+			delete(q.rooms, room)
+			q.mu.Unlock()
+			return
+		}
+		job := rq.jobs[0]
+		q.mu.Unlock()
+
+		q.deliver(room, rq, job)
+		close(job.done)
+
+		q.mu.Lock()
+		rq.jobs[0] = nil
+		rq.jobs = rq.jobs[1:]
+		q.mu.Unlock()
+	}
+}
+
+// deliver sends job, retrying with backoff while the failure is undelivered, and fills job.res.
+func (q *Queue) deliver(room string, rq *roomQueue, job *sendJob) {
+	attempts := q.opts.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	res := &job.res
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err := q.wait(job.ctx, rq); err != nil {
+			if res.Attempts == 0 {
+				q.dropped.Add(1)
+				res.Waited = time.Since(job.queue)
+			} else {
+				q.failed.Add(1)
+			}
+			res.Err = err
+			return
+		}
+		if attempt == 1 {
+			res.Waited = time.Since(job.queue)
+		}
+		res.Attempts = attempt
+		res.Err = job.send(job.ctx)
+		if res.Err == nil {
+			q.sent.Add(1)
+			return
+		}
+		if attempt == attempts || job.ctx.Err() != nil || !Undelivered(res.Err) {
+			break
+		}
+		q.retried.Add(1)
+		q.logger.Warn("egress_retry", zap.Error(res.Err), zap.String("room", room), zap.String("type", job.kind), zap.Int("attempt", attempt))
+		if sleepErr := sleepContext(job.ctx, backoffDuration(attempt)); sleepErr != nil {
+			break
+		}
+	}
+	q.failed.Add(1)
+}
+
+// Undelivered reports whether err proves the payload never reached Iris, so sending it again
+// cannot post a duplicate: a failed dial, no free connection, no WS connection, or a 5xx reply
+// other than 504. /reply is not idempotent, so timeouts and connections dropped mid-request are
+// not retried — Iris may already have posted the message.
+func Undelivered(err error) bool {
+	if err == nil {
+		return false
+	}
+	var dial *fasthttp.ErrDialWithUpstream
+	if errors.As(err, &dial) || errors.Is(err, fasthttp.ErrNoFreeConns) || errors.Is(err, ErrNotConnected) {
+		return true
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Code >= 500 && status.Code != fasthttp.StatusGatewayTimeout
+	}
+	return false
+}
+
+// TooLarge reports whether Iris rejected the payload for its size (413), so nothing was posted
+// and a smaller payload may still go through.
+func TooLarge(err error) bool {
+	var status *StatusError
+	return errors.As(err, &status) && status.Code == fasthttp.StatusRequestEntityTooLarge
+}
+
+// wait reserves the next send slot for the room (RoomInterval) and globally (GlobalInterval),
+// then sleeps until it.
+func (q *Queue) wait(ctx context.Context, rq *roomQueue) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	q.mu.Lock()
+	now := time.Now()
+	at := now
+	if rq.next.After(at) {
+		at = rq.next
+	}
+	if q.nextGlobal.After(at) {
+		at = q.nextGlobal
+	}
+	rq.next = at.Add(q.opts.RoomInterval)
+	q.nextGlobal = at.Add(q.opts.GlobalInterval)
+	q.mu.Unlock()
+	return sleepContext(ctx, at.Sub(now))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// queuedEgress routes an inner Egress through a Queue.
+type queuedEgress struct {
+	q     *Queue
+	inner Egress
+}
+
+func (e *queuedEgress) SendText(ctx context.Context, room, message string) error {
+	return e.Send(ctx, room, "text", message).Err
+}
+
+func (e *queuedEgress) SendImage(ctx context.Context, room, imageBase64 string) error {
+	return e.Send(ctx, room, "image", imageBase64).Err
+}
+
+// Send queues one payload and waits for its result; kind is "text" or "image".
+func (e *queuedEgress) Send(ctx context.Context, room, kind, data string) SendResult {
+	send := func(ctx context.Context) error { return e.inner.SendText(ctx, room, data) }
+	if kind == "image" {
+		send = func(ctx context.Context) error { return e.inner.SendImage(ctx, room, data) }
+	}
+	return e.q.enqueue(ctx, room, kind, len(data), send)
+}
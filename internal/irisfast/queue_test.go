@@ -0,0 +1,227 @@
+package irisfast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// fakeEgress records sends and fails the first failFirst attempts of each payload
+// with failErr (a retryable 503 when nil).
+type fakeEgress struct {
+	mu        sync.Mutex
+	sent      []string
+	at        []time.Time
+	tries     map[string]int
+	failFirst int
+	failErr   error
+	block     chan struct{}
+}
+
+func (f *fakeEgress) send(room, data string) error {
+	if f.block != nil {
+		<-f.block
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.tries == nil {
+		f.tries = make(map[string]int)
+	}
+	f.tries[data]++
+	if f.tries[data] <= f.failFirst {
+		if f.failErr != nil {
+			return f.failErr
+		}
+		return &StatusError{Code: http.StatusServiceUnavailable}
+	}
+	f.sent = append(f.sent, room+":"+data)
+	f.at = append(f.at, time.Now())
+	return nil
+}
+
+func (f *fakeEgress) SendText(_ context.Context, room, message string) error {
+	return f.send(room, message)
+}
+
+func (f *fakeEgress) SendImage(_ context.Context, room, imageBase64 string) error {
+	return f.send(room, imageBase64)
+}
+
+func TestQueueRetriesInOrder(t *testing.T) {
+	inner := &fakeEgress{failFirst: 1}
+	q := NewQueue(QueueOptions{MaxAttempts: 2})
+	eg := q.Wrap(inner)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := eg.SendText(ctx, "r1", "text"); err != nil {
+			t.Errorf("SendText: %v", err)
+		}
+	}()
+	// 텍스트가 먼저 대기열에 들어간 뒤 이미지
+	waitDepth(t, q, "r1", 1)
+	if err := eg.SendImage(ctx, "r1", "image"); err != nil {
+		t.Fatalf("SendImage: %v", err)
+	}
+	wg.Wait()
+
+	if got := inner.sent; len(got) != 2 || got[0] != "r1:text" || got[1] != "r1:image" {
+		t.Fatalf("sent = %v, want text then image", got)
+	}
+	st := q.Stats()
+	if st.Sent != 2 || st.Retried != 2 || st.Failed != 0 || st.Depth != 0 {
+		t.Fatalf("stats = %+v", st)
+	}
+}
+
+func TestQueueGivesUpAfterMaxAttempts(t *testing.T) {
+	inner := &fakeEgress{failFirst: 5}
+	q := NewQueue(QueueOptions{MaxAttempts: 2})
+	res := Deliver(context.Background(), q.Wrap(inner), "r1", "text", "x")
+	if res.Delivered() || res.Err == nil || res.Attempts != 2 || res.Bytes != 1 {
+		t.Fatalf("result = %+v", res)
+	}
+	if st := q.Stats(); st.Failed != 1 || st.Retried != 1 || inner.tries["x"] != 2 {
+		t.Fatalf("stats = %+v tries = %d", st, inner.tries["x"])
+	}
+}
+
+func TestQueueDoesNotRetryPossiblyDelivered(t *testing.T) {
+	inner := &fakeEgress{failFirst: 5, failErr: fmt.Errorf("request failed: %w", fasthttp.ErrTimeout)}
+	q := NewQueue(QueueOptions{MaxAttempts: 3})
+	res := Deliver(context.Background(), q.Wrap(inner), "r1", "text", "x")
+	if res.Err == nil || res.Attempts != 1 || inner.tries["x"] != 1 {
+		t.Fatalf("result = %+v tries = %d", res, inner.tries["x"])
+	}
+	if st := q.Stats(); st.Failed != 1 || st.Retried != 0 {
+		t.Fatalf("stats = %+v", st)
+	}
+}
+
+func TestUndelivered(t *testing.T) {
+	ctx := context.Background()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closed := "http://" + ln.Addr().String()
+	ln.Close()
+	if err := NewClient(closed).SendMessage(ctx, "r1", "x"); !Undelivered(err) {
+		t.Fatalf("refused connection should be undelivered: %v", err)
+	}
+
+	for code, want := range map[int]bool{http.StatusServiceUnavailable: true, http.StatusGatewayTimeout: false, http.StatusBadRequest: false} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(code) }))
+		err := NewClient(srv.URL).SendMessage(ctx, "r1", "x")
+		srv.Close()
+		if err == nil || Undelivered(err) != want {
+			t.Fatalf("status %d: Undelivered(%v) != %v", code, err, want)
+		}
+	}
+
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { <-release }))
+	defer slow.Close()
+	defer close(release)
+	if err := NewClient(slow.URL, WithTimeout(50*time.Millisecond)).SendMessage(ctx, "r1", "x"); err == nil || Undelivered(err) {
+		t.Fatalf("timeout must not count as undelivered: %v", err)
+	}
+	if !Undelivered(ErrNotConnected) || Undelivered(errors.New("boom")) {
+		t.Fatal("sentinel classification")
+	}
+	if tooBig := fmt.Errorf("send: %w", &StatusError{Code: http.StatusRequestEntityTooLarge}); !TooLarge(tooBig) || Undelivered(tooBig) || TooLarge(errors.New("boom")) {
+		t.Fatal("size rejection classification")
+	}
+}
+
+func TestQueueForgetsIdleRooms(t *testing.T) {
+	q := NewQueue(QueueOptions{RoomInterval: 20 * time.Millisecond})
+	eg := q.Wrap(&fakeEgress{})
+	if err := eg.SendText(context.Background(), "r1", "a"); err != nil {
+		t.Fatalf("SendText: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		q.mu.Lock()
+		n := len(q.rooms)
+		q.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("room never removed (%d rooms)", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := eg.SendText(context.Background(), "r1", "b"); err != nil {
+		t.Fatalf("SendText after removal: %v", err)
+	}
+}
+
+func TestQueueRoomInterval(t *testing.T) {
+	inner := &fakeEgress{}
+	q := NewQueue(QueueOptions{RoomInterval: 40 * time.Millisecond})
+	eg := q.Wrap(inner)
+	ctx := context.Background()
+	for _, m := range []string{"a", "b", "c"} {
+		if err := eg.SendText(ctx, "r1", m); err != nil {
+			t.Fatalf("SendText: %v", err)
+		}
+	}
+	for i := 1; i < len(inner.at); i++ {
+		if gap := inner.at[i].Sub(inner.at[i-1]); gap < 35*time.Millisecond {
+			t.Fatalf("gap %d = %v, want >= 40ms", i, gap)
+		}
+	}
+	// 다른 방은 방 간격에 묶이지 않음
+	start := time.Now()
+	if err := eg.SendText(ctx, "r2", "d"); err != nil {
+		t.Fatalf("SendText: %v", err)
+	}
+	if d := time.Since(start); d > 30*time.Millisecond {
+		t.Fatalf("other room waited %v", d)
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	inner := &fakeEgress{block: make(chan struct{})}
+	q := NewQueue(QueueOptions{MaxDepth: 1})
+	eg := q.Wrap(inner)
+	ctx := context.Background()
+
+	done := make(chan error, 1)
+	go func() { done <- eg.SendText(ctx, "r1", "first") }()
+	waitDepth(t, q, "r1", 1)
+	if err := eg.SendText(ctx, "r1", "second"); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("err = %v, want ErrQueueFull", err)
+	}
+	if st := q.Stats(); st.Depth != 1 || st.Rooms["r1"] != 1 || st.Dropped != 1 {
+		t.Fatalf("stats = %+v", st)
+	}
+	close(inner.block)
+	if err := <-done; err != nil {
+		t.Fatalf("first: %v", err)
+	}
+}
+
+func waitDepth(t *testing.T, q *Queue, room string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for q.Depth(room) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("depth(%s) never reached %d", room, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
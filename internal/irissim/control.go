@@ -0,0 +1,160 @@
+package irissim
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irisfast"
+)
+
+// ControlPrefix is where the control API is mounted next to the Iris endpoints.
+const ControlPrefix = "/_sim/"
+
+// Step is one scripted action: a chat message, a fault change, or a socket drop.
+// 스크립트 파일은 한 줄에 Step JSON 하나이며, 빈 줄과 #으로 시작하는 줄은 무시합니다.
+type Step struct {
+	WaitMS int `json:"wait_ms,omitempty"` // 실행 전 대기
+
+	Room   string `json:"room,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+	Sender string `json:"sender,omitempty"`
+	Msg    string `json:"msg,omitempty"`
+
+	Fault *FaultSpec `json:"fault,omitempty"`
+	Drop  bool       `json:"drop,omitempty"`
+}
+
+// FaultSpec is the JSON form of a Fault for the control API and scripts.
+type FaultSpec struct {
+	Path    string `json:"path"`
+	Status  int    `json:"status,omitempty"`
+	DelayMS int    `json:"delay_ms,omitempty"`
+	Count   int    `json:"count,omitempty"`
+}
+
+func (f FaultSpec) fault() Fault {
+	return Fault{Status: f.Status, Delay: time.Duration(f.DelayMS) * time.Millisecond, Count: f.Count}
+}
+
+// Run applies one step and returns how many clients received its message (0 for non-message steps).
+func (s *Server) Run(ctx context.Context, st Step) (int, error) {
+	if st.WaitMS > 0 {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Duration(st.WaitMS) * time.Millisecond):
+		}
+	}
+	switch {
+	case st.Fault != nil:
+		if strings.TrimSpace(st.Fault.Path) == "" {
+			return 0, fmt.Errorf("fault path required")
+		}
+		s.SetFault(st.Fault.Path, st.Fault.fault())
+		return 0, nil
+	case st.Drop:
+		s.DropSockets()
+		return 0, nil
+	case st.Msg != "":
+		if strings.TrimSpace(st.Room) == "" || strings.TrimSpace(st.UserID) == "" {
+			return 0, fmt.Errorf("room and user_id required")
+		}
+		return s.Say(ctx, st.Room, st.UserID, st.Sender, st.Msg), nil
+	}
+	return 0, nil
+}
+
+// RunScript runs JSON-lines steps from r in order.
+func (s *Server) RunScript(ctx context.Context, r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		raw := strings.TrimSpace(sc.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+		var st Step
+		if err := json.Unmarshal([]byte(raw), &st); err != nil {
+			return fmt.Errorf("script line %d: %w", line, err)
+		}
+		if _, err := s.Run(ctx, st); err != nil {
+			return fmt.Errorf("script line %d: %w", line, err)
+		}
+	}
+	return sc.Err()
+}
+
+// ControlHandler serves the scripting API under ControlPrefix:
+//
+//	POST   /_sim/step     Step JSON (say / fault / drop)
+//	POST   /_sim/inject   raw irisfast.Message
+//	GET    /_sim/replies  recorded replies (?room= to filter)
+//	DELETE /_sim/replies  forget recorded replies
+//	DELETE /_sim/faults   clear faults
+func (s *Server) ControlHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ControlPrefix+"step", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var st Step
+		if err := json.NewDecoder(r.Body).Decode(&st); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		n, err := s.Run(r.Context(), st)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]int{"delivered": n, "connections": s.Connections()})
+	})
+	mux.HandleFunc(ControlPrefix+"inject", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var msg irisfast.Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]int{"delivered": s.Inject(r.Context(), msg)})
+	})
+	mux.HandleFunc(ControlPrefix+"replies", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			out := s.Replies()
+			if room := r.URL.Query().Get("room"); room != "" {
+				out = s.RepliesTo(room)
+			}
+			if out == nil {
+				out = []Reply{}
+			}
+			writeJSON(w, out)
+		case http.MethodDelete:
+			s.Reset()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc(ControlPrefix+"faults", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.ClearFaults()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
@@ -0,0 +1,392 @@
+// Package irissim is a local stand-in for an Iris instance.
+//
+// It serves the endpoints the bot uses (/reply, /decrypt, /config and the /ws message stream),
+// injects inbound chat messages as arbitrary senders and rooms, records every outbound
+// text/image (HTTP or WebSocket), and can inject faults (5xx, slow responses, refused or
+// dropped sockets) to exercise irisfast.Client retries and WebSocket reconnects.
+package irissim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irisfast"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// Paths served by the simulator.
+const (
+	PathReply   = "/reply"
+	PathDecrypt = "/decrypt"
+	PathConfig  = "/config"
+	PathWS      = "/ws"
+)
+
+// Reply is one outbound message the bot sent to a room.
+type Reply struct {
+	Type string    `json:"type"` // "text" | "image"
+	Room string    `json:"room"`
+	Data string    `json:"data"`
+	Via  string    `json:"via"` // "http" | "ws"
+	At   time.Time `json:"at"`
+}
+
+// Fault changes how the next requests to a path are answered.
+// /ws에 Status를 주면 핸드셰이크를 거부하고, Delay를 주면 핸드셰이크를 늦춥니다.
+type Fault struct {
+	Status int // 0이면 정상 응답
+	Delay  time.Duration
+	Count  int // 적용 횟수, 0 이하이면 해제할 때까지 계속
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithConfig sets the /config response.
+func WithConfig(cfg irisfast.Config) Option {
+	return func(s *Server) { s.config = cfg }
+}
+
+// WithDecrypt sets the /decrypt implementation (default: returns the input unchanged).
+func WithDecrypt(fn func(string) (string, error)) Option {
+	return func(s *Server) {
+		if fn != nil {
+			s.decrypt = fn
+		}
+	}
+}
+
+// Server is an in-memory Iris simulator. Handler로 임의의 http.Server에 붙이거나 StartTest로 테스트에서 띄웁니다.
+type Server struct {
+	config  irisfast.Config
+	decrypt func(string) (string, error)
+
+	mu       sync.Mutex
+	replies  []Reply
+	faults   map[string]*Fault
+	requests map[string]int
+	conns    map[*wsConn]struct{}
+	changed  chan struct{} // replies/conns 변경 시 닫고 새로 만듦 (Wait* 깨우기)
+}
+
+type wsConn struct {
+	c  *websocket.Conn
+	mu sync.Mutex // wsjson.Write는 동시 호출 불가
+}
+
+// NewServer creates a simulator with no recorded traffic.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		config:   irisfast.Config{Port: 3000, PollingSpeed: 100, MessageRate: 50, WebserverEndpoint: "http://127.0.0.1/"},
+		decrypt:  func(v string) (string, error) { return v, nil },
+		faults:   map[string]*Fault{},
+		requests: map[string]int{},
+		conns:    map[*wsConn]struct{}{},
+		changed:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Handler returns the Iris API handler (/reply, /decrypt, /config, /ws).
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathReply, s.handleReply)
+	mux.HandleFunc(PathDecrypt, s.handleDecrypt)
+	mux.HandleFunc(PathConfig, s.handleConfig)
+	mux.HandleFunc(PathWS, s.handleWS)
+	return mux
+}
+
+// SetFault installs f for path, replacing any previous fault.
+func (s *Server) SetFault(path string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := f
+	s.faults[path] = &cp
+}
+
+// ClearFaults removes every installed fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = map[string]*Fault{}
+}
+
+// Requests returns how many requests reached path (including faulted ones).
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// Replies returns a copy of every recorded outbound message in arrival order.
+func (s *Server) Replies() []Reply {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Reply(nil), s.replies...)
+}
+
+// RepliesTo returns the recorded outbound messages for room.
+func (s *Server) RepliesTo(room string) []Reply {
+	var out []Reply
+	for _, r := range s.Replies() {
+		if r.Room == room {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Reset forgets recorded replies and request counters (faults and sockets are kept).
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replies = nil
+	s.requests = map[string]int{}
+	s.notifyLocked()
+}
+
+// WaitReplies blocks until at least n replies were recorded in total and returns them.
+func (s *Server) WaitReplies(ctx context.Context, n int) ([]Reply, error) {
+	for {
+		s.mu.Lock()
+		if len(s.replies) >= n {
+			out := append([]Reply(nil), s.replies...)
+			s.mu.Unlock()
+			return out, nil
+		}
+		ch := s.changed
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return s.Replies(), ctx.Err()
+		case <-ch:
+		}
+	}
+}
+
+// Connections returns the number of open WebSocket clients.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// WaitConnections blocks until at least n WebSocket clients are connected.
+func (s *Server) WaitConnections(ctx context.Context, n int) error {
+	for {
+		s.mu.Lock()
+		if len(s.conns) >= n {
+			s.mu.Unlock()
+			return nil
+		}
+		ch := s.changed
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ch:
+		}
+	}
+}
+
+// Inject broadcasts msg to every connected WebSocket client and returns how many received it.
+func (s *Server) Inject(ctx context.Context, msg irisfast.Message) int {
+	s.mu.Lock()
+	conns := make([]*wsConn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+	n := 0
+	for _, c := range conns {
+		c.mu.Lock()
+		err := wsjson.Write(ctx, c.c, &msg)
+		c.mu.Unlock()
+		if err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Say injects a chat message in the shape Iris produces: room id in json.room_id/chat_id, sender name and user id.
+func (s *Server) Say(ctx context.Context, room, userID, sender, text string) int {
+	return s.Inject(ctx, Message(room, userID, sender, text))
+}
+
+// Message builds an inbound Iris message.
+func Message(room, userID, sender, text string) irisfast.Message {
+	name := strings.TrimSpace(sender)
+	if name == "" {
+		name = userID
+	}
+	return irisfast.Message{
+		Msg:    text,
+		Room:   room,
+		Sender: &name,
+		JSON: &irisfast.MessageJSON{
+			UserID:    userID,
+			Message:   text,
+			RoomID:    room,
+			ChatID:    room,
+			Type:      "1",
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+}
+
+// DropSockets closes every WebSocket abruptly (no close frame), like a phone losing its network.
+func (s *Server) DropSockets() int {
+	s.mu.Lock()
+	conns := make([]*wsConn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.conns = map[*wsConn]struct{}{}
+	s.notifyLocked()
+	s.mu.Unlock()
+	for _, c := range conns {
+		_ = c.c.CloseNow()
+	}
+	return len(conns)
+}
+
+// takeFault counts the request and returns the fault to apply, if any.
+func (s *Server) takeFault(path string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[path]++
+	f := s.faults[path]
+	if f == nil {
+		return nil
+	}
+	cp := *f
+	if f.Count > 0 {
+		f.Count--
+		if f.Count == 0 {
+			delete(s.faults, path)
+		}
+	}
+	return &cp
+}
+
+// applyFault sleeps for the fault delay and writes the fault status; it reports whether the request was answered.
+func applyFault(w http.ResponseWriter, r *http.Request, f *Fault) bool {
+	if f == nil {
+		return false
+	}
+	if f.Delay > 0 {
+		select {
+		case <-time.After(f.Delay):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+	if f.Status != 0 {
+		http.Error(w, "irissim: injected fault", f.Status)
+		return true
+	}
+	return false
+}
+
+func (s *Server) record(r Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r.At = time.Now()
+	s.replies = append(s.replies, r)
+	s.notifyLocked()
+}
+
+func (s *Server) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *Server) handleReply(w http.ResponseWriter, r *http.Request) {
+	if applyFault(w, r, s.takeFault(PathReply)) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req irisfast.ReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Room == "" {
+		http.Error(w, "bad reply request", http.StatusBadRequest)
+		return
+	}
+	s.record(Reply{Type: req.Type, Room: req.Room, Data: req.Data, Via: "http"})
+	writeJSON(w, map[string]bool{"success": true})
+}
+
+func (s *Server) handleDecrypt(w http.ResponseWriter, r *http.Request) {
+	if applyFault(w, r, s.takeFault(PathDecrypt)) {
+		return
+	}
+	var req irisfast.DecryptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad decrypt request", http.StatusBadRequest)
+		return
+	}
+	out, err := s.decrypt(req.Data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, irisfast.DecryptResponse{Decrypted: out})
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if applyFault(w, r, s.takeFault(PathConfig)) {
+		return
+	}
+	writeJSON(w, s.config)
+}
+
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+	if applyFault(w, r, s.takeFault(PathWS)) {
+		return
+	}
+	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{InsecureSkipVerify: true})
+	if err != nil {
+		return
+	}
+	conn := &wsConn{c: c}
+	s.mu.Lock()
+	s.conns[conn] = struct{}{}
+	s.notifyLocked()
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		if _, ok := s.conns[conn]; ok {
+			delete(s.conns, conn)
+			s.notifyLocked()
+		}
+		s.mu.Unlock()
+		_ = c.CloseNow()
+	}()
+	// 봇이 WS로 보내는 ReplyRequest(wsEgress)를 기록; 읽기를 계속해야 ping/close 프레임도 처리됨
+	for {
+		var req irisfast.ReplyRequest
+		if err := wsjson.Read(r.Context(), c, &req); err != nil {
+			return
+		}
+		if req.Room != "" && (req.Type == "text" || req.Type == "image") {
+			s.record(Reply{Type: req.Type, Room: req.Room, Data: req.Data, Via: "ws"})
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
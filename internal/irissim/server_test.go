@@ -0,0 +1,104 @@
+package irissim
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/irisfast"
+)
+
+func TestClientRetriesAndTimeouts(t *testing.T) {
+	sim := StartTest(t)
+	ctx := context.Background()
+	client := irisfast.NewClient(sim.URL, irisfast.WithTimeout(500*time.Millisecond))
+
+	// /decrypt는 재시도 대상: 503 두 번 후 성공
+	sim.SetFault(PathDecrypt, Fault{Status: 503, Count: 2})
+	if out, err := client.Decrypt(ctx, "secret"); err != nil || out != "secret" {
+		t.Fatalf("Decrypt: %q %v", out, err)
+	}
+	if n := sim.Requests(PathDecrypt); n != 3 {
+		t.Fatalf("expected 3 decrypt attempts, got %d", n)
+	}
+
+	// /reply는 재시도하지 않음
+	sim.SetFault(PathReply, Fault{Status: 500, Count: 1})
+	if err := client.SendMessage(ctx, "room1", "hello"); err == nil {
+		t.Fatalf("expected reply error on injected 500")
+	}
+	if err := client.SendMessage(ctx, "room1", "hello"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if got := sim.RepliesTo("room1"); len(got) != 1 || got[0].Data != "hello" || got[0].Via != "http" {
+		t.Fatalf("unexpected replies: %+v", got)
+	}
+
+	sim.SetFault(PathConfig, Fault{Delay: time.Second, Count: 1})
+	if _, err := client.GetConfig(ctx); err == nil {
+		t.Fatalf("expected timeout on slow /config")
+	}
+	if cfg, err := client.GetConfig(ctx); err != nil || cfg.Port == 0 {
+		t.Fatalf("GetConfig: %+v %v", cfg, err)
+	}
+}
+
+func TestWebSocketStreamAndReconnect(t *testing.T) {
+	sim := StartTest(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ws := irisfast.NewWebSocket(sim.WSURL, 5, 10*time.Millisecond)
+	got := make(chan *irisfast.Message, 4)
+	ws.OnMessage(func(m *irisfast.Message) { got <- m })
+	connected := make(chan struct{}, 4)
+	ws.OnStateChange(func(s irisfast.WebSocketState) {
+		if s == irisfast.WSStateConnected {
+			connected <- struct{}{}
+		}
+	})
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer ws.Close(context.Background())
+	<-connected
+	if err := sim.WaitConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitConnections: %v", err)
+	}
+
+	sim.Say(ctx, "room1", "u1", "Alice", "!체스 도움")
+	select {
+	case m := <-got:
+		if m.JSON == nil || m.JSON.UserID != "u1" || m.Msg != "!체스 도움" || *m.Sender != "Alice" {
+			t.Fatalf("unexpected message: %+v", m)
+		}
+	case <-ctx.Done():
+		t.Fatalf("message not delivered")
+	}
+
+	// 소켓 끊김 + 첫 재연결 거부 → 클라이언트가 다시 붙어야 함
+	sim.SetFault(PathWS, Fault{Status: 503, Count: 1})
+	if n := sim.DropSockets(); n != 1 {
+		t.Fatalf("expected one dropped socket, got %d", n)
+	}
+	select {
+	case <-connected:
+	case <-ctx.Done():
+		t.Fatalf("client did not reconnect")
+	}
+	if err := sim.WaitConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitConnections after reconnect: %v", err)
+	}
+	if n := sim.Requests(PathWS); n < 3 {
+		t.Fatalf("expected a refused handshake before reconnecting, got %d handshakes", n)
+	}
+
+	eg := irisfast.NewEgress("ws", false, nil, ws, nil)
+	if err := eg.SendText(ctx, "room2", "pong"); err != nil {
+		t.Fatalf("ws SendText: %v", err)
+	}
+	replies, err := sim.WaitReplies(ctx, 1)
+	if err != nil || replies[0].Room != "room2" || replies[0].Via != "ws" {
+		t.Fatalf("unexpected ws replies: %+v %v", replies, err)
+	}
+}
@@ -0,0 +1,31 @@
+package irissim
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestServer is a Server listening on a loopback port for the duration of a test.
+type TestServer struct {
+	*Server
+	// URL is the Iris base URL for irisfast.NewClient; WSURL is the message stream for irisfast.NewWebSocket.
+	URL   string
+	WSURL string
+}
+
+// StartTest starts a simulator on a random loopback port and stops it when tb finishes.
+func StartTest(tb testing.TB, opts ...Option) *TestServer {
+	tb.Helper()
+	s := NewServer(opts...)
+	hs := httptest.NewServer(s.Handler())
+	tb.Cleanup(func() {
+		s.DropSockets()
+		hs.Close()
+	})
+	return &TestServer{
+		Server: s,
+		URL:    hs.URL,
+		WSURL:  "ws" + strings.TrimPrefix(hs.URL, "http") + PathWS,
+	}
+}
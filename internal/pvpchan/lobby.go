@@ -0,0 +1,184 @@
+package pvpchan
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// errLobbyTaken signals that the lobby started or vanished between lookup and removal.
+var errLobbyTaken = errors.New("lobby_taken")
+
+// CancelLobby removes the user's own waiting lobby (ch:lobby, 사용자 인덱스, 메타 모두 삭제).
+func (m *Manager) CancelLobby(ctx context.Context, userID string) (*ChannelMeta, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, ErrInvalidArgs
+	}
+	codes, err := m.store.CodesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range codes {
+		meta, _ := m.store.LoadMeta(ctx, c)
+		if meta == nil || meta.State != StateLobby || strings.TrimSpace(meta.CreatorID) != userID {
+			continue
+		}
+		if err := m.removeLobby(ctx, c); err != nil {
+			if errors.Is(err, errLobbyTaken) {
+				continue
+			}
+			return nil, err
+		}
+		obslog.L().Info("lobby_cancel", zap.String("code", c), zap.String("creator_id", userID))
+		return meta, nil
+	}
+	return nil, ErrNoLobby
+}
+
+// SweepExpiredLobbies removes lobbies that waited longer than maxAge without an opponent.
+// 만료된 대기방 메타를 반환하며(생성자 방 안내용), 메타가 사라진 코드는 목록에서만 정리합니다.
+func (m *Manager) SweepExpiredLobbies(ctx context.Context, maxAge time.Duration) ([]*ChannelMeta, error) {
+	if maxAge <= 0 {
+		return nil, nil
+	}
+	codes, err := m.rdb.SMembers(ctx, m.store.keyLobby()).Result()
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().Add(-maxAge)
+	var out []*ChannelMeta
+	for _, c := range codes {
+		meta, err := m.store.LoadMeta(ctx, c)
+		if err != nil {
+			continue
+		}
+		if meta == nil || meta.State != StateLobby {
+			_ = m.store.RemoveLobby(ctx, c)
+			continue
+		}
+		if !meta.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if err := m.removeLobby(ctx, c); err != nil {
+			if !errors.Is(err, errLobbyTaken) {
+				obslog.L().Warn("lobby_expire_error", zap.String("code", c), zap.Error(err))
+			}
+			continue
+		}
+		obslog.L().Info("lobby_expire", zap.String("code", c), zap.String("creator_id", meta.CreatorID), zap.Time("created_at", meta.CreatedAt))
+		out = append(out, meta)
+	}
+	return out, nil
+}
+
+// StartLobbySweeper runs SweepExpiredLobbies every interval until ctx is done.
+// onExpire는 만료된 대기방마다 호출됩니다(생성자 방 안내 등).
+func (m *Manager) StartLobbySweeper(ctx context.Context, maxAge, interval time.Duration, onExpire func(*ChannelMeta)) {
+	if m == nil || maxAge <= 0 || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				metas, err := m.SweepExpiredLobbies(ctx, maxAge)
+				if err != nil {
+					obslog.L().Warn("lobby_sweep_error", zap.Error(err))
+				}
+				if onExpire == nil {
+					continue
+				}
+				for _, meta := range metas {
+					onExpire(meta)
+				}
+			}
+		}
+	}()
+}
+
+// removeLobby deletes a still-waiting lobby under WATCH so a concurrent Join cannot start it halfway.
+func (m *Manager) removeLobby(ctx context.Context, code string) error {
+	metaKey := m.store.keyMeta(code)
+	partKey := m.store.keyParticipants(code)
+	err := m.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		meta, err := m.store.LoadMeta(ctx, code)
+		if err != nil {
+			return err
+		}
+		if meta == nil || meta.State != StateLobby {
+			return errLobbyTaken
+		}
+		users, err := tx.SMembers(ctx, partKey).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if len(users) >= 2 {
+			return errLobbyTaken
+		}
+		pipe := tx.TxPipeline()
+		pipe.SRem(ctx, m.store.keyLobby(), code)
+		for _, u := range users {
+			pipe.SRem(ctx, m.store.keyUserIdx(u), code)
+		}
+		pipe.SRem(ctx, m.store.keyUserIdx(meta.CreatorID), code)
+		pipe.Del(ctx, metaKey, partKey, m.store.keyRooms(code), m.store.keyPINFails(code))
+		_, pErr := pipe.Exec(ctx)
+		return pErr
+	}, metaKey, partKey)
+	if errors.Is(err, redis.TxFailedErr) {
+		return errLobbyTaken
+	}
+	return err
+}
+
+// checkPIN verifies pin against the lobby's hash, locking the code after maxPINFailures wrong attempts.
+func (m *Manager) checkPIN(ctx context.Context, meta *ChannelMeta, pin string) error {
+	if n, err := m.store.PINFailures(ctx, meta.ID); err != nil {
+		return err
+	} else if n >= maxPINFailures {
+		return ErrPINLocked
+	}
+	pin = strings.TrimSpace(pin)
+	if pin == "" {
+		return ErrPINRequired
+	}
+	if subtle.ConstantTimeCompare([]byte(hashPIN(meta.ID, pin)), []byte(meta.PINHash)) == 1 {
+		return nil
+	}
+	if _, err := m.store.RecordPINFailure(ctx, meta.ID); err != nil {
+		return err
+	}
+	return ErrWrongPIN
+}
+
+// normalizePIN trims pin and validates its length; "" means no PIN.
+func normalizePIN(pin string) (string, error) {
+	pin = strings.TrimSpace(pin)
+	if pin == "" {
+		return "", nil
+	}
+	if n := utf8.RuneCountInString(pin); n < 4 || n > 12 || strings.ContainsAny(pin, " \t\n") {
+		return "", ErrInvalidPIN
+	}
+	return pin, nil
+}
+
+// hashPIN salts the PIN with the lobby code so the stored meta never holds it in plain text.
+func hashPIN(code, pin string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(code) + ":" + pin))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,7 +2,9 @@ package pvpchan
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,12 +25,22 @@ func NewManager(rdb *redis.Client, pvp *pvpchess.Manager) *Manager {
 }
 
 func (m *Manager) Make(ctx context.Context, room, userID, userName string, color ColorChoice) (*MakeResult, error) {
+	return m.MakeLobby(ctx, room, userID, userName, LobbyOptions{Color: color})
+}
+
+// MakeLobby creates a lobby with visibility and join PIN options.
+// Unlisted 대기방도 ch:lobby에 올라가(만료 정리 대상) 목록 조회에서만 제외됩니다.
+func (m *Manager) MakeLobby(ctx context.Context, room, userID, userName string, opts LobbyOptions) (*MakeResult, error) {
     if strings.TrimSpace(room) == "" || strings.TrimSpace(userID) == "" {
         return nil, ErrInvalidArgs
     }
-    // 동시성: 플레이어가 동일 방에서 이미 진행 중인 대국이 있으면 채널 생성 금지
-    if g, _ := m.pvp.GetActiveGameByUserInRoom(ctx, userID, room); g != nil {
-        return nil, ErrPlayerBusyInRoom
+    pin, err := normalizePIN(opts.PIN)
+    if err != nil {
+        return nil, err
+    }
+    // 동시 대국 상한: 이미 최대 개수의 대국을 진행 중이면 채널 생성 금지
+    if m.atGameLimit(ctx, userID) {
+        return nil, ErrPlayerBusy
     }
     // 동일 사용자의 중복 대기방 생성 금지: 사용자 인덱스의 코드 중 LOBBY 상태가 있는지 검사
     if codes, err := m.store.CodesByUser(ctx, userID); err == nil {
@@ -40,47 +52,64 @@ func (m *Manager) Make(ctx context.Context, room, userID, userName string, color
             }
         }
     }
-	// generate unique code
-	var code string
+	code, err := m.reserveCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	meta := &ChannelMeta{
+		ID:             code,
+		State:          StateLobby,
+		CreatedAt:      time.Now(),
+		CreatorID:      userID,
+		CreatorName:    userName,
+		CreatorRoom:    room,
+		CreatorColor:   normalizeColor(opts.Color),
+		Unlisted:       opts.Unlisted,
+		Correspondence: opts.Correspondence,
+	}
+	if pin != "" {
+		meta.PINHash = hashPIN(code, pin)
+	}
+	if err := m.store.SaveMeta(ctx, code, meta); err != nil {
+		return nil, err
+	}
+	if err := m.store.AddRoom(ctx, code, room, channelTTL(meta)); err != nil {
+		return nil, err
+	}
+	if err := m.store.AddParticipant(ctx, code, userID, channelTTL(meta)); err != nil {
+		return nil, err
+	}
+	if err := m.store.AddLobby(ctx, code); err != nil {
+		return nil, err
+	}
+	obslog.L().Info("lobby_make", zap.String("code", code), zap.String("room", room), zap.String("creator_id", userID), zap.Bool("unlisted", meta.Unlisted), zap.Bool("pin", pin != ""), zap.Bool("correspondence", meta.Correspondence))
+	return &MakeResult{Code: code, Meta: meta}, nil
+}
+
+// reserveCode allocates a unique channel code by claiming ch:<code> with SETNX.
+func (m *Manager) reserveCode(ctx context.Context) (string, error) {
 	for i := 0; i < 5; i++ {
 		c, err := codeGen()
 		if err != nil {
-			return nil, err
-		}
-		meta := &ChannelMeta{
-			ID:          c,
-			State:       StateLobby,
-			CreatedAt:   time.Now(),
-			CreatorID:   userID,
-			CreatorName: userName,
-			CreatorRoom: room,
+			return "", err
 		}
 		ok, err := m.rdb.SetNX(ctx, m.store.keyMeta(c), []byte("{}"), ttlChannel).Result()
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		if ok {
-			code = c
-			if err := m.store.SaveMeta(ctx, code, meta); err != nil {
-				return nil, err
-			}
-			if err := m.store.AddRoom(ctx, code, room); err != nil {
-				return nil, err
-			}
-			if err := m.store.AddParticipant(ctx, code, userID); err != nil {
-				return nil, err
-			}
-			if err := m.store.AddLobby(ctx, code); err != nil {
-				return nil, err
-			}
-			obslog.L().Info("lobby_make", zap.String("code", code), zap.String("room", room), zap.String("creator_id", userID))
-			return &MakeResult{Code: code, Meta: meta}, nil
+			return c, nil
 		}
 	}
-	return nil, fmt.Errorf("failed to allocate channel code")
+	return "", fmt.Errorf("failed to allocate channel code")
 }
 
 func (m *Manager) Join(ctx context.Context, room, code, userID, userName string, pref ColorChoice) (*JoinResult, error) {
+	return m.JoinWithPIN(ctx, room, code, userID, userName, pref, "")
+}
+
+// JoinWithPIN joins a lobby, checking pin when the lobby was made with one.
+func (m *Manager) JoinWithPIN(ctx context.Context, room, code, userID, userName string, pref ColorChoice, pin string) (*JoinResult, error) {
 	code = strings.TrimSpace(code)
 	if room == "" || code == "" || userID == "" {
 		return nil, ErrInvalidArgs
@@ -96,9 +125,16 @@ func (m *Manager) Join(ctx context.Context, room, code, userID, userName string,
 	if meta.State != StateLobby {
 		return nil, ErrChannelActive
 	}
+	if meta.PINHash != "" && strings.TrimSpace(meta.CreatorID) != strings.TrimSpace(userID) {
+		if err := m.checkPIN(ctx, meta, pin); err != nil {
+			obslog.L().Info("lobby_join_pin_rejected", zap.String("code", code), zap.String("user_id", userID), zap.Error(err))
+			return nil, err
+		}
+	}
 
 	// WATCH participants to prevent race joins
 	partKey := m.store.keyParticipants(code)
+	ttl := channelTTL(meta)
 	err = m.rdb.Watch(ctx, func(tx *redis.Tx) error {
 		cnt, err := tx.SCard(ctx, partKey).Result()
 		if err != nil && err != redis.Nil {
@@ -109,21 +145,23 @@ func (m *Manager) Join(ctx context.Context, room, code, userID, userName string,
 		}
 		pipe := tx.TxPipeline()
 		pipe.SAdd(ctx, partKey, userID)
-		pipe.Expire(ctx, partKey, ttlChannel)
+		pipe.Expire(ctx, partKey, ttl)
 		pipe.SAdd(ctx, m.store.keyRooms(code), room)
-		pipe.Expire(ctx, m.store.keyRooms(code), ttlChannel)
+		pipe.Expire(ctx, m.store.keyRooms(code), ttl)
 		pipe.SAdd(ctx, m.store.keyUserIdx(userID), code)
-		pipe.Expire(ctx, m.store.keyUserIdx(userID), ttlChannel)
 		_, pErr := pipe.Exec(ctx)
 		return pErr
 	}, partKey)
+	if err == nil {
+		err = m.store.touchUserIdx(ctx, userID, ttl)
+	}
 	if err != nil {
 		obslog.L().Warn("lobby_join_error", zap.String("code", code), zap.String("room", room), zap.String("user_id", userID), zap.Error(err))
 		return nil, err
 	}
 
 	// reload meta and decide start
-	if err := m.store.AddRoom(ctx, code, room); err != nil {
+	if err := m.store.AddRoom(ctx, code, room, ttl); err != nil {
 		return nil, err
 	}
 	meta, err = m.store.LoadMeta(ctx, code)
@@ -143,20 +181,21 @@ func (m *Manager) Join(ctx context.Context, room, code, userID, userName string,
 	// 참가자는 호출자(userID), 도전자는 생성자(meta.CreatorID)
 	challengerID, challengerName := meta.CreatorID, meta.CreatorName
 	targetID, targetName := userID, userName
-	// 색 선호 입력은 무시하고 항상 랜덤 배정
-	colorChoice := string(ColorRandom)
+	// 생성자 선호 우선, 참가자 선호는 생성자가 랜덤일 때 반영 (같은 색 충돌 시 랜덤)
+	colorChoice := string(resolveCreatorColor(meta.CreatorColor, pref))
 
-	// 방 기준 중복 대국 금지: 참가자/생성자 각각 자신의 방에서 ACTIVE 대국이 있는지 검사
-	if busy, _ := m.pvp.GetActiveGameByUserInRoom(ctx, userID, room); busy != nil {
-		return nil, ErrPlayerBusyInRoom
-	}
-	if busy2, _ := m.pvp.GetActiveGameByUserInRoom(ctx, challengerID, meta.CreatorRoom); busy2 != nil {
-		return nil, ErrPlayerBusyInRoom
+	// 동시 대국 상한: 참가자/생성자 모두 새 대국을 시작할 수 있어야 함
+	if m.atGameLimit(ctx, userID) || m.atGameLimit(ctx, challengerID) {
+		return nil, ErrPlayerBusy
 	}
 
-	g, gerr := m.pvp.CreateGameFromChallenge(ctx, meta.CreatorRoom, room, challengerID, challengerName, targetID, targetName, colorChoice, "")
+	timeControl := ""
+	if meta.Correspondence {
+		timeControl = pvpchess.TimeControlCorrespondence
+	}
+	g, gerr := m.pvp.CreateGameFromChallenge(ctx, meta.CreatorRoom, room, challengerID, challengerName, targetID, targetName, colorChoice, timeControl)
 	if gerr != nil {
-		return nil, gerr
+		return nil, createError(gerr)
 	}
 
 	// persist meta with colors and game id
@@ -182,6 +221,33 @@ func (m *Manager) Rooms(ctx context.Context, code string) ([]string, error) {
     return m.store.Rooms(ctx, code)
 }
 
+// ListLobbyScoped lists open lobbies visible to room under scope, newest first.
+// ScopeAllowed에서 allowed가 비어 있으면(모든 방 허용) 전체 목록과 같습니다.
+func (m *Manager) ListLobbyScoped(ctx context.Context, scope LobbyScope, room string, allowed []string) ([]*ChannelMeta, error) {
+	metas, err := m.store.ListLobby(ctx)
+	if err != nil {
+		return nil, err
+	}
+	room = strings.TrimSpace(room)
+	out := metas[:0]
+	for _, meta := range metas {
+		switch scope {
+		case ScopeGlobal:
+		case ScopeAllowed:
+			if len(allowed) > 0 && !containsRoom(allowed, strings.TrimSpace(meta.CreatorRoom)) {
+				continue
+			}
+		default:
+			if strings.TrimSpace(meta.CreatorRoom) != room {
+				continue
+			}
+		}
+		out = append(out, meta)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
 // RoomsByUserAndGame finds channel rooms for a user where its channel binds the given game.
 func (m *Manager) RoomsByUserAndGame(ctx context.Context, userID, gameID string) ([]string, error) {
 	codes, err := m.store.CodesByUser(ctx, userID)
@@ -218,3 +284,318 @@ func (m *Manager) MetaByGame(ctx context.Context, g *pvpchess.Game) (*ChannelMet
     }
     return nil, "", nil
 }
+
+// Spectate subscribes room to the fanout list of an ACTIVE channel (read-only).
+// limit는 대국당 관전 방 최대 수이며 0 이하이면 제한하지 않습니다.
+func (m *Manager) Spectate(ctx context.Context, room, code string, limit int) (*ChannelMeta, error) {
+    room = strings.TrimSpace(room)
+    code = strings.TrimSpace(code)
+    if room == "" || code == "" {
+        return nil, ErrInvalidArgs
+    }
+    meta, err := m.store.LoadMeta(ctx, code)
+    if err != nil {
+        return nil, err
+    }
+    if meta == nil {
+        return nil, ErrChannelGone
+    }
+    if meta.State != StateActive || strings.TrimSpace(meta.GameID) == "" {
+        return nil, ErrNotInProgress
+    }
+    if g, _ := m.pvp.LoadGame(ctx, meta.GameID); g == nil || g.Status != pvpchess.StatusActive {
+        return nil, ErrNotInProgress
+    }
+    if rooms, _ := m.store.Rooms(ctx, code); containsRoom(rooms, room) {
+        return nil, ErrRoomIsPlayerRoom
+    }
+    if cur, _ := m.store.SpectatingCode(ctx, room); cur != "" && cur != code {
+        // 이전 관전 대상이 끝났으면 교체 허용
+        if prev, _ := m.SpectatedGame(ctx, room); prev != nil {
+            return nil, ErrAlreadySpectating
+        }
+        _ = m.rdb.SRem(ctx, m.store.keySpectators(cur), room).Err()
+    }
+
+    specKey := m.store.keySpectators(code)
+    err = m.rdb.Watch(ctx, func(tx *redis.Tx) error {
+        isMember, err := tx.SIsMember(ctx, specKey, room).Result()
+        if err != nil && err != redis.Nil {
+            return err
+        }
+        if !isMember && limit > 0 {
+            cnt, err := tx.SCard(ctx, specKey).Result()
+            if err != nil && err != redis.Nil {
+                return err
+            }
+            if cnt >= int64(limit) {
+                return ErrSpectatorLimit
+            }
+        }
+        pipe := tx.TxPipeline()
+        pipe.SAdd(ctx, specKey, room)
+        pipe.Expire(ctx, specKey, channelTTL(meta))
+        pipe.Set(ctx, m.store.keySpectateRoom(room), code, channelTTL(meta))
+        _, pErr := pipe.Exec(ctx)
+        return pErr
+    }, specKey)
+    if err != nil {
+        obslog.L().Warn("spectate_error", zap.String("code", code), zap.String("room", room), zap.Error(err))
+        return nil, err
+    }
+    obslog.L().Info("spectate_start", zap.String("code", code), zap.String("room", room), zap.String("game_id", meta.GameID))
+    return meta, nil
+}
+
+// Unspectate removes room from the channel it is spectating and returns that channel code.
+func (m *Manager) Unspectate(ctx context.Context, room string) (string, error) {
+    room = strings.TrimSpace(room)
+    if room == "" {
+        return "", ErrInvalidArgs
+    }
+    code, err := m.store.SpectatingCode(ctx, room)
+    if err != nil {
+        return "", err
+    }
+    if code == "" {
+        return "", ErrNotSpectating
+    }
+    pipe := m.rdb.TxPipeline()
+    pipe.SRem(ctx, m.store.keySpectators(code), room)
+    pipe.Del(ctx, m.store.keySpectateRoom(room))
+    if _, err := pipe.Exec(ctx); err != nil {
+        return "", err
+    }
+    obslog.L().Info("spectate_stop", zap.String("code", code), zap.String("room", room))
+    return code, nil
+}
+
+// SpectatorRooms returns rooms subscribed to the channel as spectators.
+func (m *Manager) SpectatorRooms(ctx context.Context, code string) ([]string, error) {
+    return m.store.SpectatorRooms(ctx, code)
+}
+
+// SpectatedGame returns the ACTIVE game the room is spectating, or nil.
+func (m *Manager) SpectatedGame(ctx context.Context, room string) (*pvpchess.Game, error) {
+    code, err := m.store.SpectatingCode(ctx, room)
+    if err != nil || code == "" {
+        return nil, err
+    }
+    meta, err := m.store.LoadMeta(ctx, code)
+    if err != nil || meta == nil || strings.TrimSpace(meta.GameID) == "" {
+        return nil, err
+    }
+    g, err := m.pvp.LoadGame(ctx, meta.GameID)
+    if err != nil || g == nil || g.Status != pvpchess.StatusActive {
+        return nil, err
+    }
+    return g, nil
+}
+
+func containsRoom(rooms []string, room string) bool {
+    for _, r := range rooms {
+        if strings.TrimSpace(r) == room {
+            return true
+        }
+    }
+    return false
+}
+
+// NoteSpeaker records that userID spoke in room as name, so challenges can resolve `@name` to a user.
+func (m *Manager) NoteSpeaker(ctx context.Context, room, userID, name string) error {
+    if strings.TrimSpace(room) == "" || strings.TrimSpace(userID) == "" || strings.TrimSpace(name) == "" {
+        return nil
+    }
+    return m.store.SaveSpeaker(ctx, room, userID, name)
+}
+
+// Challenge stores a direct challenge from userID to the player named target in room.
+// target은 이 방에서 그 이름으로 마지막에 말한 사용자(NoteSpeaker)로 확정되며, 신청은 ttl 동안 그 사용자만
+// 수락/거절할 수 있습니다(이후 이름을 바꾼 다른 사용자는 불가). 대기방 목록에는 노출되지 않습니다.
+func (m *Manager) Challenge(ctx context.Context, room, userID, userName, target string, ttl time.Duration) (*Challenge, error) {
+    room = strings.TrimSpace(room)
+    userID = strings.TrimSpace(userID)
+    target = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(target), "@"))
+    if room == "" || userID == "" || target == "" || ttl <= 0 {
+        return nil, ErrInvalidArgs
+    }
+    if strings.EqualFold(target, strings.TrimSpace(userName)) {
+        return nil, ErrSelfChallenge
+    }
+    if m.atGameLimit(ctx, userID) {
+        return nil, ErrPlayerBusy
+    }
+    // 동시에 하나의 신청만 허용
+    if key, err := m.store.OutgoingChallengeKey(ctx, userID); err != nil {
+        return nil, err
+    } else if key != "" {
+        return nil, ErrChallengePending
+    }
+    targetID, err := m.store.SpeakerID(ctx, room, target)
+    if err != nil {
+        return nil, err
+    }
+    if targetID == "" {
+        return nil, ErrUnknownTarget
+    }
+    if targetID == userID {
+        return nil, ErrSelfChallenge
+    }
+    now := time.Now()
+    c := &Challenge{
+        Room:           room,
+        ChallengerID:   userID,
+        ChallengerName: strings.TrimSpace(userName),
+        TargetID:       targetID,
+        TargetName:     target,
+        CreatedAt:      now,
+        ExpiresAt:      now.Add(ttl),
+    }
+    ok, err := m.store.CreateChallenge(ctx, c, ttl)
+    if err != nil {
+        return nil, err
+    }
+    if !ok {
+        return nil, ErrChallengePending
+    }
+    obslog.L().Info("challenge_create", zap.String("room", room), zap.String("challenger_id", userID), zap.String("target_id", targetID), zap.String("target_name", target), zap.Duration("ttl", ttl))
+    return c, nil
+}
+
+// AcceptChallenge starts the game for a challenge addressed to userID in room.
+// 대국은 현재 방에서만 진행되며, 채널 메타는 팬아웃/관전을 위해 ACTIVE 상태로 바로 저장됩니다.
+func (m *Manager) AcceptChallenge(ctx context.Context, room, userID, userName string) (*JoinResult, error) {
+    room = strings.TrimSpace(room)
+    userID = strings.TrimSpace(userID)
+    if room == "" || userID == "" || strings.TrimSpace(userName) == "" {
+        return nil, ErrInvalidArgs
+    }
+    if m.atGameLimit(ctx, userID) {
+        return nil, ErrPlayerBusy
+    }
+    // 확인이 실패하면 신청은 그대로 남아 신청자가 취소하거나 만료될 때까지 유지됨
+    c, err := m.store.TakeChallengeIf(ctx, m.store.keyChallenge(room, userID), func(c *Challenge) error {
+        if c.TargetID != userID {
+            return ErrNoChallenge
+        }
+        if c.ChallengerID == userID {
+            return ErrSelfChallenge
+        }
+        if m.atGameLimit(ctx, c.ChallengerID) {
+            return ErrPlayerBusy
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    if c == nil {
+        return nil, ErrNoChallenge
+    }
+
+    g, err := m.pvp.CreateGameFromChallenge(ctx, room, room, c.ChallengerID, c.ChallengerName, userID, userName, string(ColorRandom), "")
+    if err != nil {
+        return nil, createError(err)
+    }
+    meta, err := m.bindActiveChannel(ctx, g, c.ChallengerID, c.ChallengerName, room, room)
+    if err != nil {
+        return nil, err
+    }
+    obslog.L().Info("challenge_start_game", zap.String("code", meta.ID), zap.String("game_id", g.ID), zap.String("white_id", g.WhiteID), zap.String("black_id", g.BlackID))
+    return &JoinResult{Started: true, GameID: g.ID, Meta: meta}, nil
+}
+
+// bindActiveChannel stores an ACTIVE channel for a game started outside the lobby (challenge/matching)
+// so fanout, spectating and MetaByGame work as for lobby games. 대기방 목록에는 추가하지 않습니다.
+func (m *Manager) bindActiveChannel(ctx context.Context, g *pvpchess.Game, creatorID, creatorName, creatorRoom, otherRoom string) (*ChannelMeta, error) {
+    code, err := m.reserveCode(ctx)
+    if err != nil {
+        return nil, err
+    }
+    meta := &ChannelMeta{
+        ID:          code,
+        State:       StateActive,
+        CreatedAt:   time.Now(),
+        CreatorID:   creatorID,
+        CreatorName: creatorName,
+        CreatorRoom: creatorRoom,
+        WhiteID:     g.WhiteID,
+        WhiteName:   g.WhiteName,
+        BlackID:     g.BlackID,
+        BlackName:   g.BlackName,
+        GameID:      g.ID,
+    }
+    if err := m.store.SaveMeta(ctx, code, meta); err != nil {
+        return nil, err
+    }
+    for _, r := range []string{creatorRoom, otherRoom} {
+        if err := m.store.AddRoom(ctx, code, r, channelTTL(meta)); err != nil {
+            return nil, err
+        }
+    }
+    for _, uid := range []string{g.WhiteID, g.BlackID} {
+        if err := m.store.AddParticipant(ctx, code, uid, channelTTL(meta)); err != nil {
+            return nil, err
+        }
+    }
+    return meta, nil
+}
+
+// DeclineChallenge removes the challenge addressed to userID in room and returns it.
+func (m *Manager) DeclineChallenge(ctx context.Context, room, userID string) (*Challenge, error) {
+    userID = strings.TrimSpace(userID)
+    if strings.TrimSpace(room) == "" || userID == "" {
+        return nil, ErrInvalidArgs
+    }
+    c, err := m.store.TakeChallengeIf(ctx, m.store.keyChallenge(room, userID), func(c *Challenge) error {
+        if c.TargetID != userID {
+            return ErrNoChallenge
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    if c == nil {
+        return nil, ErrNoChallenge
+    }
+    obslog.L().Info("challenge_decline", zap.String("room", c.Room), zap.String("challenger_id", c.ChallengerID), zap.String("target_id", c.TargetID))
+    return c, nil
+}
+
+// CancelChallenge withdraws the user's own pending challenge and returns it.
+func (m *Manager) CancelChallenge(ctx context.Context, userID string) (*Challenge, error) {
+    if strings.TrimSpace(userID) == "" {
+        return nil, ErrInvalidArgs
+    }
+    key, err := m.store.OutgoingChallengeKey(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    if key == "" {
+        return nil, ErrNoChallenge
+    }
+    c, err := m.store.TakeChallenge(ctx, key)
+    if err != nil {
+        return nil, err
+    }
+    if c == nil {
+        return nil, ErrNoChallenge
+    }
+    obslog.L().Info("challenge_cancel", zap.String("room", c.Room), zap.String("challenger_id", c.ChallengerID), zap.String("target_name", c.TargetName))
+    return c, nil
+}
+
+// atGameLimit reports whether the user already plays as many games as pvpchess allows (SetMaxGamesPerUser).
+func (m *Manager) atGameLimit(ctx context.Context, userID string) bool {
+    return errors.Is(m.pvp.CanStartGame(ctx, userID), pvpchess.ErrTooManyUserGames)
+}
+
+// createError reports the per-user limit hit at game creation (동시 요청으로 사전 확인을 지나친 경우)
+// as ErrPlayerBusy, the same as the pre-checks.
+func createError(err error) error {
+    if errors.Is(err, pvpchess.ErrTooManyUserGames) {
+        return ErrPlayerBusy
+    }
+    return err
+}
@@ -2,8 +2,10 @@ package pvpchan
 
 import (
     "context"
+    "errors"
     "fmt"
     "testing"
+    "time"
 
     miniredis "github.com/alicebob/miniredis/v2"
     "github.com/redis/go-redis/v9"
@@ -107,25 +109,33 @@ func TestJoinUsesProvidedUserName(t *testing.T) {
     }
 }
 
-func TestMakeBlockedIfActiveGameInSameRoom(t *testing.T) {
+func TestMakeBlockedAtGameLimit(t *testing.T) {
     m, chessMgr, cleanup := newTestManagers(t)
     defer cleanup()
     ctx := context.Background()
+    chessMgr.SetMaxGamesPerUser(2)
 
     // Create an active game for u1 in roomA
     if _, err := chessMgr.CreateGameFromChallenge(ctx, "roomA", "roomB", "u1", "u1", "u2", "u2", "random", "none"); err != nil {
         t.Fatalf("CreateGameFromChallenge: %v", err)
     }
-    // Try to create a new channel in the same room for u1 → should be blocked
-    if _, err := m.Make(ctx, "roomA", "u1", "u1", ColorRandom); err == nil {
-        t.Fatalf("expected ErrPlayerBusyInRoom on Make when user has active game in same room")
+    // 상한 미만이면 같은 방에서도 새 대기방 허용
+    if _, err := m.Make(ctx, "roomA", "u1", "u1", ColorRandom); err != nil {
+        t.Fatalf("expected second game to be allowed under the cap: %v", err)
+    }
+    if _, err := chessMgr.CreateGameFromChallenge(ctx, "roomC", "roomC", "u1", "u1", "u3", "u3", "random", "none"); err != nil {
+        t.Fatalf("CreateGameFromChallenge: %v", err)
+    }
+    if _, err := m.Make(ctx, "roomD", "u1", "u1", ColorRandom); !errors.Is(err, ErrPlayerBusy) {
+        t.Fatalf("expected ErrPlayerBusy on Make at the game limit, got %v", err)
     }
 }
 
-func TestJoinBlockedIfUserActiveInSameRoom(t *testing.T) {
+func TestJoinBlockedAtGameLimit(t *testing.T) {
     m, chessMgr, cleanup := newTestManagers(t)
     defer cleanup()
     ctx := context.Background()
+    chessMgr.SetMaxGamesPerUser(1)
 
     // Pre-create an active game for u2 in roomB
     if _, err := chessMgr.CreateGameFromChallenge(ctx, "roomX", "roomB", "x1", "x1", "u2", "u2", "random", "none"); err != nil {
@@ -136,9 +146,9 @@ func TestJoinBlockedIfUserActiveInSameRoom(t *testing.T) {
     mr, err := m.Make(ctx, "roomA", "u1", "u1", ColorRandom)
     if err != nil { t.Fatalf("Make: %v", err) }
 
-    // Attempt to join from roomB as u2 → should be blocked due to active game in roomB
-    if _, err := m.Join(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom); err == nil {
-        t.Fatalf("expected ErrPlayerBusyInRoom on Join when user has active game in same room")
+    // Attempt to join from roomB as u2 → should be blocked: u2 already plays one game
+    if _, err := m.Join(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom); !errors.Is(err, ErrPlayerBusy) {
+        t.Fatalf("expected ErrPlayerBusy on Join when user is at the game limit, got %v", err)
     }
 }
 
@@ -156,3 +166,324 @@ func TestMakeRestrictedDuplicateCreator(t *testing.T) {
         t.Fatalf("expected ErrCreatorHasLobby on duplicate creator lobby")
     }
 }
+
+func TestSpectateAddsFanoutRoomWithCap(t *testing.T) {
+    m, _, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+
+    mr, err := m.Make(ctx, "roomA", "u1", "u1", ColorRandom)
+    if err != nil { t.Fatalf("Make: %v", err) }
+    // 대국 시작 전에는 관전 불가
+    if _, err := m.Spectate(ctx, "roomS1", mr.Code, 1); err != ErrNotInProgress {
+        t.Fatalf("expected ErrNotInProgress before start, got %v", err)
+    }
+    if _, err := m.Join(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom); err != nil { t.Fatalf("Join: %v", err) }
+
+    // 참가자 방은 관전 대상이 아님
+    if _, err := m.Spectate(ctx, "roomA", mr.Code, 1); err != ErrRoomIsPlayerRoom {
+        t.Fatalf("expected ErrRoomIsPlayerRoom, got %v", err)
+    }
+    if _, err := m.Spectate(ctx, "roomS1", mr.Code, 1); err != nil { t.Fatalf("Spectate: %v", err) }
+    // 같은 방 재구독은 허용, 다른 방은 한도 초과
+    if _, err := m.Spectate(ctx, "roomS1", mr.Code, 1); err != nil { t.Fatalf("re-Spectate: %v", err) }
+    if _, err := m.Spectate(ctx, "roomS2", mr.Code, 1); err != ErrSpectatorLimit {
+        t.Fatalf("expected ErrSpectatorLimit, got %v", err)
+    }
+    rooms, _ := m.SpectatorRooms(ctx, mr.Code)
+    if len(rooms) != 1 || rooms[0] != "roomS1" { t.Fatalf("unexpected spectator rooms: %v", rooms) }
+    if g, _ := m.SpectatedGame(ctx, "roomS1"); g == nil { t.Fatalf("expected spectated game for roomS1") }
+
+    code, err := m.Unspectate(ctx, "roomS1")
+    if err != nil || code != mr.Code { t.Fatalf("Unspectate: code=%q err=%v", code, err) }
+    if _, err := m.Unspectate(ctx, "roomS1"); err != ErrNotSpectating {
+        t.Fatalf("expected ErrNotSpectating, got %v", err)
+    }
+    if _, err := m.Spectate(ctx, "roomS2", mr.Code, 1); err != nil { t.Fatalf("Spectate after unsubscribe: %v", err) }
+}
+
+func TestCorrespondenceChannelKeysOutliveRegularTTL(t *testing.T) {
+    m, _, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+
+    // u2가 먼저 일반 대기방에 참가해 사용자 색인에 24시간 TTL이 걸려 있어도 늘어나야 함
+    if _, err := m.Make(ctx, "roomX", "u2", "u2", ColorRandom); err != nil { t.Fatalf("Make: %v", err) }
+    mr, err := m.MakeLobby(ctx, "roomA", "u1", "u1", LobbyOptions{Color: ColorRandom, Correspondence: true})
+    if err != nil { t.Fatalf("MakeLobby: %v", err) }
+    if _, err := m.Join(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom); err != nil { t.Fatalf("Join: %v", err) }
+    if _, err := m.Spectate(ctx, "roomS", mr.Code, 1); err != nil { t.Fatalf("Spectate: %v", err) }
+
+    keys := []string{
+        m.store.keyMeta(mr.Code),
+        m.store.keyRooms(mr.Code),
+        m.store.keyParticipants(mr.Code),
+        m.store.keySpectators(mr.Code),
+        m.store.keySpectateRoom("roomS"),
+        m.store.keyUserIdx("u1"),
+        m.store.keyUserIdx("u2"),
+    }
+    for _, k := range keys {
+        ttl, err := m.rdb.TTL(ctx, k).Result()
+        if err != nil { t.Fatalf("TTL %s: %v", k, err) }
+        if ttl <= ttlChannel { t.Fatalf("%s ttl=%v, want correspondence ttl", k, ttl) }
+    }
+
+    // 이후 일반 채널 참가가 통신 대국 사용자 색인의 TTL을 줄이지 않음
+    lobby, err := m.Make(ctx, "roomY", "u3", "u3", ColorRandom)
+    if err != nil { t.Fatalf("Make: %v", err) }
+    if err := m.store.AddParticipant(ctx, lobby.Code, "u2", channelTTL(lobby.Meta)); err != nil { t.Fatalf("AddParticipant: %v", err) }
+    if ttl, _ := m.rdb.TTL(ctx, m.store.keyUserIdx("u2")).Result(); ttl <= ttlChannel {
+        t.Fatalf("user index ttl shortened to %v", ttl)
+    }
+}
+
+// noteSpeakers records name → user id pairs as if each had spoken in room.
+func noteSpeakers(t *testing.T, m *Manager, room string, pairs ...string) {
+    t.Helper()
+    for i := 0; i+1 < len(pairs); i += 2 {
+        if err := m.NoteSpeaker(context.Background(), room, pairs[i], pairs[i+1]); err != nil { t.Fatalf("NoteSpeaker: %v", err) }
+    }
+}
+
+func TestChallengeAcceptStartsGameOutsideLobby(t *testing.T) {
+    m, chessMgr, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+    noteSpeakers(t, m, "roomA", "u1", "Alice", "u2", "Bob", "u3", "Carol")
+
+    c, err := m.Challenge(ctx, "roomA", "u1", "Alice", "@Bob", time.Minute)
+    if err != nil { t.Fatalf("Challenge: %v", err) }
+    if c.TargetName != "Bob" || c.TargetID != "u2" { t.Fatalf("expected target Bob/u2, got %q/%q", c.TargetName, c.TargetID) }
+    if _, err := m.Challenge(ctx, "roomA", "u1", "Alice", "Carol", time.Minute); err != ErrChallengePending {
+        t.Fatalf("expected ErrChallengePending for second challenge, got %v", err)
+    }
+    // 다른 사용자/다른 방에서는 수락 불가
+    if _, err := m.AcceptChallenge(ctx, "roomA", "u3", "Carol"); err != ErrNoChallenge {
+        t.Fatalf("expected ErrNoChallenge for non-target, got %v", err)
+    }
+    if _, err := m.AcceptChallenge(ctx, "roomB", "u2", "Bob"); err != ErrNoChallenge {
+        t.Fatalf("expected ErrNoChallenge in other room, got %v", err)
+    }
+    // 신청 후 같은 이름으로 바꾼 다른 사용자도 수락 불가
+    noteSpeakers(t, m, "roomA", "u4", "Bob")
+    if _, err := m.AcceptChallenge(ctx, "roomA", "u4", "Bob"); err != ErrNoChallenge {
+        t.Fatalf("expected ErrNoChallenge for a user renamed to the target, got %v", err)
+    }
+
+    jr, err := m.AcceptChallenge(ctx, "roomA", "u2", "bob")
+    if err != nil { t.Fatalf("AcceptChallenge: %v", err) }
+    if !jr.Started || jr.Meta.State != StateActive { t.Fatalf("expected started active channel: %+v", jr.Meta) }
+    g, _ := chessMgr.GetActiveGameByUserInRoom(ctx, "u1", "roomA")
+    if g == nil || g.ID != jr.GameID || g.OriginRoom != "roomA" || g.ResolveRoom != "roomA" {
+        t.Fatalf("unexpected game for challenge: %+v", g)
+    }
+    if lobby, _ := m.ListLobby(ctx); len(lobby) != 0 { t.Fatalf("challenge must not be listed in lobby: %v", lobby) }
+    if rooms, _ := m.RoomsByUserAndGame(ctx, "u2", g.ID); len(rooms) != 1 || rooms[0] != "roomA" {
+        t.Fatalf("unexpected fanout rooms: %v", rooms)
+    }
+    if _, err := m.AcceptChallenge(ctx, "roomA", "u2", "Bob"); err == nil { t.Fatalf("expected consumed challenge") }
+}
+
+func TestChallengeSurvivesFailedAccept(t *testing.T) {
+    m, chessMgr, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+    chessMgr.SetMaxGamesPerUser(1)
+    noteSpeakers(t, m, "roomA", "u1", "Alice", "u2", "Bob")
+
+    if _, err := m.Challenge(ctx, "roomA", "u1", "Alice", "Bob", time.Minute); err != nil { t.Fatalf("Challenge: %v", err) }
+    // 신청자가 그 사이 다른 대국을 시작해 확인이 실패해도 신청은 소비되지 않아야 함
+    other, err := chessMgr.CreateGameFromChallenge(ctx, "roomX", "roomX", "u1", "Alice", "u3", "Carol", "random", "none")
+    if err != nil { t.Fatalf("CreateGameFromChallenge: %v", err) }
+    if _, err := m.AcceptChallenge(ctx, "roomA", "u2", "Bob"); err != ErrPlayerBusy {
+        t.Fatalf("expected ErrPlayerBusy, got %v", err)
+    }
+    if key, _ := m.store.OutgoingChallengeKey(ctx, "u1"); key == "" { t.Fatalf("outgoing challenge index must survive a failed accept") }
+    if _, _, err := chessMgr.ResignInGame(ctx, "u3", other.ID); err != nil { t.Fatalf("ResignInGame: %v", err) }
+    jr, err := m.AcceptChallenge(ctx, "roomA", "u2", "Bob")
+    if err != nil || !jr.Started { t.Fatalf("AcceptChallenge after failed attempt: %+v %v", jr, err) }
+    if key, _ := m.store.OutgoingChallengeKey(ctx, "u1"); key != "" { t.Fatalf("accepted challenge must clear the outgoing index, got %q", key) }
+}
+
+func TestChallengeDeclineAndCancel(t *testing.T) {
+    m, _, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+    noteSpeakers(t, m, "roomA", "u1", "Alice", "u2", "Bob")
+
+    if _, err := m.Challenge(ctx, "roomA", "u1", "Alice", "alice", time.Minute); err != ErrSelfChallenge {
+        t.Fatalf("expected ErrSelfChallenge, got %v", err)
+    }
+    if _, err := m.Challenge(ctx, "roomA", "u1", "Alice", "Dave", time.Minute); err != ErrUnknownTarget {
+        t.Fatalf("expected ErrUnknownTarget, got %v", err)
+    }
+    if _, err := m.Challenge(ctx, "roomA", "u1", "Alice", "Bob", time.Minute); err != nil { t.Fatalf("Challenge: %v", err) }
+    c, err := m.DeclineChallenge(ctx, "roomA", "u2")
+    if err != nil || c.ChallengerID != "u1" { t.Fatalf("DeclineChallenge: c=%+v err=%v", c, err) }
+    // 거절 후 새 신청 가능, 신청자는 취소 가능
+    if _, err := m.Challenge(ctx, "roomA", "u1", "Alice", "Bob", time.Minute); err != nil { t.Fatalf("re-Challenge: %v", err) }
+    if _, err := m.CancelChallenge(ctx, "u1"); err != nil { t.Fatalf("CancelChallenge: %v", err) }
+    if _, err := m.CancelChallenge(ctx, "u1"); err != ErrNoChallenge { t.Fatalf("expected ErrNoChallenge, got %v", err) }
+}
+
+func TestResolveCreatorColor(t *testing.T) {
+    cases := []struct{ creator, joiner, want ColorChoice }{
+        {ColorWhite, ColorRandom, ColorWhite},
+        {ColorBlack, ColorWhite, ColorBlack},
+        {ColorRandom, ColorWhite, ColorBlack},
+        {ColorRandom, ColorBlack, ColorWhite},
+        {ColorWhite, ColorWhite, ColorRandom}, // 같은 색 충돌 → 랜덤
+        {ColorBlack, ColorBlack, ColorRandom},
+        {"", "", ColorRandom},
+    }
+    for _, c := range cases {
+        if got := resolveCreatorColor(c.creator, c.joiner); got != c.want {
+            t.Fatalf("resolveCreatorColor(%q, %q) = %q, want %q", c.creator, c.joiner, got, c.want)
+        }
+    }
+}
+
+func TestMakeJoinHonoursColorPreference(t *testing.T) {
+    m, _, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+
+    mr, err := m.Make(ctx, "roomA", "u1", "u1", ColorBlack)
+    if err != nil { t.Fatalf("Make: %v", err) }
+    lobby, _ := m.ListLobby(ctx)
+    if len(lobby) != 1 || lobby[0].CreatorColor != ColorBlack { t.Fatalf("expected stored creator color: %+v", lobby) }
+    jr, err := m.Join(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom)
+    if err != nil { t.Fatalf("Join: %v", err) }
+    if jr.Meta.BlackID != "u1" || jr.Meta.WhiteID != "u2" { t.Fatalf("creator should be black: %+v", jr.Meta) }
+
+    // 생성자가 랜덤이면 참가자 선호를 따름
+    mr, err = m.Make(ctx, "roomC", "u3", "u3", ColorRandom)
+    if err != nil { t.Fatalf("Make#2: %v", err) }
+    jr, err = m.Join(ctx, "roomD", mr.Code, "u4", "u4", ColorWhite)
+    if err != nil { t.Fatalf("Join#2: %v", err) }
+    if jr.Meta.WhiteID != "u4" { t.Fatalf("joiner should be white: %+v", jr.Meta) }
+}
+
+func TestCancelLobbyAllowsNewLobby(t *testing.T) {
+    m, _, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+
+    mr, err := m.Make(ctx, "roomA", "u1", "u1", ColorRandom)
+    if err != nil { t.Fatalf("Make: %v", err) }
+    if _, err := m.CancelLobby(ctx, "u2"); err != ErrNoLobby { t.Fatalf("expected ErrNoLobby for non-creator, got %v", err) }
+
+    meta, err := m.CancelLobby(ctx, "u1")
+    if err != nil || meta == nil || meta.ID != mr.Code { t.Fatalf("CancelLobby: meta=%v err=%v", meta, err) }
+    if list, _ := m.ListLobby(ctx); len(list) != 0 { t.Fatalf("expected empty lobby list, got %d", len(list)) }
+    if _, err := m.Join(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom); err != ErrChannelGone { t.Fatalf("expected ErrChannelGone on join, got %v", err) }
+    if codes, _ := m.store.CodesByUser(ctx, "u1"); len(codes) != 0 { t.Fatalf("expected user index cleared, got %v", codes) }
+
+    if _, err := m.Make(ctx, "roomA", "u1", "u1", ColorRandom); err != nil { t.Fatalf("Make after cancel: %v", err) }
+    if _, err := m.CancelLobby(ctx, "u1"); err != nil { t.Fatalf("second cancel: %v", err) }
+    if _, err := m.CancelLobby(ctx, "u1"); err != ErrNoLobby { t.Fatalf("expected ErrNoLobby, got %v", err) }
+}
+
+func TestSweepExpiredLobbies(t *testing.T) {
+    m, _, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+
+    old, err := m.Make(ctx, "roomA", "u1", "u1", ColorRandom)
+    if err != nil { t.Fatalf("Make old: %v", err) }
+    fresh, err := m.Make(ctx, "roomB", "u2", "u2", ColorRandom)
+    if err != nil { t.Fatalf("Make fresh: %v", err) }
+    old.Meta.CreatedAt = time.Now().Add(-2 * time.Hour)
+    if err := m.store.SaveMeta(ctx, old.Code, old.Meta); err != nil { t.Fatalf("SaveMeta: %v", err) }
+
+    expired, err := m.SweepExpiredLobbies(ctx, time.Hour)
+    if err != nil { t.Fatalf("sweep: %v", err) }
+    if len(expired) != 1 || expired[0].ID != old.Code || expired[0].CreatorRoom != "roomA" { t.Fatalf("expected only old lobby expired, got %+v", expired) }
+    list, _ := m.ListLobby(ctx)
+    if len(list) != 1 || list[0].ID != fresh.Code { t.Fatalf("expected fresh lobby to remain, got %+v", list) }
+    if _, err := m.Make(ctx, "roomA", "u1", "u1", ColorRandom); err != nil { t.Fatalf("Make after expiry: %v", err) }
+}
+
+func TestUnlistedLobbyHiddenFromList(t *testing.T) {
+    m, _, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+
+    pub, err := m.Make(ctx, "roomA", "u1", "u1", ColorRandom)
+    if err != nil { t.Fatalf("Make: %v", err) }
+    priv, err := m.MakeLobby(ctx, "roomB", "u2", "u2", LobbyOptions{Unlisted: true})
+    if err != nil { t.Fatalf("MakeLobby: %v", err) }
+
+    list, err := m.ListLobby(ctx)
+    if err != nil { t.Fatalf("ListLobby: %v", err) }
+    if len(list) != 1 || list[0].ID != pub.Code { t.Fatalf("expected only public lobby listed, got %+v", list) }
+
+    jr, err := m.Join(ctx, "roomC", priv.Code, "u3", "u3", ColorRandom)
+    if err != nil || !jr.Started { t.Fatalf("join unlisted by code: jr=%+v err=%v", jr, err) }
+}
+
+func TestJoinWithPIN(t *testing.T) {
+    m, _, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+
+    if _, err := m.MakeLobby(ctx, "roomA", "u1", "u1", LobbyOptions{PIN: "12"}); err != ErrInvalidPIN { t.Fatalf("expected ErrInvalidPIN, got %v", err) }
+    mr, err := m.MakeLobby(ctx, "roomA", "u1", "u1", LobbyOptions{PIN: "4821"})
+    if err != nil { t.Fatalf("MakeLobby: %v", err) }
+    if mr.Meta.PINHash == "" || mr.Meta.PINHash == "4821" { t.Fatalf("expected hashed pin, got %q", mr.Meta.PINHash) }
+
+    if _, err := m.Join(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom); err != ErrPINRequired { t.Fatalf("expected ErrPINRequired, got %v", err) }
+    if _, err := m.JoinWithPIN(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom, "0000"); err != ErrWrongPIN { t.Fatalf("expected ErrWrongPIN, got %v", err) }
+    jr, err := m.JoinWithPIN(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom, "4821")
+    if err != nil || !jr.Started { t.Fatalf("join with pin: jr=%+v err=%v", jr, err) }
+}
+
+func TestJoinPINLocksAfterRepeatedFailures(t *testing.T) {
+    m, _, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+
+    mr, err := m.MakeLobby(ctx, "roomA", "u1", "u1", LobbyOptions{PIN: "4821"})
+    if err != nil { t.Fatalf("MakeLobby: %v", err) }
+    for i := 0; i < maxPINFailures; i++ {
+        if _, err := m.JoinWithPIN(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom, "0000"); err != ErrWrongPIN { t.Fatalf("attempt %d: expected ErrWrongPIN, got %v", i, err) }
+    }
+    if _, err := m.JoinWithPIN(ctx, "roomB", mr.Code, "u2", "u2", ColorRandom, "4821"); err != ErrPINLocked { t.Fatalf("expected ErrPINLocked, got %v", err) }
+}
+
+func TestListLobbyScoped(t *testing.T) {
+    m, _, cleanup := newTestManagers(t)
+    defer cleanup()
+    ctx := context.Background()
+
+    a, err := m.Make(ctx, "roomA", "u1", "u1", ColorRandom)
+    if err != nil { t.Fatalf("Make A: %v", err) }
+    time.Sleep(2 * time.Millisecond)
+    a2, err := m.Make(ctx, "roomA", "u2", "u2", ColorRandom)
+    if err != nil { t.Fatalf("Make A2: %v", err) }
+    if _, err := m.Make(ctx, "roomB", "u3", "u3", ColorRandom); err != nil { t.Fatalf("Make B: %v", err) }
+    if _, err := m.Make(ctx, "roomC", "u4", "u4", ColorRandom); err != nil { t.Fatalf("Make C: %v", err) }
+
+    room, err := m.ListLobbyScoped(ctx, ScopeRoom, "roomA", nil)
+    if err != nil { t.Fatalf("room scope: %v", err) }
+    if len(room) != 2 || room[0].ID != a2.Code || room[1].ID != a.Code { t.Fatalf("expected roomA lobbies newest first, got %+v", room) }
+
+    allowed, _ := m.ListLobbyScoped(ctx, ScopeAllowed, "roomA", []string{"roomA", "roomB"})
+    if len(allowed) != 3 { t.Fatalf("expected 3 lobbies in allowed rooms, got %d", len(allowed)) }
+    if all, _ := m.ListLobbyScoped(ctx, ScopeAllowed, "roomA", nil); len(all) != 4 { t.Fatalf("expected empty allow list to mean all rooms, got %d", len(all)) }
+    if all, _ := m.ListLobbyScoped(ctx, ScopeGlobal, "roomZ", nil); len(all) != 4 { t.Fatalf("expected 4 global lobbies, got %d", len(all)) }
+}
+
+func TestClampLobbyScope(t *testing.T) {
+    cases := []struct{ req, max, want LobbyScope }{
+        {ScopeGlobal, ScopeRoom, ScopeRoom},
+        {ScopeRoom, ScopeGlobal, ScopeRoom},
+        {ScopeAllowed, ScopeGlobal, ScopeAllowed},
+        {"", ScopeAllowed, ScopeAllowed},
+        {ScopeGlobal, "bogus", ScopeRoom},
+    }
+    for _, c := range cases {
+        if got := ClampLobbyScope(c.req, c.max); got != c.want { t.Fatalf("ClampLobbyScope(%q,%q)=%q want %q", c.req, c.max, got, c.want) }
+    }
+}
@@ -0,0 +1,88 @@
+package pvpchan
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"go.uber.org/zap"
+)
+
+// EnqueueMatch pairs the user with the oldest compatible waiting player, or queues them.
+// ratingWindow가 0보다 크면 레이팅 차이가 그 이내인 상대만 매칭합니다. 대국은 기존
+// CreateGameFromChallenge 경로로 만들어지며(먼저 기다린 쪽이 origin), 채널은 ACTIVE로 바로 저장됩니다.
+func (m *Manager) EnqueueMatch(ctx context.Context, room, userID, userName string, rating, ratingWindow int) (*MatchResult, error) {
+	room = strings.TrimSpace(room)
+	userID = strings.TrimSpace(userID)
+	if room == "" || userID == "" {
+		return nil, ErrInvalidArgs
+	}
+	if m.atGameLimit(ctx, userID) {
+		return nil, ErrPlayerBusy
+	}
+	if e, err := m.store.LoadMatchEntry(ctx, userID); err != nil {
+		return nil, err
+	} else if e != nil {
+		return nil, ErrAlreadyQueued
+	}
+
+	entry := &MatchEntry{UserID: userID, Name: strings.TrimSpace(userName), Room: room, Rating: rating, JoinedAt: time.Now()}
+	opp, err := m.store.ClaimOrEnqueue(ctx, entry, func(opp *MatchEntry) bool {
+		if ratingWindow > 0 && absInt(opp.Rating-rating) > ratingWindow {
+			return false
+		}
+		return !m.atGameLimit(ctx, opp.UserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opp != nil {
+		g, err := m.pvp.CreateGameFromChallenge(ctx, opp.Room, room, opp.UserID, opp.Name, userID, userName, string(ColorRandom), "")
+		if err != nil {
+			// 대국 생성 실패(동시 대국 상한 등) 시 상대를 원래 순서로 대기열에 복귀
+			_ = m.store.SaveMatchEntry(ctx, opp)
+			return nil, createError(err)
+		}
+		meta, err := m.bindActiveChannel(ctx, g, opp.UserID, opp.Name, opp.Room, room)
+		if err != nil {
+			// 채널 없이 남은 대국은 어느 방에서도 진행·안내되지 않으므로 지우고 상대를 대기열에 복귀
+			if derr := m.pvp.DiscardGame(ctx, g); derr != nil {
+				obslog.L().Error("match_discard_error", zap.String("game_id", g.ID), zap.Error(derr))
+			} else {
+				_ = m.store.SaveMatchEntry(ctx, opp)
+			}
+			return nil, err
+		}
+		obslog.L().Info("match_start_game", zap.String("code", meta.ID), zap.String("game_id", g.ID), zap.String("white_id", g.WhiteID), zap.String("black_id", g.BlackID), zap.Duration("waited", time.Since(opp.JoinedAt)))
+		return &MatchResult{Matched: true, GameID: g.ID, Meta: meta, Opponent: opp}, nil
+	}
+
+	waiting, _ := m.store.QueueLength(ctx)
+	obslog.L().Info("match_enqueue", zap.String("room", room), zap.String("user_id", userID), zap.Int("rating", rating), zap.Int64("waiting", waiting))
+	return &MatchResult{Waiting: waiting}, nil
+}
+
+// LeaveMatch removes the user from the matching queue.
+func (m *Manager) LeaveMatch(ctx context.Context, userID string) error {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return ErrInvalidArgs
+	}
+	claimed, err := m.store.RemoveFromQueue(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrNotQueued
+	}
+	obslog.L().Info("match_leave", zap.String("user_id", userID))
+	return nil
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,127 @@
+package pvpchan
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestEnqueueMatchPairsWithinRatingWindow(t *testing.T) {
+	m, chessMgr, cleanup := newTestManagers(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	r, err := m.EnqueueMatch(ctx, "roomA", "u1", "A", 1500, 100)
+	if err != nil || r.Matched || r.Waiting != 1 {
+		t.Fatalf("first enqueue: r=%+v err=%v", r, err)
+	}
+	if _, err := m.EnqueueMatch(ctx, "roomA", "u1", "A", 1500, 100); err != ErrAlreadyQueued {
+		t.Fatalf("expected ErrAlreadyQueued, got %v", err)
+	}
+	// 레이팅 차이가 커서 매칭되지 않고 대기
+	r, err = m.EnqueueMatch(ctx, "roomB", "u2", "B", 1200, 100)
+	if err != nil || r.Matched || r.Waiting != 2 {
+		t.Fatalf("out-of-window enqueue: r=%+v err=%v", r, err)
+	}
+	r, err = m.EnqueueMatch(ctx, "roomC", "u3", "C", 1450, 100)
+	if err != nil || !r.Matched || r.Opponent.UserID != "u1" {
+		t.Fatalf("expected match with u1: r=%+v err=%v", r, err)
+	}
+	g, _ := chessMgr.GetActiveGameByUserInRoom(ctx, "u3", "roomC")
+	if g == nil || g.ID != r.GameID || g.OriginRoom != "roomA" {
+		t.Fatalf("unexpected matched game: %+v", g)
+	}
+	if rooms, _ := m.Rooms(ctx, r.Meta.ID); len(rooms) != 2 {
+		t.Fatalf("expected both rooms bound to channel, got %v", rooms)
+	}
+	if lobby, _ := m.ListLobby(ctx); len(lobby) != 0 {
+		t.Fatalf("matched game must not be listed in lobby: %v", lobby)
+	}
+
+	if err := m.LeaveMatch(ctx, "u2"); err != nil {
+		t.Fatalf("LeaveMatch: %v", err)
+	}
+	if err := m.LeaveMatch(ctx, "u2"); err != ErrNotQueued {
+		t.Fatalf("expected ErrNotQueued, got %v", err)
+	}
+}
+
+func TestEnqueueMatchRequeuesOpponentAtCapacity(t *testing.T) {
+	m, chessMgr, cleanup := newTestManagers(t)
+	defer cleanup()
+	ctx := context.Background()
+	chessMgr.SetMaxActiveGames(1)
+
+	if _, err := chessMgr.CreateGameFromChallenge(ctx, "r1", "r2", "x", "X", "y", "Y", "white", ""); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := m.EnqueueMatch(ctx, "roomA", "u1", "A", 1200, 0); err != nil {
+		t.Fatalf("enqueue u1: %v", err)
+	}
+	if _, err := m.EnqueueMatch(ctx, "roomB", "u2", "B", 1200, 0); err == nil {
+		t.Fatalf("expected capacity error")
+	}
+	if e, _ := m.store.LoadMatchEntry(ctx, "u1"); e == nil {
+		t.Fatalf("opponent should be back in the queue")
+	}
+}
+
+func TestEnqueueMatchDiscardsGameWhenChannelBindFails(t *testing.T) {
+	m, chessMgr, cleanup := newTestManagers(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if _, err := m.EnqueueMatch(ctx, "roomA", "u1", "A", 1200, 0); err != nil {
+		t.Fatalf("enqueue u1: %v", err)
+	}
+	// u2의 채널 색인을 문자열 키로 막아 채널 저장(AddParticipant)이 실패하게 함
+	if err := m.rdb.Set(ctx, m.store.keyUserIdx("u2"), "x", 0).Err(); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	if _, err := m.EnqueueMatch(ctx, "roomB", "u2", "B", 1200, 0); err == nil {
+		t.Fatalf("expected channel bind error")
+	}
+	for _, uid := range []string{"u1", "u2"} {
+		if games, _ := chessMgr.ActiveGamesByUser(ctx, uid); len(games) != 0 {
+			t.Fatalf("%s should have no game left, got %d", uid, len(games))
+		}
+	}
+	if n, _ := chessMgr.CountActiveGames(ctx); n != 0 {
+		t.Fatalf("active slot should be released, got %d", n)
+	}
+	if e, _ := m.store.LoadMatchEntry(ctx, "u1"); e == nil {
+		t.Fatalf("opponent should be back in the queue")
+	}
+}
+
+func TestEnqueueMatchConcurrentPairsOnce(t *testing.T) {
+	m, _, cleanup := newTestManagers(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// 빈 대기열에 동시에 들어온 두 사용자는 둘 다 대기하지 않고 서로 매칭되어야 함
+	for round := 0; round < 10; round++ {
+		a, b := fmt.Sprintf("a%d", round), fmt.Sprintf("b%d", round)
+		results := make([]*MatchResult, 2)
+		var wg sync.WaitGroup
+		for i, uid := range []string{a, b} {
+			wg.Add(1)
+			go func(i int, uid string) {
+				defer wg.Done()
+				r, err := m.EnqueueMatch(ctx, "room"+uid, uid, uid, 1200, 0)
+				if err != nil {
+					t.Errorf("enqueue %s: %v", uid, err)
+				}
+				results[i] = r
+			}(i, uid)
+		}
+		wg.Wait()
+		if results[0] == nil || results[1] == nil || results[0].Matched == results[1].Matched {
+			t.Fatalf("round %d: exactly one side should match: %+v %+v", round, results[0], results[1])
+		}
+		if n, _ := m.store.QueueLength(ctx); n != 0 {
+			t.Fatalf("round %d: queue length = %d", round, n)
+		}
+	}
+}
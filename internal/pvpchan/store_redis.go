@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -13,6 +14,17 @@ import (
 
 const (
 	ttlChannel = 24 * time.Hour
+	// ttlCorrespondenceChannel: 통신 대국 채널(방/참가자/관전) 유지 시간, 수 간격이 하루를 넘을 수 있음
+	ttlCorrespondenceChannel = 30 * 24 * time.Hour
+	// ttlMatchEntry: 매칭 대기열 항목 유지 시간(만료된 항목은 다음 매칭 시 정리)
+	ttlMatchEntry = 10 * time.Minute
+	// ttlPINLock: 비번 실패 횟수 집계 기간(maxPINFailures 초과 시 이 기간 동안 잠김)
+	ttlPINLock     = 10 * time.Minute
+	maxPINFailures = 5
+	// maxMatchAttempts: ClaimOrEnqueue가 대기열 경합으로 다시 고르는 최대 횟수
+	maxMatchAttempts = 5
+	// ttlSpeakers: 방별 표시 이름 → 사용자 ID 기록 유지 시간(지정 도전 상대 확인용)
+	ttlSpeakers = 7 * 24 * time.Hour
 )
 
 type Store struct{ rdb *redis.Client }
@@ -24,20 +36,39 @@ func (s *Store) keyRooms(code string) string        { return s.keyMeta(code) + "
 func (s *Store) keyParticipants(code string) string { return s.keyMeta(code) + ":participants" }
 func (s *Store) keyUserIdx(user string) string      { return "ch:index:user:" + strings.TrimSpace(user) }
 func (s *Store) keyLobby() string                   { return "ch:lobby" }
+func (s *Store) keySpectators(code string) string   { return s.keyMeta(code) + ":spectators" }
+func (s *Store) keySpectateRoom(room string) string { return "ch:spectate:room:" + strings.TrimSpace(room) }
+func (s *Store) keyChallenge(room, targetID string) string {
+	return "ch:challenge:" + strings.TrimSpace(room) + ":" + strings.TrimSpace(targetID)
+}
+func (s *Store) keySpeakers(room string) string { return "ch:speakers:" + strings.TrimSpace(room) }
+func (s *Store) keyChallengeBy(user string) string { return "ch:challenge:by:" + strings.TrimSpace(user) }
+func (s *Store) keyMatchQueue() string             { return "ch:match:queue" }
+func (s *Store) keyMatchEntry(user string) string  { return "ch:match:entry:" + strings.TrimSpace(user) }
+func (s *Store) keyPINFails(code string) string    { return s.keyMeta(code) + ":pinfail" }
 
 func (s *Store) SaveMeta(ctx context.Context, code string, meta *ChannelMeta) error {
 	raw, err := json.Marshal(meta)
 	if err != nil {
 		return err
 	}
-	if err := s.rdb.Set(ctx, s.keyMeta(code), raw, ttlChannel).Err(); err != nil {
+	ttl := channelTTL(meta)
+	if err := s.rdb.Set(ctx, s.keyMeta(code), raw, ttl).Err(); err != nil {
 		return err
 	}
-	_ = s.rdb.Expire(ctx, s.keyRooms(code), ttlChannel).Err()
-	_ = s.rdb.Expire(ctx, s.keyParticipants(code), ttlChannel).Err()
+	_ = s.rdb.Expire(ctx, s.keyRooms(code), ttl).Err()
+	_ = s.rdb.Expire(ctx, s.keyParticipants(code), ttl).Err()
 	return nil
 }
 
+// channelTTL is the lifetime of every key of the channel (메타·방·참가자·사용자 색인·관전).
+func channelTTL(meta *ChannelMeta) time.Duration {
+	if meta != nil && meta.Correspondence {
+		return ttlCorrespondenceChannel
+	}
+	return ttlChannel
+}
+
 func (s *Store) LoadMeta(ctx context.Context, code string) (*ChannelMeta, error) {
 	raw, err := s.rdb.Get(ctx, s.keyMeta(code)).Bytes()
 	if err == redis.Nil {
@@ -53,14 +84,15 @@ func (s *Store) LoadMeta(ctx context.Context, code string) (*ChannelMeta, error)
 	return &m, nil
 }
 
-func (s *Store) AddRoom(ctx context.Context, code, room string) error {
+// AddRoom records a room of the channel; ttl is channelTTL of its meta.
+func (s *Store) AddRoom(ctx context.Context, code, room string, ttl time.Duration) error {
 	if strings.TrimSpace(room) == "" {
 		return nil
 	}
 	if err := s.rdb.SAdd(ctx, s.keyRooms(code), room).Err(); err != nil {
 		return err
 	}
-	return s.rdb.Expire(ctx, s.keyRooms(code), ttlChannel).Err()
+	return s.rdb.Expire(ctx, s.keyRooms(code), ttl).Err()
 }
 
 func (s *Store) Rooms(ctx context.Context, code string) ([]string, error) {
@@ -71,19 +103,33 @@ func (s *Store) ParticipantCount(ctx context.Context, code string) (int64, error
 	return s.rdb.SCard(ctx, s.keyParticipants(code)).Result()
 }
 
-func (s *Store) AddParticipant(ctx context.Context, code, userID string) error {
+// AddParticipant records a player of the channel and indexes the channel under the user; ttl is channelTTL of its meta.
+func (s *Store) AddParticipant(ctx context.Context, code, userID string, ttl time.Duration) error {
 	if strings.TrimSpace(userID) == "" {
 		return nil
 	}
 	if err := s.rdb.SAdd(ctx, s.keyParticipants(code), userID).Err(); err != nil {
 		return err
 	}
-	_ = s.rdb.Expire(ctx, s.keyParticipants(code), ttlChannel).Err()
+	_ = s.rdb.Expire(ctx, s.keyParticipants(code), ttl).Err()
 	// index by user → codes
 	if err := s.rdb.SAdd(ctx, s.keyUserIdx(userID), code).Err(); err != nil {
 		return err
 	}
-	return s.rdb.Expire(ctx, s.keyUserIdx(userID), ttlChannel).Err()
+	return s.touchUserIdx(ctx, userID, ttl)
+}
+
+// touchUserIdx extends the user's channel index to live at least ttl. 색인은 사용자의 모든 채널이
+// 함께 쓰므로 일반 채널 참가가 통신 대국 채널의 색인 유지 시간을 줄이지 않게 합니다.
+func (s *Store) touchUserIdx(ctx context.Context, userID string, ttl time.Duration) error {
+	cur, err := s.rdb.TTL(ctx, s.keyUserIdx(userID)).Result()
+	if err != nil {
+		return err
+	}
+	if cur >= ttl {
+		return nil
+	}
+	return s.rdb.Expire(ctx, s.keyUserIdx(userID), ttl).Err()
 }
 
 func (s *Store) CodesByUser(ctx context.Context, userID string) ([]string, error) {
@@ -132,10 +178,254 @@ func (s *Store) ListLobby(ctx context.Context) ([]*ChannelMeta, error) {
 		if m == nil {
 			continue
 		}
-		if m.State != StateLobby {
+		if m.State != StateLobby || m.Unlisted {
 			continue
 		}
 		out = append(out, m)
 	}
 	return out, nil
 }
+
+// RecordPINFailure counts a wrong PIN attempt for code and returns the running total.
+func (s *Store) RecordPINFailure(ctx context.Context, code string) (int64, error) {
+	n, err := s.rdb.Incr(ctx, s.keyPINFails(code)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if n == 1 {
+		_ = s.rdb.Expire(ctx, s.keyPINFails(code), ttlPINLock).Err()
+	}
+	return n, nil
+}
+
+// PINFailures returns the wrong PIN attempts recorded for code within ttlPINLock.
+func (s *Store) PINFailures(ctx context.Context, code string) (int64, error) {
+	n, err := s.rdb.Get(ctx, s.keyPINFails(code)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// Spectator helpers: ch:<code>:spectators(set of rooms) + ch:spectate:room:<room>(code)
+func (s *Store) SpectatorRooms(ctx context.Context, code string) ([]string, error) {
+	return s.rdb.SMembers(ctx, s.keySpectators(code)).Result()
+}
+
+func (s *Store) SpectatingCode(ctx context.Context, room string) (string, error) {
+	code, err := s.rdb.Get(ctx, s.keySpectateRoom(room)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return code, err
+}
+
+// Speaker helpers: ch:speakers:<room>(hash 소문자 표시 이름 → user ID, TTL)
+func (s *Store) SaveSpeaker(ctx context.Context, room, userID, name string) error {
+	key := s.keySpeakers(room)
+	pipe := s.rdb.TxPipeline()
+	pipe.HSet(ctx, key, speakerField(name), strings.TrimSpace(userID))
+	pipe.Expire(ctx, key, ttlSpeakers)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// SpeakerID returns the user last seen in room under name, or "".
+func (s *Store) SpeakerID(ctx context.Context, room, name string) (string, error) {
+	id, err := s.rdb.HGet(ctx, s.keySpeakers(room), speakerField(name)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return id, err
+}
+
+func speakerField(name string) string { return strings.ToLower(strings.TrimSpace(name)) }
+
+// Challenge helpers: ch:challenge:<room>:<target id>(JSON, TTL) + ch:challenge:by:<user>(challenge key)
+func (s *Store) CreateChallenge(ctx context.Context, c *Challenge, ttl time.Duration) (bool, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return false, err
+	}
+	key := s.keyChallenge(c.Room, c.TargetID)
+	ok, err := s.rdb.SetNX(ctx, key, raw, ttl).Result()
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := s.rdb.Set(ctx, s.keyChallengeBy(c.ChallengerID), key, ttl).Err(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TakeChallenge atomically removes and returns the challenge stored at key (nil when absent or expired).
+func (s *Store) TakeChallenge(ctx context.Context, key string) (*Challenge, error) {
+	raw, err := s.rdb.GetDel(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c Challenge
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	_ = s.rdb.Del(ctx, s.keyChallengeBy(c.ChallengerID)).Err()
+	return &c, nil
+}
+
+// TakeChallengeIf removes and returns the challenge at key only if check accepts it. The challenge is
+// read under WATCH, so it stays in place when check fails and is consumed at most once. 없거나 만료됐거나
+// 확인 중 바뀌었으면 (nil, nil).
+func (s *Store) TakeChallengeIf(ctx context.Context, key string, check func(*Challenge) error) (*Challenge, error) {
+	var taken *Challenge
+	err := s.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		raw, err := tx.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var c Challenge
+		if err := json.Unmarshal(raw, &c); err != nil {
+			return err
+		}
+		if err := check(&c); err != nil {
+			return err
+		}
+		pipe := tx.TxPipeline()
+		pipe.Del(ctx, key, s.keyChallengeBy(c.ChallengerID))
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		taken = &c
+		return nil
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return taken, nil
+}
+
+// OutgoingChallengeKey returns the key of the user's pending outgoing challenge, or "".
+func (s *Store) OutgoingChallengeKey(ctx context.Context, userID string) (string, error) {
+	key, err := s.rdb.Get(ctx, s.keyChallengeBy(userID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return key, err
+}
+
+// Match queue helpers: ch:match:queue(zset user → enqueue time) + ch:match:entry:<user>(JSON, TTL)
+func (s *Store) SaveMatchEntry(ctx context.Context, e *MatchEntry) error {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	pipe := s.rdb.TxPipeline()
+	pipe.Set(ctx, s.keyMatchEntry(e.UserID), raw, ttlMatchEntry)
+	pipe.ZAdd(ctx, s.keyMatchQueue(), redis.Z{Score: float64(e.JoinedAt.UnixNano()), Member: e.UserID})
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// ClaimOrEnqueue removes and returns the oldest waiting entry accepted by pick, or queues self when
+// none fits. 대기열을 WATCH한 채 고르고 쓰므로 동시에 들어온 두 사용자가 모두 대기하거나 같은 상대를
+// 가져가지 않습니다(경합 시 처음부터 다시 고름). 이미 대기 중이면 ErrAlreadyQueued.
+func (s *Store) ClaimOrEnqueue(ctx context.Context, self *MatchEntry, pick func(opp *MatchEntry) bool) (*MatchEntry, error) {
+	queueKey := s.keyMatchQueue()
+	selfRaw, err := json.Marshal(self)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 0; attempt < maxMatchAttempts; attempt++ {
+		var claimed *MatchEntry
+		err := s.rdb.Watch(ctx, func(tx *redis.Tx) error {
+			claimed = nil
+			users, err := tx.ZRange(ctx, queueKey, 0, -1).Result()
+			if err != nil {
+				return err
+			}
+			var stale []interface{}
+			for _, uid := range users {
+				if uid == self.UserID {
+					return ErrAlreadyQueued
+				}
+				if claimed != nil {
+					continue
+				}
+				raw, err := tx.Get(ctx, s.keyMatchEntry(uid)).Bytes()
+				if err == redis.Nil {
+					// 항목이 만료된 대기자 정리
+					stale = append(stale, uid)
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				var opp MatchEntry
+				if err := json.Unmarshal(raw, &opp); err != nil {
+					return err
+				}
+				if pick(&opp) {
+					claimed = &opp
+				}
+			}
+			pipe := tx.TxPipeline()
+			if len(stale) > 0 {
+				pipe.ZRem(ctx, queueKey, stale...)
+			}
+			if claimed != nil {
+				pipe.ZRem(ctx, queueKey, claimed.UserID)
+				pipe.Del(ctx, s.keyMatchEntry(claimed.UserID))
+			} else {
+				pipe.Set(ctx, s.keyMatchEntry(self.UserID), selfRaw, ttlMatchEntry)
+				pipe.ZAdd(ctx, queueKey, redis.Z{Score: float64(self.JoinedAt.UnixNano()), Member: self.UserID})
+			}
+			_, err = pipe.Exec(ctx)
+			return err
+		}, queueKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return claimed, nil
+	}
+	return nil, redis.TxFailedErr
+}
+
+func (s *Store) LoadMatchEntry(ctx context.Context, userID string) (*MatchEntry, error) {
+	raw, err := s.rdb.Get(ctx, s.keyMatchEntry(userID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var e MatchEntry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// RemoveFromQueue removes the user from the queue; claimed is false when another caller removed it first.
+func (s *Store) RemoveFromQueue(ctx context.Context, userID string) (bool, error) {
+	n, err := s.rdb.ZRem(ctx, s.keyMatchQueue(), userID).Result()
+	if err != nil {
+		return false, err
+	}
+	_ = s.rdb.Del(ctx, s.keyMatchEntry(userID)).Err()
+	return n > 0, nil
+}
+
+func (s *Store) QueueLength(ctx context.Context) (int64, error) {
+	return s.rdb.ZCard(ctx, s.keyMatchQueue()).Result()
+}
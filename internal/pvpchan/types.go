@@ -1,6 +1,9 @@
 package pvpchan
 
-import "time"
+import (
+    "strings"
+    "time"
+)
 
 // ChannelState represents the lifecycle of a PvP channel.
 type ChannelState string
@@ -21,6 +24,44 @@ const (
     ColorRandom ColorChoice = "random"
 )
 
+// ParseColorChoice maps a user token (백/흑/랜덤, white/black/random) to a ColorChoice.
+func ParseColorChoice(token string) (ColorChoice, bool) {
+    switch strings.ToLower(strings.TrimSpace(token)) {
+    case "백", "white", "w":
+        return ColorWhite, true
+    case "흑", "black", "b":
+        return ColorBlack, true
+    case "랜덤", "무작위", "random":
+        return ColorRandom, true
+    default:
+        return "", false
+    }
+}
+
+// normalizeColor treats anything other than white/black as random.
+func normalizeColor(c ColorChoice) ColorChoice {
+    switch c {
+    case ColorWhite, ColorBlack:
+        return c
+    }
+    return ColorRandom
+}
+
+// resolveCreatorColor decides the creator's side from both preferences.
+// 한쪽만 지정하면 그 선호를 따르고, 둘 다 같은 색을 원하면 랜덤으로 정합니다.
+func resolveCreatorColor(creator, joiner ColorChoice) ColorChoice {
+    creator, joiner = normalizeColor(creator), normalizeColor(joiner)
+    switch {
+    case creator != ColorRandom && creator != joiner:
+        return creator
+    case creator == ColorRandom && joiner == ColorWhite:
+        return ColorBlack
+    case creator == ColorRandom && joiner == ColorBlack:
+        return ColorWhite
+    }
+    return ColorRandom
+}
+
 // ChannelMeta is stored as JSON in Redis under ch:<code>.
 type ChannelMeta struct {
     ID          string       `json:"id"`
@@ -30,6 +71,14 @@ type ChannelMeta struct {
     CreatorID   string `json:"creator_id"`
     CreatorName string `json:"creator_name"`
     CreatorRoom string `json:"creator_room"`
+    // CreatorColor: 생성자의 색 선호(white|black|random), 대기방 목록에 표시
+    CreatorColor ColorChoice `json:"creator_color,omitempty"`
+    // Unlisted: 대기방 목록(방 리스트)에 노출하지 않음, 코드는 직접 공유
+    Unlisted bool `json:"unlisted,omitempty"`
+    // PINHash: 참가 비번의 해시(hashPIN), 비어 있으면 비번 없이 참가
+    PINHash string `json:"pin_hash,omitempty"`
+    // Correspondence: 통신 대국 대기방 — 시작되는 대국은 TTL 없이 DB에 보존되고 채널도 길게 유지
+    Correspondence bool `json:"correspondence,omitempty"`
 
     WhiteID     string `json:"white_id,omitempty"`
     WhiteName   string `json:"white_name,omitempty"`
@@ -39,6 +88,86 @@ type ChannelMeta struct {
     GameID      string `json:"game_id,omitempty"`
 }
 
+// Challenge is a pending direct challenge to a named player in one room. 이름은 신청 시점에 방에서 본
+// 사용자 ID(TargetID)로 확정되며, Redis ch:challenge:<room>:<target id> 에 TTL(응답 기한)과 함께 저장되고
+// 대기방 목록(ch:lobby)에는 올라가지 않습니다.
+type Challenge struct {
+    Room           string    `json:"room"`
+    ChallengerID   string    `json:"challenger_id"`
+    ChallengerName string    `json:"challenger_name"`
+    TargetID       string    `json:"target_id"`
+    TargetName     string    `json:"target_name"`
+    CreatedAt      time.Time `json:"created_at"`
+    ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// MatchEntry is a player waiting in the random matching queue (ch:match:entry:<user>).
+type MatchEntry struct {
+    UserID   string    `json:"user_id"`
+    Name     string    `json:"name"`
+    Room     string    `json:"room"`
+    Rating   int       `json:"rating"`
+    JoinedAt time.Time `json:"joined_at"`
+}
+
+// LobbyScope limits which lobbies a room sees in the lobby list.
+type LobbyScope string
+
+const (
+    // ScopeRoom: 요청한 방에서 만든 대기방만
+    ScopeRoom LobbyScope = "room"
+    // ScopeAllowed: 허용 방(ALLOWED_ROOMS)에서 만든 대기방
+    ScopeAllowed LobbyScope = "allowed"
+    // ScopeGlobal: 모든 대기방
+    ScopeGlobal LobbyScope = "global"
+)
+
+// ParseLobbyScope maps a config value or user token (이방/허용/전체) to a LobbyScope.
+func ParseLobbyScope(token string) (LobbyScope, bool) {
+    switch strings.ToLower(strings.TrimSpace(token)) {
+    case "room", "이방", "여기":
+        return ScopeRoom, true
+    case "allowed", "허용":
+        return ScopeAllowed, true
+    case "global", "전체":
+        return ScopeGlobal, true
+    default:
+        return "", false
+    }
+}
+
+// ClampLobbyScope narrows requested so it never exceeds max (room < allowed < global).
+func ClampLobbyScope(requested, max LobbyScope) LobbyScope {
+    rank := func(s LobbyScope) int {
+        switch s {
+        case ScopeRoom:
+            return 0
+        case ScopeAllowed:
+            return 1
+        case ScopeGlobal:
+            return 2
+        }
+        return -1
+    }
+    if rank(max) < 0 {
+        max = ScopeRoom
+    }
+    if rank(requested) < 0 || rank(requested) > rank(max) {
+        return max
+    }
+    return requested
+}
+
+// LobbyOptions configures a lobby created via MakeLobby.
+type LobbyOptions struct {
+    Color    ColorChoice
+    Unlisted bool
+    // PIN: 참가 비번(4~12자, 공백 없음), 비어 있으면 비번 없음
+    PIN string
+    // Correspondence: 통신 대국(방 간 장기 대국)으로 시작
+    Correspondence bool
+}
+
 // Results
 type MakeResult struct {
     Code string
@@ -51,16 +180,46 @@ type JoinResult struct {
     Meta    *ChannelMeta
 }
 
+// MatchResult: Matched이면 Opponent와 대국이 시작되었고, 아니면 대기열에 등록되어 Waiting명이 대기 중입니다.
+type MatchResult struct {
+    Matched  bool
+    GameID   string
+    Meta     *ChannelMeta
+    Opponent *MatchEntry
+    Waiting  int64
+}
+
 // Errors
 var (
     ErrInvalidArgs   = errf("invalid arguments")
     ErrChannelGone   = errf("channel not found or expired")
     ErrChannelActive = errf("channel already active")
     ErrFull          = errf("channel already has two participants")
-    // 플레이어가 동일 방에서 이미 진행 중인 대국이 있는 경우
-    ErrPlayerBusyInRoom = errf("player has active game in this room")
+    // 플레이어가 동시 진행 가능한 대국 수(pvpchess SetMaxGamesPerUser)를 이미 채운 경우
+    ErrPlayerBusy = errf("player has too many active games")
     // 동일 사용자가 동시에 2개 이상 대기방 생성 불가
     ErrCreatorHasLobby = errf("user already has a lobby")
+    // 취소할 본인 대기방이 없음
+    ErrNoLobby = errf("no open lobby")
+    // 참가 비번: 형식 오류 / 비번 필요 / 불일치 / 실패 횟수 초과로 잠김
+    ErrInvalidPIN  = errf("pin must be 4-12 characters without spaces")
+    ErrPINRequired = errf("lobby requires a pin")
+    ErrWrongPIN    = errf("wrong lobby pin")
+    ErrPINLocked   = errf("too many wrong pin attempts")
+    // 관전: 진행 중인 대국이 아님 / 관전 방 수 초과 / 대국 참가 방 / 이미 다른 대국 관전 중
+    ErrNotInProgress     = errf("channel has no game in progress")
+    ErrSpectatorLimit    = errf("spectator room limit reached")
+    ErrRoomIsPlayerRoom  = errf("room already receives this game")
+    ErrAlreadySpectating = errf("room already spectates another game")
+    ErrNotSpectating     = errf("room is not spectating")
+    // 지정 도전: 이미 대기 중인 신청 / 받은(보낸) 신청 없음 또는 만료 / 자기 자신 지정 / 방에서 본 적 없는 이름
+    ErrChallengePending = errf("challenge already pending")
+    ErrNoChallenge      = errf("no pending challenge")
+    ErrSelfChallenge    = errf("cannot challenge yourself")
+    ErrUnknownTarget    = errf("challenge target not seen in room")
+    // 랜덤 매칭: 이미 대기열에 있음 / 대기열에 없음
+    ErrAlreadyQueued = errf("already waiting in match queue")
+    ErrNotQueued     = errf("not in match queue")
 )
 
 type staticErr string
@@ -77,6 +77,7 @@ func (m *Manager) ToDTOForViewer(ctx context.Context, g *Game, viewerID string)
 		FEN:         g.FEN,
 		BoardImage:  png,
 		MoveCount:   len(g.MovesUCI),
+		PlayerColor: string(colorFrom(viewerColor)),
 	}
 	return state, nil
 }
@@ -0,0 +1,138 @@
+package pvpchess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"go.uber.org/zap"
+)
+
+// SetCorrespondenceIdle sets how long a correspondence game may sit without a move before
+// SweepAbandoned finalizes it (0 keeps correspondence games out of the sweep).
+func (m *Manager) SetCorrespondenceIdle(d time.Duration) {
+	if m != nil {
+		m.corrIdle = d
+	}
+}
+
+// RestoreCorrespondence reloads ACTIVE correspondence games from Postgres into Redis (startup).
+// Redis에 더 최신 상태가 있으면 그대로 두고, 인덱스만 다시 채웁니다.
+func (m *Manager) RestoreCorrespondence(ctx context.Context) (int, error) {
+	if m == nil || m.rdb == nil || m.repo == nil {
+		return 0, nil
+	}
+	games, err := m.repo.ActiveGames(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, g := range games {
+		if cur, _ := m.cached(ctx, g.ID); cur == nil || cur.UpdatedAt.Before(g.UpdatedAt) {
+			if err := m.cache(ctx, g); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if err := m.indexCorrespondence(ctx, g); err != nil {
+			return n, err
+		}
+	}
+	obslog.L().Info("pvp_correspondence_restore", zap.Int("stored", len(games)), zap.Int("restored", n))
+	return n, nil
+}
+
+// persistCorrespondence writes a correspondence game to Postgres ahead of its Redis write:
+// 진행 중이면 upsert, 종료되면 행을 지웁니다. 재시작 후에는 이 행에서 복원하므로, 실패하면 호출자가
+// Redis 쓰기도 포기하고 오류를 돌려줍니다.
+func (m *Manager) persistCorrespondence(ctx context.Context, g *Game) error {
+	if m == nil || g == nil || !g.Correspondence {
+		return nil
+	}
+	if g.Status == StatusActive {
+		if err := m.repo.SaveActiveGame(ctx, g); err != nil {
+			return fmt.Errorf("save correspondence game: %w", err)
+		}
+		return nil
+	}
+	if err := m.repo.DeleteActiveGame(ctx, g.ID); err != nil {
+		return fmt.Errorf("delete correspondence game: %w", err)
+	}
+	return nil
+}
+
+// resyncCorrespondence rewrites the Postgres row from Redis after a WATCH transaction that had
+// already written Postgres lost to a concurrent update.
+func (m *Manager) resyncCorrespondence(ctx context.Context, id string) {
+	g, err := m.cached(ctx, id)
+	if err == nil {
+		err = m.persistCorrespondence(ctx, g)
+	}
+	if err != nil {
+		obslog.L().Error("pvp_correspondence_resync_error", zap.String("game_id", id), zap.Error(err))
+	}
+}
+
+// unindexCorrespondence drops a finished correspondence game from the players' correspondence index.
+func (m *Manager) unindexCorrespondence(ctx context.Context, g *Game) {
+	if m == nil || g == nil || !g.Correspondence || g.Status == StatusActive {
+		return
+	}
+	_ = m.rdb.SRem(ctx, idxCorrKey(g.WhiteID), g.ID).Err()
+	_ = m.rdb.SRem(ctx, idxCorrKey(g.BlackID), g.ID).Err()
+}
+
+// loadCorrespondence is the Redis-miss fallback of get: 저장소에 남은 통신 대국이면 Redis에 다시 적재합니다.
+func (m *Manager) loadCorrespondence(ctx context.Context, id string) (*Game, error) {
+	if m.repo == nil {
+		return nil, nil
+	}
+	g, err := m.repo.LoadActiveGame(ctx, id)
+	if err != nil || g == nil {
+		return nil, err
+	}
+	if err := m.cache(ctx, g); err != nil {
+		return nil, err
+	}
+	_ = m.indexCorrespondence(ctx, g)
+	return g, nil
+}
+
+// indexCorrespondence adds the game to both players' non-expiring correspondence index.
+func (m *Manager) indexCorrespondence(ctx context.Context, g *Game) error {
+	for _, uid := range []string{g.WhiteID, g.BlackID} {
+		if strings.TrimSpace(uid) == "" {
+			continue
+		}
+		if err := m.rdb.SAdd(ctx, idxCorrKey(uid), g.ID).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cached reads the game from Redis only (no repository fallback).
+func (m *Manager) cached(ctx context.Context, id string) (*Game, error) {
+	raw, err := m.rdb.Get(ctx, gameKey(id)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var g Game
+	if err := json.Unmarshal(raw, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// cache writes the game to Redis without syncing back to the repository.
+func (m *Manager) cache(ctx context.Context, g *Game) error {
+	pipe := m.rdb.TxPipeline()
+	if err := stageGame(ctx, pipe, g, ""); err != nil {
+		return err
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
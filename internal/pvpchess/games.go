@@ -0,0 +1,191 @@
+package pvpchess
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ttlDefaultGame: 방별 기본 대국 지정 유지 기간 (대국이 끝나면 읽을 때 무시됨)
+const ttlDefaultGame = 30 * 24 * time.Hour
+
+func defaultGameKey(userID, room string) string {
+	return "pvp:default:" + strings.TrimSpace(userID) + ":" + strings.TrimSpace(room)
+}
+
+// SetMaxGamesPerUser caps how many ACTIVE games one user may play at once (0 disables the cap).
+func (m *Manager) SetMaxGamesPerUser(n int) {
+	if m != nil {
+		m.maxPerUser = n
+	}
+}
+
+// CanStartGame reports ErrTooManyUserGames when the user already plays the maximum number of games.
+// 대기방 생성/참가, 도전, 매칭 진입 시 미리 안내하기 위한 확인이며, 상한 자체는 대국 생성(saveNew)이 지킵니다.
+func (m *Manager) CanStartGame(ctx context.Context, userID string) error {
+	if m == nil || m.maxPerUser <= 0 {
+		return nil
+	}
+	games, err := m.ActiveGamesByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(games) >= m.maxPerUser {
+		return ErrTooManyUserGames
+	}
+	return nil
+}
+
+// DiscardGame deletes a game that was created but could not be started (예: 매칭 후 채널 저장 실패).
+// 종료 기록·레이팅·결과 훅 없이 게임 키와 플레이어 인덱스, pvp:active 자리를 지웁니다.
+func (m *Manager) DiscardGame(ctx context.Context, g *Game) error {
+	if m == nil || m.rdb == nil || g == nil {
+		return fmt.Errorf("pvp manager not initialized")
+	}
+	if g.Correspondence && m.repo != nil {
+		if err := m.repo.DeleteActiveGame(ctx, g.ID); err != nil {
+			return fmt.Errorf("delete correspondence game: %w", err)
+		}
+	}
+	pipe := m.rdb.TxPipeline()
+	pipe.Del(ctx, gameKey(g.ID))
+	pipe.SRem(ctx, activeGamesKey, g.ID)
+	for _, uid := range []string{g.WhiteID, g.BlackID} {
+		pipe.SRem(ctx, idxUserKey(uid), g.ID)
+		pipe.SRem(ctx, idxCorrKey(uid), g.ID)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ActiveGamesByUser lists every ACTIVE game the user plays, ordered by handle.
+func (m *Manager) ActiveGamesByUser(ctx context.Context, userID string) ([]*Game, error) {
+	if m == nil || m.rdb == nil {
+		return nil, fmt.Errorf("pvp manager not initialized")
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, nil
+	}
+	ids, err := m.userGameIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var out []*Game
+	for _, id := range ids {
+		g, err := m.get(ctx, id)
+		if err != nil || g == nil || g.Status != StatusActive {
+			continue
+		}
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Handle != out[j].Handle {
+			return out[i].Handle < out[j].Handle
+		}
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
+
+// GameByHandle returns the user's ACTIVE game numbered handle (#N), or ErrNoSuchGame.
+func (m *Manager) GameByHandle(ctx context.Context, userID string, handle int) (*Game, error) {
+	games, err := m.ActiveGamesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range games {
+		if g.Handle == handle && handle > 0 {
+			return g, nil
+		}
+	}
+	return nil, ErrNoSuchGame
+}
+
+// ResolveGame picks the game a move from room should go to.
+// handle > 0이면 그 대국(방 무관), 아니면 이 방의 기본 대국 → 이 방의 유일한 대국 → 내 차례인 유일한 대국 순이며,
+// 그래도 고를 수 없으면 ErrAmbiguousGame을 반환합니다. 이 방에 대국이 없으면 (nil, nil)입니다.
+func (m *Manager) ResolveGame(ctx context.Context, userID, room string, handle int) (*Game, error) {
+	if handle > 0 {
+		return m.GameByHandle(ctx, userID, handle)
+	}
+	games, err := m.ActiveGamesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	room = strings.TrimSpace(room)
+	def := m.DefaultGameID(ctx, userID, room)
+	var inRoom, myTurn []*Game
+	for _, g := range games {
+		if g.OriginRoom != room && g.ResolveRoom != room {
+			continue
+		}
+		if g.ID == def {
+			return g, nil
+		}
+		inRoom = append(inRoom, g)
+		if playerColorOf(g, userID) == string(g.Turn) {
+			myTurn = append(myTurn, g)
+		}
+	}
+	switch {
+	case len(inRoom) == 0:
+		return nil, nil
+	case len(inRoom) == 1:
+		return inRoom[0], nil
+	case len(myTurn) == 1:
+		return myTurn[0], nil
+	}
+	return nil, ErrAmbiguousGame
+}
+
+// SetDefaultGame makes game #handle the user's default in room; handle 0 clears the setting.
+// 기본 대국은 이 방에서 진행 중인 대국만 지정할 수 있습니다.
+func (m *Manager) SetDefaultGame(ctx context.Context, userID, room string, handle int) (*Game, error) {
+	if m == nil || m.rdb == nil {
+		return nil, fmt.Errorf("pvp manager not initialized")
+	}
+	if strings.TrimSpace(userID) == "" || strings.TrimSpace(room) == "" {
+		return nil, fmt.Errorf("invalid parameters")
+	}
+	if handle <= 0 {
+		return nil, m.rdb.Del(ctx, defaultGameKey(userID, room)).Err()
+	}
+	g, err := m.GameByHandle(ctx, userID, handle)
+	if err != nil {
+		return nil, err
+	}
+	room = strings.TrimSpace(room)
+	if g.OriginRoom != room && g.ResolveRoom != room {
+		return nil, ErrGameNotInRoom
+	}
+	if err := m.rdb.Set(ctx, defaultGameKey(userID, room), g.ID, ttlDefaultGame).Err(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// DefaultGameID returns the game id set as the user's default in room ("" when none).
+func (m *Manager) DefaultGameID(ctx context.Context, userID, room string) string {
+	if m == nil || m.rdb == nil {
+		return ""
+	}
+	id, _ := m.rdb.Get(ctx, defaultGameKey(userID, room)).Result()
+	return id
+}
+
+// freeHandle returns the smallest #N not used by games (두 플레이어의 ACTIVE 대국).
+// saveNew가 플레이어 인덱스를 WATCH한 채 호출하므로 동시에 만든 대국끼리 번호가 겹치지 않습니다.
+func freeHandle(games []*Game) int {
+	used := map[int]bool{}
+	for _, g := range games {
+		used[g.Handle] = true
+	}
+	n := 1
+	for used[n] {
+		n++
+	}
+	return n
+}
@@ -25,6 +25,16 @@ type Manager struct {
     rdb      *redis.Client
     renderer svcchess.BoardRenderer
     repo     *Repository
+    // maxActive: 인스턴스 전체 ACTIVE 대국 상한 (0이면 제한 없음)
+    maxActive int
+    // resultHooks: 대국 종료(persistIfFinal) 시 호출되는 콜백 (토너먼트 결과 반영 등)
+    resultHooks []func(context.Context, *Game)
+    // corrIdle: 통신 대국 방치 종료 기준 (0이면 방치 정리 대상 아님)
+    corrIdle time.Duration
+    // maxPerUser: 사용자 1명이 동시에 진행할 수 있는 ACTIVE 대국 수 (0이면 제한 없음)
+    maxPerUser int
+    // coinFlip: 랜덤 색 배정에서 true면 상대가 백 (nil이면 crypto/rand)
+    coinFlip func() bool
 }
 
 func NewManager(redisURL string) (*Manager, error) {
@@ -52,11 +62,42 @@ func (m *Manager) AttachRepository(r *Repository) {
     }
 }
 
+// SetMaxActiveGames caps the number of ACTIVE games across the instance (0 disables the cap).
+func (m *Manager) SetMaxActiveGames(n int) {
+    if m != nil {
+        m.maxActive = n
+    }
+}
+
+// SetCoinFlip replaces the random color draw (nil restores crypto/rand); true gives White to the target.
+// 시나리오 테스트처럼 색 배정을 고정해야 할 때만 사용합니다.
+func (m *Manager) SetCoinFlip(fn func() bool) {
+    if m != nil {
+        m.coinFlip = fn
+    }
+}
+
+func (m *Manager) flipCoin() bool {
+    if m.coinFlip != nil {
+        return m.coinFlip()
+    }
+    n, _ := rand.Int(rand.Reader, big.NewInt(2))
+    return n != nil && n.Int64() == 0
+}
+
+// OnResult registers fn to run whenever a game reaches a final state.
+// 저장소 연결 여부와 무관하게 호출되며, 레이팅 반영 이후라 변동폭 필드가 채워져 있습니다.
+// 등록은 대국 처리 시작 전에만 해야 합니다.
+func (m *Manager) OnResult(fn func(context.Context, *Game)) {
+    if m != nil && fn != nil {
+        m.resultHooks = append(m.resultHooks, fn)
+    }
+}
+
 // CreateGameFromChallenge creates a PvP game from a challenge with auto-accept outcome.
 func (m *Manager) CreateGameFromChallenge(ctx context.Context, originRoom, resolveRoom, challengerID, challengerName, targetID, targetName, colorChoice, timeControl string) (*Game, error) {
     if m == nil || m.rdb == nil { return nil, fmt.Errorf("pvp manager not initialized") }
     if challengerID == "" || targetID == "" { return nil, fmt.Errorf("invalid participants") }
-
     // assign colors
     whiteID, whiteName := challengerID, challengerName
     blackID, blackName := targetID, targetName
@@ -67,7 +108,7 @@ func (m *Manager) CreateGameFromChallenge(ctx context.Context, originRoom, resol
     case "black", "b":
         whiteID, whiteName, blackID, blackName = targetID, targetName, challengerID, challengerName
     default: // random using crypto/rand
-        if n, _ := rand.Int(rand.Reader, big.NewInt(2)); n != nil && n.Int64() == 0 {
+        if m.flipCoin() {
             whiteID, whiteName, blackID, blackName = targetID, targetName, challengerID, challengerName
         }
     }
@@ -87,39 +128,36 @@ func (m *Manager) CreateGameFromChallenge(ctx context.Context, originRoom, resol
         ResolveRoom: strings.TrimSpace(resolveRoom),
         CreatedAt:   time.Now(),
         UpdatedAt:   time.Now(),
+        Correspondence: strings.EqualFold(strings.TrimSpace(timeControl), TimeControlCorrespondence),
     }
-
-    if err := m.save(ctx, g); err != nil { return nil, err }
+    // 참가한 방 기록: 신청자는 originRoom, 상대는 resolveRoom (통신 대국 차례 알림 대상)
+    if g.WhiteID == strings.TrimSpace(challengerID) {
+        g.WhiteRoom, g.BlackRoom = g.OriginRoom, g.ResolveRoom
+    } else {
+        g.WhiteRoom, g.BlackRoom = g.ResolveRoom, g.OriginRoom
+    }
+    if err := m.saveNew(ctx, g); err != nil { return nil, err }
     obslog.L().Info("pvp_game_create",
         zap.String("game_id", g.ID),
         zap.String("origin_room", g.OriginRoom),
         zap.String("resolve_room", g.ResolveRoom),
         zap.String("white_id", g.WhiteID),
         zap.String("black_id", g.BlackID),
+        zap.Bool("correspondence", g.Correspondence),
+        zap.Int("handle", g.Handle),
     )
-    if err := m.indexParticipants(ctx, g.ID, g.WhiteID, g.BlackID); err != nil { return nil, err }
+    if g.Correspondence {
+        if err := m.indexCorrespondence(ctx, g); err != nil { return nil, err }
+    }
     return g, nil
 }
 
-// GetActiveGameByUser returns the latest active game for a user.
+// GetActiveGameByUser returns the user's most recently updated ACTIVE game (방 무관).
 func (m *Manager) GetActiveGameByUser(ctx context.Context, userID string) (*Game, error) {
-    if m == nil || m.rdb == nil { return nil, fmt.Errorf("pvp manager not initialized") }
-    if strings.TrimSpace(userID) == "" { return nil, nil }
-    key := idxUserKey(userID)
-    ids, err := m.rdb.SMembers(ctx, key).Result()
-    if err != nil { return nil, err }
-    if len(ids) == 0 { return nil, nil }
-    // Prefer most recently updated
-    var list []*Game
-    for _, id := range ids {
-        g, gerr := m.get(ctx, id)
-        if gerr == nil && g != nil && g.Status == StatusActive {
-            list = append(list, g)
-        }
-    }
-    if len(list) == 0 { return nil, nil }
-    sort.Slice(list, func(i, j int) bool { return list[i].UpdatedAt.After(list[j].UpdatedAt) })
-    return list[0], nil
+    games, err := m.ActiveGamesByUser(ctx, userID)
+    if err != nil || len(games) == 0 { return nil, err }
+    sort.Slice(games, func(i, j int) bool { return games[i].UpdatedAt.After(games[j].UpdatedAt) })
+    return games[0], nil
 }
 
 // GetActiveGameByUserInRoom returns the most recent ACTIVE game for the user in the given room.
@@ -129,8 +167,7 @@ func (m *Manager) GetActiveGameByUserInRoom(ctx context.Context, userID, room st
     userID = strings.TrimSpace(userID)
     room = strings.TrimSpace(room)
     if userID == "" || room == "" { return nil, nil }
-    key := idxUserKey(userID)
-    ids, err := m.rdb.SMembers(ctx, key).Result()
+    ids, err := m.userGameIDs(ctx, userID)
     if err != nil { return nil, err }
     if len(ids) == 0 { return nil, nil }
     var list []*Game
@@ -143,145 +180,38 @@ func (m *Manager) GetActiveGameByUserInRoom(ctx context.Context, userID, room st
         }
     }
     if len(list) == 0 { return nil, nil }
-    sort.Slice(list, func(i, j int) bool { return list[i].UpdatedAt.After(list[j].UpdatedAt) })
-    return list[0], nil
-}
-
-// PlayMove applies a move for the requesting user (UCI preferred, fallback to SAN).
-func (m *Manager) PlayMove(ctx context.Context, userID, moveStr string) (*Game, string, error) {
-    if strings.TrimSpace(userID) == "" { return nil, "", fmt.Errorf("invalid user") }
-    g, err := m.GetActiveGameByUser(ctx, userID)
-    if err != nil || g == nil { return nil, "", err }
-
-    // optimistic concurrency control using WATCH on game key
-    gameK := gameKey(g.ID)
-    oldLen := len(g.MovesUCI)
-
-    var resultText string
-    // sentinels for flow control
-    var (
-        errNotYourTurn = errors.New("not_your_turn")
-        errIllegalMove = errors.New("illegal_move")
-    )
-
-    err = m.rdb.Watch(ctx, func(tx *redis.Tx) error {
-        raw, err := tx.Get(ctx, gameK).Bytes()
-        if err == redis.Nil {
-            return fmt.Errorf("game not found")
-        }
-        if err != nil { return err }
-        var cur Game
-        if jerr := json.Unmarshal(raw, &cur); jerr != nil { return jerr }
-        if cur.Status != StatusActive { return redis.TxFailedErr }
-        // Ensure no concurrent move applied
-        if len(cur.MovesUCI) != oldLen { return redis.TxFailedErr }
-
-        // validate turn
-        playerColor := m.playerColor(&cur, userID)
-        if playerColor == "" { return fmt.Errorf("user not in game") }
-        if (cur.Turn == White && playerColor != "white") || (cur.Turn == Black && playerColor != "black") {
-            return errNotYourTurn
+    // 이 방의 기본 대국(SetDefaultGame)이 유효하면 우선
+    if def := m.DefaultGameID(ctx, userID, room); def != "" {
+        for _, g := range list {
+            if g.ID == def { return g, nil }
         }
-
-        // reconstruct and apply move
-        game := reconstruct(cur.FEN, cur.MovesUCI)
-        if game == nil { return fmt.Errorf("failed to reconstruct game") }
-        pos := game.Position()
-        rawMove := strings.TrimSpace(moveStr)
-        uci := strings.ToLower(rawMove)
-        if uci == "" { resultText = "잘못된 수 입력입니다."; return errIllegalMove }
-
-        notationUCI := nchess.UCINotation{}
-        if mv, derr := notationUCI.Decode(pos, uci); derr == nil {
-            game.Move(mv, nil)
-            san := nchess.AlgebraicNotation{}.Encode(pos, mv)
-            cur.MovesUCI = append(cur.MovesUCI, uci)
-            cur.MovesSAN = append(cur.MovesSAN, san)
-        } else {
-            if err := game.PushNotationMove(rawMove, nchess.AlgebraicNotation{}, nil); err != nil {
-                resultText = "유효하지 않은 수입니다."
-                return errIllegalMove
-            }
-            last := lastMove(game)
-            if last == nil { resultText = "유효하지 않은 수입니다."; return errIllegalMove }
-            cur.MovesSAN = append(cur.MovesSAN, nchess.AlgebraicNotation{}.Encode(pos, last))
-            cur.MovesUCI = append(cur.MovesUCI, last.String())
-        }
-
-        cur.FEN = game.FEN()
-        cur.Turn = colorFrom(game.Position().Turn())
-        cur.UpdatedAt = time.Now()
-
-        switch game.Outcome() {
-        case nchess.WhiteWon:
-            cur.Status = StatusFinished
-            cur.Winner = cur.WhiteID
-            cur.Outcome = "white"
-        case nchess.BlackWon:
-            cur.Status = StatusFinished
-            cur.Winner = cur.BlackID
-            cur.Outcome = "black"
-        case nchess.Draw:
-            cur.Status = StatusDraw
-            cur.Outcome = "draw"
-        }
-
-        // persist atomically
-        pipe := tx.TxPipeline()
-        newRaw, _ := json.Marshal(&cur)
-        pipe.Set(ctx, gameK, newRaw, 24*time.Hour)
-        if _, err := pipe.Exec(ctx); err != nil { return err }
-
-        // publish result to outer scope
-        g = &cur
-        who := g.WhiteName
-        if playerColor == "black" { who = g.BlackName }
-        resultText = fmt.Sprintf("%s: %s", who, strings.TrimSpace(moveStr))
-        return nil
-    }, gameK)
-
-    if err != nil {
-        if errors.Is(err, redis.TxFailedErr) {
-            // concurrent update detected
-            return g, "동시 명령이 감지되어 처리되지 않았습니다. 다시 시도해주세요.", nil
-        }
-        if err.Error() == "illegal_move" || errors.Is(err, errors.New("illegal_move")) { // fallback check
-            if strings.TrimSpace(resultText) == "" { resultText = "유효하지 않은 수입니다." }
-            return g, resultText, nil
-        }
-        if err.Error() == "not_your_turn" || errors.Is(err, errors.New("not_your_turn")) {
-            return g, "지금은 상대 차례입니다.", nil
-        }
-        return nil, "", err
     }
-
-    obslog.L().Info("pvp_move",
-        zap.String("game_id", g.ID),
-        zap.String("user_id", strings.TrimSpace(userID)),
-        zap.String("turn", func() string { if g.Turn == White { return "white" }; return "black" }()),
-        zap.String("last_uci", func() string { if n := len(g.MovesUCI); n > 0 { return g.MovesUCI[n-1] }; return "" }()),
-        zap.String("status", string(g.Status)),
-        zap.String("outcome", g.Outcome),
-    )
-    // Persist final results (checkmate/draw) when game is no longer active
-    if g.Status == StatusFinished {
-        _ = m.persistIfFinal(ctx, g, "checkmate")
-    } else if g.Status == StatusDraw {
-        _ = m.persistIfFinal(ctx, g, "draw")
-    }
-
-    return g, resultText, nil
+    sort.Slice(list, func(i, j int) bool { return list[i].UpdatedAt.After(list[j].UpdatedAt) })
+    return list[0], nil
 }
 
 // PlayMoveByRoom applies a move but restricts target selection to the user's ACTIVE game in the given room.
 // 왜: 동일 사용자가 여러 방에서 PvP를 동시 진행할 때 현재 방 외 게임에 수가 적용되는 문제를 방지.
 func (m *Manager) PlayMoveByRoom(ctx context.Context, userID, roomID, moveStr string) (*Game, string, error) {
-    if strings.TrimSpace(userID) == "" || strings.TrimSpace(roomID) == "" {
+    g, err := m.resolveInRoom(ctx, userID, roomID)
+    if err != nil || g == nil { return nil, "", err }
+    return m.playMove(ctx, userID, roomID, g, moveStr)
+}
+
+// PlayMoveInGame applies a move to a game picked explicitly by the player (#N), wherever it is played.
+// 사용자가 해당 대국의 참가자가 아니거나 이미 종료된 대국이면 (nil, "", nil)을 반환합니다.
+func (m *Manager) PlayMoveInGame(ctx context.Context, userID, gameID, moveStr string) (*Game, string, error) {
+    if strings.TrimSpace(userID) == "" || strings.TrimSpace(gameID) == "" {
         return nil, "", fmt.Errorf("invalid parameters")
     }
-    g, err := m.GetActiveGameByUserInRoom(ctx, userID, roomID)
+    g, err := m.get(ctx, gameID)
     if err != nil || g == nil { return nil, "", err }
+    if g.Status != StatusActive || playerColorOf(g, userID) == "" { return nil, "", nil }
+    return m.playMove(ctx, userID, "", g, moveStr)
+}
 
+// playMove applies moveStr to g under WATCH. roomID가 비어 있으면 방 스코프 확인을 생략합니다.
+func (m *Manager) playMove(ctx context.Context, userID, roomID string, g *Game, moveStr string) (*Game, string, error) {
     gameK := gameKey(g.ID)
     oldLen := len(g.MovesUCI)
     var resultText string
@@ -289,7 +219,7 @@ func (m *Manager) PlayMoveByRoom(ctx context.Context, userID, roomID, moveStr st
         errNotYourTurn = errors.New("not_your_turn")
         errIllegalMove = errors.New("illegal_move")
     )
-    err = m.rdb.Watch(ctx, func(tx *redis.Tx) error {
+    err := m.rdb.Watch(ctx, func(tx *redis.Tx) error {
         raw, err := tx.Get(ctx, gameK).Bytes()
         if err == redis.Nil { return fmt.Errorf("game not found") }
         if err != nil { return err }
@@ -297,8 +227,8 @@ func (m *Manager) PlayMoveByRoom(ctx context.Context, userID, roomID, moveStr st
         if jerr := json.Unmarshal(raw, &cur); jerr != nil { return jerr }
         if cur.Status != StatusActive { return redis.TxFailedErr }
         if len(cur.MovesUCI) != oldLen { return redis.TxFailedErr }
-        // 스코프 확인: 여전히 같은 방이어야 함
-        if !(strings.TrimSpace(cur.OriginRoom) == strings.TrimSpace(roomID) || strings.TrimSpace(cur.ResolveRoom) == strings.TrimSpace(roomID)) {
+        // 스코프 확인: 여전히 같은 방이어야 함 (#N 직접 지정은 생략)
+        if roomID != "" && !(strings.TrimSpace(cur.OriginRoom) == strings.TrimSpace(roomID) || strings.TrimSpace(cur.ResolveRoom) == strings.TrimSpace(roomID)) {
             return fmt.Errorf("game not in room")
         }
         // 턴 검증
@@ -316,7 +246,11 @@ func (m *Manager) PlayMoveByRoom(ctx context.Context, userID, roomID, moveStr st
         if uci == "" { resultText = "잘못된 수 입력입니다."; return errIllegalMove }
         notationUCI := nchess.UCINotation{}
         if mv, derr := notationUCI.Decode(pos, uci); derr == nil {
-            game.Move(mv, nil)
+            // Decode는 합법성을 보지 않으므로 Move 실패(불법 수)는 그대로 거부
+            if merr := game.Move(mv, nil); merr != nil {
+                resultText = "유효하지 않은 수입니다."
+                return errIllegalMove
+            }
             san := nchess.AlgebraicNotation{}.Encode(pos, mv)
             cur.MovesUCI = append(cur.MovesUCI, uci)
             cur.MovesSAN = append(cur.MovesSAN, san)
@@ -333,6 +267,11 @@ func (m *Manager) PlayMoveByRoom(ctx context.Context, userID, roomID, moveStr st
         cur.FEN = game.FEN()
         cur.Turn = colorFrom(game.Position().Turn())
         cur.UpdatedAt = time.Now()
+        // 다음 수가 두어지면 대기 중인 무승부 제안/무르기 요청은 만료
+        cur.DrawOfferBy = ""
+        cur.DrawOfferPly = 0
+        cur.TakebackBy = ""
+        cur.TakebackPly = 0
         switch game.Outcome() {
         case nchess.WhiteWon:
             cur.Status = StatusFinished
@@ -346,10 +285,7 @@ func (m *Manager) PlayMoveByRoom(ctx context.Context, userID, roomID, moveStr st
             cur.Status = StatusDraw
             cur.Outcome = "draw"
         }
-        pipe := tx.TxPipeline()
-        newRaw, _ := json.Marshal(&cur)
-        pipe.Set(ctx, gameK, newRaw, 24*time.Hour)
-        if _, err := pipe.Exec(ctx); err != nil { return err }
+        if err := m.commitGame(ctx, tx, &cur, StatusActive); err != nil { return err }
         g = &cur
         who := g.WhiteName
         if m.playerColor(g, userID) == "black" { who = g.BlackName }
@@ -369,6 +305,7 @@ func (m *Manager) PlayMoveByRoom(ctx context.Context, userID, roomID, moveStr st
         }
         return nil, "", err
     }
+    m.unindexCorrespondence(ctx, g)
     obslog.L().Info("pvp_move_by_room",
         zap.String("game_id", g.ID),
         zap.String("room_id", strings.TrimSpace(roomID)),
@@ -386,51 +323,267 @@ func (m *Manager) PlayMoveByRoom(ctx context.Context, userID, roomID, moveStr st
     return g, resultText, nil
 }
 
-func (m *Manager) Resign(ctx context.Context, userID string) (*Game, string, error) {
-    g, err := m.GetActiveGameByUser(ctx, userID)
+// ResignByRoom resigns the user's game in roomID, picked by ResolveGame (기본 대국 → 유일한 대국 → 내 차례인 유일한 대국).
+// 고를 수 없으면 ErrAmbiguousGame, 이 방에 대국이 없으면 (nil, "", nil)입니다.
+func (m *Manager) ResignByRoom(ctx context.Context, userID, roomID string) (*Game, string, error) {
+    g, err := m.resolveInRoom(ctx, userID, roomID)
     if err != nil || g == nil { return nil, "", err }
-    gameK := gameKey(g.ID)
-    err = m.rdb.Watch(ctx, func(tx *redis.Tx) error {
-        raw, err := tx.Get(ctx, gameK).Bytes()
-        if err == redis.Nil { return fmt.Errorf("game not found") }
-        if err != nil { return err }
-        var cur Game
-        if jerr := json.Unmarshal(raw, &cur); jerr != nil { return jerr }
-        if cur.Status != StatusActive { return redis.TxFailedErr }
+    return m.ResignInGame(ctx, userID, g.ID)
+}
+
+// ResignInGame resigns a game picked explicitly (ResolveGame / #N), wherever it is played.
+// 사용자가 해당 대국의 참가자가 아니거나 이미 종료된 대국이면 (nil, "", nil)을 반환합니다.
+func (m *Manager) ResignInGame(ctx context.Context, userID, gameID string) (*Game, string, error) {
+    userID = strings.TrimSpace(userID)
+    g, err := m.updateInGame(ctx, userID, gameID, func(cur *Game) error {
         cur.Status = StatusResigned
-        cur.Winner = opponentID(&cur, userID)
+        cur.Winner = opponentID(cur, userID)
         cur.Outcome = "resign"
-        cur.UpdatedAt = time.Now()
-        pipe := tx.TxPipeline()
-        newRaw, _ := json.Marshal(&cur)
-        pipe.Set(ctx, gameK, newRaw, 24*time.Hour)
-        if _, err := pipe.Exec(ctx); err != nil { return err }
-        g = &cur
         return nil
-    }, gameK)
-    if err != nil {
-        if errors.Is(err, redis.TxFailedErr) {
-            return nil, "", fmt.Errorf("game no longer active")
-        }
-        return nil, "", err
-    }
-    obslog.L().Info("pvp_resign",
+    })
+    if err != nil || g == nil { return nil, "", err }
+    obslog.L().Info("pvp_resign_in_game",
         zap.String("game_id", g.ID),
-        zap.String("resigner", strings.TrimSpace(userID)),
+        zap.String("resigner", userID),
         zap.String("winner", g.Winner),
     )
     _ = m.persistIfFinal(ctx, g, "resignation")
     return g, "기권", nil
 }
 
-// ResignByRoom resigns the active game for a user limited to a specific room scope.
-// 방 스코프 모호성 제거: 같은 유저가 여러 방에서 동시 PvP를 진행하더라도 정확히 해당 방의 게임만 기권 처리.
-func (m *Manager) ResignByRoom(ctx context.Context, userID, roomID string) (*Game, string, error) {
+// OfferDrawByRoom is OfferDrawInGame on the user's game in roomID, picked by ResolveGame (ErrAmbiguousGame when it cannot choose).
+func (m *Manager) OfferDrawByRoom(ctx context.Context, userID, roomID string) (*Game, error) {
+    g, err := m.resolveInRoom(ctx, userID, roomID)
+    if err != nil || g == nil { return nil, err }
+    return m.OfferDrawInGame(ctx, userID, g.ID)
+}
+
+// OfferDrawInGame records a pending draw offer from the user on the ACTIVE game gameID.
+// 제안은 다음 수가 두어지면 자동 만료됩니다. 상대의 제안이 살아 있으면 맞제안은 합의로 보고
+// 무승부로 종료합니다 (반환된 게임의 Status가 StatusDraw).
+func (m *Manager) OfferDrawInGame(ctx context.Context, userID, gameID string) (*Game, error) {
+    userID = strings.TrimSpace(userID)
+    agreed := false
+    g, err := m.updateInGame(ctx, userID, gameID, func(cur *Game) error {
+        agreed = false
+        if offer := strings.TrimSpace(cur.DrawOfferBy); offer != "" && cur.DrawOfferPly == len(cur.MovesUCI) {
+            if offer == userID { return ErrDrawOfferPending }
+            agreed = true
+            agreeDraw(cur)
+            return nil
+        }
+        cur.DrawOfferBy = userID
+        cur.DrawOfferPly = len(cur.MovesUCI)
+        return nil
+    })
+    if err != nil || g == nil { return g, err }
+    if agreed {
+        obslog.L().Info("pvp_draw_accept",
+            zap.String("game_id", g.ID),
+            zap.String("user_id", userID),
+        )
+        _ = m.persistIfFinal(ctx, g, "agreement")
+        return g, nil
+    }
+    obslog.L().Info("pvp_draw_offer",
+        zap.String("game_id", g.ID),
+        zap.String("user_id", userID),
+        zap.Int("ply", g.DrawOfferPly),
+    )
+    return g, nil
+}
+
+// AcceptDrawByRoom is AcceptDrawInGame on the user's game in roomID, picked by ResolveGame (ErrAmbiguousGame when it cannot choose).
+func (m *Manager) AcceptDrawByRoom(ctx context.Context, userID, roomID string) (*Game, error) {
+    g, err := m.resolveInRoom(ctx, userID, roomID)
+    if err != nil || g == nil { return nil, err }
+    return m.AcceptDrawInGame(ctx, userID, g.ID)
+}
+
+// AcceptDrawInGame accepts the opponent's pending draw offer and finishes the game as a draw by agreement.
+func (m *Manager) AcceptDrawInGame(ctx context.Context, userID, gameID string) (*Game, error) {
+    userID = strings.TrimSpace(userID)
+    g, err := m.updateInGame(ctx, userID, gameID, func(cur *Game) error {
+        if err := checkDrawResponder(cur, userID); err != nil { return err }
+        agreeDraw(cur)
+        return nil
+    })
+    if err != nil || g == nil { return g, err }
+    obslog.L().Info("pvp_draw_accept",
+        zap.String("game_id", g.ID),
+        zap.String("user_id", userID),
+    )
+    _ = m.persistIfFinal(ctx, g, "agreement")
+    return g, nil
+}
+
+// DeclineDrawByRoom is DeclineDrawInGame on the user's game in roomID, picked by ResolveGame (ErrAmbiguousGame when it cannot choose).
+func (m *Manager) DeclineDrawByRoom(ctx context.Context, userID, roomID string) (*Game, error) {
+    g, err := m.resolveInRoom(ctx, userID, roomID)
+    if err != nil || g == nil { return nil, err }
+    return m.DeclineDrawInGame(ctx, userID, g.ID)
+}
+
+// DeclineDrawInGame clears the opponent's pending draw offer; the game continues.
+func (m *Manager) DeclineDrawInGame(ctx context.Context, userID, gameID string) (*Game, error) {
+    userID = strings.TrimSpace(userID)
+    g, err := m.updateInGame(ctx, userID, gameID, func(cur *Game) error {
+        if err := checkDrawResponder(cur, userID); err != nil { return err }
+        cur.DrawOfferBy = ""
+        cur.DrawOfferPly = 0
+        return nil
+    })
+    if err != nil || g == nil { return g, err }
+    obslog.L().Info("pvp_draw_decline",
+        zap.String("game_id", g.ID),
+        zap.String("user_id", userID),
+    )
+    return g, nil
+}
+
+// RequestTakebackByRoom is RequestTakebackInGame on the user's game in roomID, picked by ResolveGame (ErrAmbiguousGame when it cannot choose).
+func (m *Manager) RequestTakebackByRoom(ctx context.Context, userID, roomID string) (*Game, error) {
+    g, err := m.resolveInRoom(ctx, userID, roomID)
+    if err != nil || g == nil { return nil, err }
+    return m.RequestTakebackInGame(ctx, userID, g.ID)
+}
+
+// RequestTakebackInGame records a pending takeback request from the user; the opponent must approve it.
+func (m *Manager) RequestTakebackInGame(ctx context.Context, userID, gameID string) (*Game, error) {
+    userID = strings.TrimSpace(userID)
+    g, err := m.updateInGame(ctx, userID, gameID, func(cur *Game) error {
+        if strings.TrimSpace(cur.TakebackBy) != "" && cur.TakebackPly == len(cur.MovesUCI) {
+            return ErrTakebackPending
+        }
+        if takebackPlies(cur, userID) == 0 { return ErrNothingToUndo }
+        cur.TakebackBy = userID
+        cur.TakebackPly = len(cur.MovesUCI)
+        return nil
+    })
+    if err != nil || g == nil { return g, err }
+    obslog.L().Info("pvp_takeback_request",
+        zap.String("game_id", g.ID),
+        zap.String("user_id", userID),
+        zap.Int("ply", g.TakebackPly),
+    )
+    return g, nil
+}
+
+// AcceptTakebackByRoom is AcceptTakebackInGame on the user's game in roomID, picked by ResolveGame (ErrAmbiguousGame when it cannot choose).
+func (m *Manager) AcceptTakebackByRoom(ctx context.Context, userID, roomID string) (*Game, int, error) {
+    g, err := m.resolveInRoom(ctx, userID, roomID)
+    if err != nil || g == nil { return nil, 0, err }
+    return m.AcceptTakebackInGame(ctx, userID, g.ID)
+}
+
+// AcceptTakebackInGame approves the opponent's takeback request and pops the requester's last move
+// (plus the reply played after it when it is already the requester's turn again).
+func (m *Manager) AcceptTakebackInGame(ctx context.Context, userID, gameID string) (*Game, int, error) {
+    userID = strings.TrimSpace(userID)
+    popped := 0
+    g, err := m.updateInGame(ctx, userID, gameID, func(cur *Game) error {
+        requester := strings.TrimSpace(cur.TakebackBy)
+        if requester == "" || cur.TakebackPly != len(cur.MovesUCI) { return ErrNoTakeback }
+        if requester == userID { return ErrOwnTakeback }
+        n := takebackPlies(cur, requester)
+        if n == 0 { return ErrNothingToUndo }
+        keep := len(cur.MovesUCI) - n
+        game := reconstruct(cur.FEN, cur.MovesUCI[:keep])
+        if game == nil { return fmt.Errorf("failed to reconstruct game") }
+        cur.MovesUCI = append([]string{}, cur.MovesUCI[:keep]...)
+        if len(cur.MovesSAN) >= keep {
+            cur.MovesSAN = append([]string{}, cur.MovesSAN[:keep]...)
+        }
+        cur.FEN = game.FEN()
+        cur.Turn = colorFrom(game.Position().Turn())
+        cur.TakebackBy = ""
+        cur.TakebackPly = 0
+        cur.DrawOfferBy = ""
+        cur.DrawOfferPly = 0
+        popped = n
+        return nil
+    })
+    if err != nil || g == nil { return g, 0, err }
+    obslog.L().Info("pvp_takeback_accept",
+        zap.String("game_id", g.ID),
+        zap.String("user_id", userID),
+        zap.Int("popped", popped),
+        zap.Int("plies", len(g.MovesUCI)),
+    )
+    return g, popped, nil
+}
+
+// DeclineTakebackByRoom is DeclineTakebackInGame on the user's game in roomID, picked by ResolveGame (ErrAmbiguousGame when it cannot choose).
+func (m *Manager) DeclineTakebackByRoom(ctx context.Context, userID, roomID string) (*Game, error) {
+    g, err := m.resolveInRoom(ctx, userID, roomID)
+    if err != nil || g == nil { return nil, err }
+    return m.DeclineTakebackInGame(ctx, userID, g.ID)
+}
+
+// DeclineTakebackInGame clears the opponent's pending takeback request.
+func (m *Manager) DeclineTakebackInGame(ctx context.Context, userID, gameID string) (*Game, error) {
+    userID = strings.TrimSpace(userID)
+    g, err := m.updateInGame(ctx, userID, gameID, func(cur *Game) error {
+        requester := strings.TrimSpace(cur.TakebackBy)
+        if requester == "" || cur.TakebackPly != len(cur.MovesUCI) { return ErrNoTakeback }
+        if requester == userID { return ErrOwnTakeback }
+        cur.TakebackBy = ""
+        cur.TakebackPly = 0
+        return nil
+    })
+    if err != nil || g == nil { return g, err }
+    obslog.L().Info("pvp_takeback_decline",
+        zap.String("game_id", g.ID),
+        zap.String("user_id", userID),
+    )
+    return g, nil
+}
+
+// takebackPlies returns how many plies a takeback by userID removes: 1 when the opponent is to move,
+// 2 when the opponent has already replied. 0 means the user has no move to take back.
+func takebackPlies(cur *Game, userID string) int {
+    color := Color(playerColorOf(cur, userID))
+    if color == "" { return 0 }
+    n := 1
+    if cur.Turn == color { n = 2 }
+    if len(cur.MovesUCI) < n { return 0 }
+    return n
+}
+
+// agreeDraw finishes cur as a draw by agreement and clears the offer.
+func agreeDraw(cur *Game) {
+    cur.Status = StatusDraw
+    cur.Outcome = "draw"
+    cur.Winner = ""
+    cur.DrawOfferBy = ""
+    cur.DrawOfferPly = 0
+}
+
+// checkDrawResponder verifies that a live offer exists and that it was made by the other player.
+func checkDrawResponder(cur *Game, userID string) error {
+    offer := strings.TrimSpace(cur.DrawOfferBy)
+    if offer == "" || cur.DrawOfferPly != len(cur.MovesUCI) { return ErrNoDrawOffer }
+    if offer == userID { return ErrOwnDrawOffer }
+    return nil
+}
+
+// resolveInRoom picks the user's game in roomID with ResolveGame (#N 없이).
+func (m *Manager) resolveInRoom(ctx context.Context, userID, roomID string) (*Game, error) {
     if strings.TrimSpace(userID) == "" || strings.TrimSpace(roomID) == "" {
-        return nil, "", fmt.Errorf("invalid parameters")
+        return nil, fmt.Errorf("invalid parameters")
     }
-    g, err := m.GetActiveGameByUserInRoom(ctx, userID, roomID)
-    if err != nil || g == nil { return nil, "", err }
+    return m.ResolveGame(ctx, strings.TrimSpace(userID), roomID, 0)
+}
+
+// updateInGame applies fn to the user's ACTIVE game gameID under WATCH and saves the result.
+// 게임이 없거나 종료됐거나 사용자가 참가자가 아니면 (nil, nil)을 반환하고, fn이 반환한 오류는 그대로 전달합니다.
+func (m *Manager) updateInGame(ctx context.Context, userID, gameID string, fn func(cur *Game) error) (*Game, error) {
+    if strings.TrimSpace(userID) == "" || strings.TrimSpace(gameID) == "" {
+        return nil, fmt.Errorf("invalid parameters")
+    }
+    g, err := m.get(ctx, gameID)
+    if err != nil || g == nil { return nil, err }
+    if g.Status != StatusActive || playerColorOf(g, strings.TrimSpace(userID)) == "" { return nil, nil }
     gameK := gameKey(g.ID)
     err = m.rdb.Watch(ctx, func(tx *redis.Tx) error {
         raw, err := tx.Get(ctx, gameK).Bytes()
@@ -439,39 +592,22 @@ func (m *Manager) ResignByRoom(ctx context.Context, userID, roomID string) (*Gam
         var cur Game
         if jerr := json.Unmarshal(raw, &cur); jerr != nil { return jerr }
         if cur.Status != StatusActive { return redis.TxFailedErr }
-        // ensure room scope still matches
-        if !(cur.OriginRoom == roomID || cur.ResolveRoom == roomID) {
-            return fmt.Errorf("game not in room")
-        }
-        cur.Status = StatusResigned
-        cur.Winner = opponentID(&cur, userID)
-        cur.Outcome = "resign"
+        if err := fn(&cur); err != nil { return err }
         cur.UpdatedAt = time.Now()
-        pipe := tx.TxPipeline()
-        newRaw, _ := json.Marshal(&cur)
-        pipe.Set(ctx, gameK, newRaw, 24*time.Hour)
-        if _, err := pipe.Exec(ctx); err != nil { return err }
+        if err := m.commitGame(ctx, tx, &cur, StatusActive); err != nil { return err }
         g = &cur
         return nil
     }, gameK)
     if err != nil {
         if errors.Is(err, redis.TxFailedErr) {
-            return nil, "", fmt.Errorf("game no longer active")
+            return nil, fmt.Errorf("game no longer active")
         }
-        return nil, "", err
+        return g, err
     }
-    obslog.L().Info("pvp_resign_by_room",
-        zap.String("game_id", g.ID),
-        zap.String("resigner", strings.TrimSpace(userID)),
-        zap.String("room_id", strings.TrimSpace(roomID)),
-        zap.String("winner", g.Winner),
-    )
-    _ = m.persistIfFinal(ctx, g, "resignation")
-    return g, "기권", nil
+    m.unindexCorrespondence(ctx, g)
+    return g, nil
 }
 
-// 수동 무승부(제안/수락) 기능 제거됨: 규칙상 자동 무승부만 허용
-
 // 중단(Abort) 기능 제거됨: 정책에 따라 지원하지 않음
 
 // Helpers
@@ -481,7 +617,9 @@ func opponentID(g *Game, userID string) string {
     return ""
 }
 
-func (m *Manager) playerColor(g *Game, userID string) string {
+func (m *Manager) playerColor(g *Game, userID string) string { return playerColorOf(g, userID) }
+
+func playerColorOf(g *Game, userID string) string {
     if g.WhiteID == userID { return "white" }
     if g.BlackID == userID { return "black" }
     return ""
@@ -507,15 +645,116 @@ func colorFrom(c nchess.Color) Color { if c == nchess.White { return White }; re
 
 // Persistence
 func (m *Manager) save(ctx context.Context, g *Game) error {
+    if err := m.persistCorrespondence(ctx, g); err != nil { return err }
+    pipe := m.rdb.TxPipeline()
+    if err := stageGame(ctx, pipe, g, ""); err != nil { return err }
+    if _, err := pipe.Exec(ctx); err != nil { return err }
+    m.unindexCorrespondence(ctx, g)
+    return nil
+}
+
+// commitGame writes cur inside a WATCH transaction (prev: 읽었을 때의 상태, stageGame 참고).
+// 통신 대국은 Postgres에 먼저 기록하고 실패하면 Redis에도 쓰지 않아, 재시작 후 복원되는 상태와
+// 어긋난 수가 받아들여지지 않게 합니다. 트랜잭션이 경합으로 무산되면 Postgres를 Redis 기준으로 되돌립니다.
+func (m *Manager) commitGame(ctx context.Context, tx *redis.Tx, cur *Game, prev Status) error {
+    if err := m.persistCorrespondence(ctx, cur); err != nil { return err }
+    pipe := tx.TxPipeline()
+    if err := stageGame(ctx, pipe, cur, prev); err != nil { return err }
+    _, err := pipe.Exec(ctx)
+    if errors.Is(err, redis.TxFailedErr) && cur.Correspondence { m.resyncCorrespondence(ctx, cur.ID) }
+    return err
+}
+
+// saveNew assigns the game's #N handle and stores it with the players' index entries in one transaction.
+// 두 플레이어의 인덱스를 WATCH하므로 같은 플레이어의 대국이 동시에 만들어져도 번호가 겹치지 않고
+// 1인당 상한(maxPerUser, 넘으면 ErrTooManyUserGames)도 넘지 않습니다(경합 시 다시 계산).
+// 인스턴스 상한(maxActive)은 먼저 reserveActive로 자리를 잡아 지키고, 저장에 실패하면 자리를 돌려줍니다.
+func (m *Manager) saveNew(ctx context.Context, g *Game) error {
+    if m.maxActive > 0 {
+        if err := m.reserveActive(ctx, g.ID); err != nil { return err }
+    }
+    err := m.persistCorrespondence(ctx, g)
+    if err == nil {
+        if err = m.insertGame(ctx, g); err != nil && g.Correspondence { _ = m.repo.DeleteActiveGame(ctx, g.ID) }
+    }
+    if err != nil && m.maxActive > 0 { _ = m.rdb.SRem(ctx, activeGamesKey, g.ID).Err() }
+    return err
+}
+
+// insertGame is the WATCH loop of saveNew.
+func (m *Manager) insertGame(ctx context.Context, g *Game) error {
+    keys := []string{idxUserKey(g.WhiteID), idxUserKey(g.BlackID)}
+    for attempt := 0; attempt < maxCreateAttempts; attempt++ {
+        err := m.rdb.Watch(ctx, func(tx *redis.Tx) error {
+            var playing []*Game
+            for _, uid := range []string{g.WhiteID, g.BlackID} {
+                games, err := m.ActiveGamesByUser(ctx, uid)
+                if err != nil { return err }
+                if m.maxPerUser > 0 && len(games) >= m.maxPerUser { return ErrTooManyUserGames }
+                playing = append(playing, games...)
+            }
+            g.Handle = freeHandle(playing)
+            _, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+                if err := stageGame(ctx, pipe, g, ""); err != nil { return err }
+                pipe.Del(ctx, reserveKey(g.ID))
+                // 인덱스 키 TTL도 갱신하여 누적 방지(게임 TTL과 동일)
+                for _, uid := range []string{g.WhiteID, g.BlackID} {
+                    pipe.SAdd(ctx, idxUserKey(uid), g.ID)
+                    pipe.Expire(ctx, idxUserKey(uid), 24*time.Hour)
+                }
+                return nil
+            })
+            return err
+        }, keys...)
+        if errors.Is(err, redis.TxFailedErr) { continue }
+        return err
+    }
+    return fmt.Errorf("create game: %w", redis.TxFailedErr)
+}
+
+// reserveActiveScript adds ARGV[2] to pvp:active unless the set already holds ARGV[1] games (1 = reserved).
+// KEYS[2]는 게임 키가 생기기 전까지 자리가 만료된 것으로 정리되지 않도록 잠시 남기는 표시입니다.
+var reserveActiveScript = redis.NewScript(`
+if redis.call('SISMEMBER', KEYS[1], ARGV[2]) == 1 then return 1 end
+if redis.call('SCARD', KEYS[1]) >= tonumber(ARGV[1]) then return 0 end
+redis.call('SADD', KEYS[1], ARGV[2])
+redis.call('SET', KEYS[2], '1', 'PX', ARGV[3])
+return 1
+`)
+
+// reserveActive atomically claims a pvp:active slot for a new game, or returns ErrTooManyGames.
+// 집합이 가득 차 있으면 키가 만료된(종료 기록 없이 사라진) 대국을 한 번 정리한 뒤 다시 시도합니다.
+func (m *Manager) reserveActive(ctx context.Context, id string) error {
+    for pruned := false; ; pruned = true {
+        ok, err := reserveActiveScript.Run(ctx, m.rdb, []string{activeGamesKey, reserveKey(id)}, m.maxActive, id, ttlReserve.Milliseconds()).Int()
+        if err != nil { return err }
+        if ok == 1 { return nil }
+        if pruned { return ErrTooManyGames }
+        _, stale, err := m.countActive(ctx)
+        if err != nil { return err }
+        if len(stale) == 0 { return ErrTooManyGames }
+        if err := m.rdb.SRem(ctx, activeGamesKey, stale...).Err(); err != nil { return err }
+    }
+}
+
+// stageGame queues the game write. pvp:active는 상태가 prev에서 바뀔 때만 고칩니다
+// (prev가 ""이면 새 대국/복원처럼 이전 상태를 모르는 경우라 항상 맞춤).
+func stageGame(ctx context.Context, pipe redis.Pipeliner, g *Game, prev Status) error {
     raw, err := json.Marshal(g)
     if err != nil { return err }
-    if err := m.rdb.Set(ctx, gameKey(g.ID), raw, 24*time.Hour).Err(); err != nil { return err }
+    pipe.Set(ctx, gameKey(g.ID), raw, gameTTL(g))
+    if g.Status == prev { return nil }
+    if g.Status == StatusActive {
+        pipe.SAdd(ctx, activeGamesKey, g.ID)
+    } else {
+        pipe.SRem(ctx, activeGamesKey, g.ID)
+    }
     return nil
 }
 
 func (m *Manager) get(ctx context.Context, id string) (*Game, error) {
     raw, err := m.rdb.Get(ctx, gameKey(id)).Bytes()
-    if err == redis.Nil { return nil, nil }
+    if err == redis.Nil { return m.loadCorrespondence(ctx, id) }
     if err != nil { return nil, err }
     var g Game
     if err := json.Unmarshal(raw, &g); err != nil { return nil, err }
@@ -527,23 +766,66 @@ func (m *Manager) LoadGame(ctx context.Context, id string) (*Game, error) {
     return m.get(ctx, id)
 }
 
-func (m *Manager) indexParticipants(ctx context.Context, id string, white, black string) error {
-    if strings.TrimSpace(white) != "" {
-        key := idxUserKey(white)
-        if err := m.rdb.SAdd(ctx, key, id).Err(); err != nil { return err }
-        // 인덱스 키 TTL도 갱신하여 누적 방지(게임 TTL과 동일)
-        _ = m.rdb.Expire(ctx, key, 24*time.Hour).Err()
-    }
-    if strings.TrimSpace(black) != "" {
-        key := idxUserKey(black)
-        if err := m.rdb.SAdd(ctx, key, id).Err(); err != nil { return err }
-        _ = m.rdb.Expire(ctx, key, 24*time.Hour).Err()
+// CountActiveGames returns the number of ACTIVE games (pvp:active 집합 크기).
+func (m *Manager) CountActiveGames(ctx context.Context) (int, error) {
+    if m == nil || m.rdb == nil { return 0, fmt.Errorf("pvp manager not initialized") }
+    n, stale, err := m.countActive(ctx)
+    if err != nil { return 0, err }
+    if len(stale) > 0 { _ = m.rdb.SRem(ctx, activeGamesKey, stale...).Err() }
+    return n, nil
+}
+
+// countActive returns SCARD pvp:active. 상한에 닿았을 때만 키가 만료된(종료 기록 없이 사라진)
+// 대국을 빼고 다시 세며, 그런 ID를 stale로 돌려주어 호출자가 집합에서 지우게 합니다.
+func (m *Manager) countActive(ctx context.Context) (int, []interface{}, error) {
+    n, err := m.rdb.SCard(ctx, activeGamesKey).Result()
+    if err != nil { return 0, nil, err }
+    if m.maxActive <= 0 || int(n) < m.maxActive { return int(n), nil, nil }
+    ids, err := m.rdb.SMembers(ctx, activeGamesKey).Result()
+    if err != nil { return 0, nil, err }
+    var stale []interface{}
+    for _, id := range ids {
+        ok, err := m.rdb.Exists(ctx, gameKey(id), reserveKey(id)).Result()
+        if err != nil { return 0, nil, err }
+        if ok == 0 { stale = append(stale, id) }
     }
-    return nil
+    return len(ids) - len(stale), stale, nil
 }
 
+// activeGamesKey holds the IDs of ACTIVE games; reserveActive/stageGame add them and stageGame
+// removes them when the status leaves ACTIVE.
+const activeGamesKey = "pvp:active"
+
+// maxCreateAttempts bounds insertGame retries when a watched index changes under it.
+const maxCreateAttempts = 20
+
+// ttlReserve bounds how long a reserved pvp:active slot survives without its game key.
+const ttlReserve = 30 * time.Second
+
 func gameKey(id string) string { return "pvp:game:" + strings.TrimSpace(id) }
+func reserveKey(id string) string { return "pvp:reserve:" + strings.TrimSpace(id) }
 func idxUserKey(userID string) string { return "pvp:index:user:" + strings.TrimSpace(userID) }
+func idxCorrKey(userID string) string { return "pvp:index:corr:" + strings.TrimSpace(userID) }
+
+// gameTTL is the Redis TTL of a game key: 진행 중인 통신 대국은 만료 없음(0), 그 외(종료 포함) 24시간.
+func gameTTL(g *Game) time.Duration {
+    if g != nil && g.Correspondence && g.Status == StatusActive { return 0 }
+    return 24 * time.Hour
+}
+
+// userGameIDs returns the user's game ids from the 24h index and the non-expiring correspondence index.
+func (m *Manager) userGameIDs(ctx context.Context, userID string) ([]string, error) {
+    ids, err := m.rdb.SMembers(ctx, idxUserKey(userID)).Result()
+    if err != nil { return nil, err }
+    corr, err := m.rdb.SMembers(ctx, idxCorrKey(userID)).Result()
+    if err != nil { return nil, err }
+    seen := make(map[string]bool, len(ids))
+    for _, id := range ids { seen[id] = true }
+    for _, id := range corr {
+        if !seen[id] { ids = append(ids, id) }
+    }
+    return ids, nil
+}
 
 func parseRedisURL(raw string) (*redis.Options, error) {
     u, err := url.Parse(raw)
@@ -578,12 +860,29 @@ func secureRandSuffix(n int) string {
     return fmt.Sprintf("%x", time.Now().UnixNano()%1_000_000)
 }
 
+// TopRatings returns the PvP rating ladder (room-scoped when room is non-empty).
+// 저장소가 연결되지 않은 경우 빈 목록을 반환합니다.
+func (m *Manager) TopRatings(ctx context.Context, room string, limit int) ([]RatingEntry, error) {
+    if m == nil || m.repo == nil { return nil, nil }
+    return m.repo.TopRatings(ctx, room, limit)
+}
+
+// Rating returns the player's PvP rating entry, or a DefaultRating entry when unrated or without a repository.
+func (m *Manager) Rating(ctx context.Context, userID string) (RatingEntry, error) {
+    if m == nil || m.repo == nil { return RatingEntry{UserID: strings.TrimSpace(userID), Rating: DefaultRating}, nil }
+    return m.repo.Rating(ctx, userID)
+}
+
 // persistIfFinal saves the final game result to repository if available.
 func (m *Manager) persistIfFinal(ctx context.Context, g *Game, method string) error {
-    if m == nil || m.repo == nil || g == nil {
+    if m == nil || g == nil {
+        return nil
+    }
+    if g.Status != StatusFinished && g.Status != StatusResigned && g.Status != StatusDraw && g.Status != StatusAborted {
         return nil
     }
-    if g.Status != StatusFinished && g.Status != StatusResigned && g.Status != StatusDraw {
+    defer m.fireResultHooks(ctx, g)
+    if m.repo == nil {
         return nil
     }
     if err := m.repo.SaveResult(ctx, g, method); err != nil {
@@ -591,7 +890,41 @@ func (m *Manager) persistIfFinal(ctx context.Context, g *Game, method string) er
         return err
     }
     obslog.L().Info("pvp_result_persist", zap.String("game_id", g.ID), zap.String("outcome", g.Outcome), zap.String("method", method))
+    if g.Status == StatusAborted { return nil }
+    white, black, applied, err := m.repo.ApplyRatings(ctx, g)
+    if err != nil {
+        obslog.L().Error("pvp_rating_apply_error", zap.String("game_id", g.ID), zap.Error(err))
+        return err
+    }
+    if !applied { return nil }
+    // 종료 안내에 변동폭을 표시할 수 있도록 게임 상태에 기록
+    g.WhiteRatingDelta = white.Delta
+    g.BlackRatingDelta = black.Delta
+    g.WhiteRating = white.Rating
+    g.BlackRating = black.Rating
+    _ = m.save(ctx, g)
+    obslog.L().Info("pvp_rating_apply",
+        zap.String("game_id", g.ID),
+        zap.Int("white_rating", g.WhiteRating),
+        zap.Int("white_delta", g.WhiteRatingDelta),
+        zap.Int("black_rating", g.BlackRating),
+        zap.Int("black_delta", g.BlackRatingDelta),
+    )
     return nil
 }
 
+// fireResultHooks runs OnResult callbacks, isolating panics so one hook cannot break move handling.
+func (m *Manager) fireResultHooks(ctx context.Context, g *Game) {
+    for _, fn := range m.resultHooks {
+        func() {
+            defer func() {
+                if r := recover(); r != nil {
+                    obslog.L().Error("pvp_result_hook_panic", zap.String("game_id", g.ID), zap.Any("panic", r))
+                }
+            }()
+            fn(ctx, g)
+        }()
+    }
+}
+
 // time control removed: parsing disabled
@@ -2,9 +2,13 @@ package pvpchess
 
 import (
     "context"
+    "database/sql"
+    "errors"
     "fmt"
     "strings"
+    "sync"
     "testing"
+    "time"
 
     miniredis "github.com/alicebob/miniredis/v2"
     "github.com/redis/go-redis/v9"
@@ -34,31 +38,43 @@ func TestPlayMove_UCI_SAN_Illegal(t *testing.T) {
 	}
 
 	// UCI move by white
-	g1, txt, err := m.PlayMove(ctx, g.WhiteID, "e2e4")
+	g1, txt, err := m.PlayMoveInGame(ctx, g.WhiteID, g.ID, "e2e4")
 	if err != nil || g1 == nil {
-		t.Fatalf("PlayMove UCI: %v", err)
+		t.Fatalf("PlayMoveInGame UCI: %v", err)
 	}
 	if txt == "" || len(g1.MovesUCI) != 1 {
 		t.Fatalf("unexpected UCI result: txt=%q len=%d", txt, len(g1.MovesUCI))
 	}
 
 	// SAN move by black (e.g., Nc6)
-	g2, txt2, err := m.PlayMove(ctx, g.BlackID, "Nc6")
+	g2, txt2, err := m.PlayMoveInGame(ctx, g.BlackID, g.ID, "Nc6")
 	if err != nil || g2 == nil {
-		t.Fatalf("PlayMove SAN: %v", err)
+		t.Fatalf("PlayMoveInGame SAN: %v", err)
 	}
 	if txt2 == "" || len(g2.MovesSAN) != 2 {
 		t.Fatalf("unexpected SAN result: txt=%q lenSAN=%d", txt2, len(g2.MovesSAN))
 	}
 
 	// Illegal move should return an explanatory message
-	_, txt3, err := m.PlayMove(ctx, g.WhiteID, "invalid")
+	_, txt3, err := m.PlayMoveInGame(ctx, g.WhiteID, g.ID, "invalid")
 	if err != nil {
 		t.Fatalf("PlayMove illegal returned error: %v", err)
 	}
 	if txt3 == "" {
 		t.Fatalf("expected user-facing error message for illegal move")
 	}
+
+	// Well-formed UCI that is not legal here must not be applied
+	g4, txt4, err := m.PlayMoveInGame(ctx, g.WhiteID, g.ID, "a1a5")
+	if err != nil {
+		t.Fatalf("PlayMove illegal UCI returned error: %v", err)
+	}
+	if txt4 == "" || (g4 != nil && len(g4.MovesUCI) != 2) {
+		t.Fatalf("illegal UCI applied: txt=%q", txt4)
+	}
+	if cur, _ := m.LoadGame(ctx, g.ID); cur == nil || len(cur.MovesUCI) != 2 {
+		t.Fatalf("illegal UCI changed stored game")
+	}
 }
 
 func TestIndexTTLOnCreate(t *testing.T) {
@@ -127,3 +143,455 @@ func TestPlayMoveByRoom_NotYourTurn(t *testing.T) {
     if strings.TrimSpace(txt) == "" { t.Fatalf("expected not-your-turn message") }
     if len(gg.MovesUCI) != 0 { t.Fatalf("move should not be applied") }
 }
+
+func TestDrawOffer_AcceptDeclineExpire(t *testing.T) {
+    m := newTestManager(t)
+    ctx := context.Background()
+    g, err := m.CreateGameFromChallenge(ctx, "r1", "r2", "w", "W", "b", "B", "white", "none")
+    if err != nil { t.Fatalf("create: %v", err) }
+
+    // 응답할 제안이 없음
+    if _, err := m.AcceptDrawByRoom(ctx, "b", "r2"); !errors.Is(err, ErrNoDrawOffer) {
+        t.Fatalf("expected ErrNoDrawOffer, got %v", err)
+    }
+    // 제안 후 거절
+    if _, err := m.OfferDrawByRoom(ctx, "w", "r1"); err != nil { t.Fatalf("offer: %v", err) }
+    if _, err := m.OfferDrawByRoom(ctx, "w", "r1"); !errors.Is(err, ErrDrawOfferPending) {
+        t.Fatalf("expected ErrDrawOfferPending, got %v", err)
+    }
+    if _, err := m.AcceptDrawByRoom(ctx, "w", "r1"); !errors.Is(err, ErrOwnDrawOffer) {
+        t.Fatalf("expected ErrOwnDrawOffer, got %v", err)
+    }
+    gd, err := m.DeclineDrawByRoom(ctx, "b", "r2")
+    if err != nil || gd == nil || gd.DrawOfferBy != "" { t.Fatalf("decline: g=%v err=%v", gd, err) }
+
+    // 제안 후 수가 두어지면 만료
+    if _, err := m.OfferDrawByRoom(ctx, "b", "r2"); err != nil { t.Fatalf("offer2: %v", err) }
+    gm, _, err := m.PlayMoveByRoom(ctx, "w", "r1", "e2e4")
+    if err != nil || gm == nil || gm.DrawOfferBy != "" { t.Fatalf("offer should expire on move: g=%v err=%v", gm, err) }
+    if _, err := m.AcceptDrawByRoom(ctx, "w", "r1"); !errors.Is(err, ErrNoDrawOffer) {
+        t.Fatalf("expected ErrNoDrawOffer after move, got %v", err)
+    }
+
+    // 제안 후 수락 → 무승부 종료
+    if _, err := m.OfferDrawByRoom(ctx, "b", "r2"); err != nil { t.Fatalf("offer3: %v", err) }
+    ga, err := m.AcceptDrawByRoom(ctx, "w", "r1")
+    if err != nil || ga == nil { t.Fatalf("accept: %v", err) }
+    if ga.Status != StatusDraw || ga.Outcome != "draw" { t.Fatalf("unexpected final state: %s/%s", ga.Status, ga.Outcome) }
+    if cur, _ := m.LoadGame(ctx, g.ID); cur == nil || cur.Status != StatusDraw { t.Fatalf("draw not persisted to redis") }
+}
+
+func TestOfferDraw_CrossOfferAgrees(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+	g, err := m.CreateGameFromChallenge(ctx, "r1", "r2", "w", "W", "b", "B", "white", "none")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := m.OfferDrawInGame(ctx, "w", g.ID); err != nil {
+		t.Fatalf("offer: %v", err)
+	}
+	// 상대 제안이 걸린 상태의 맞제안은 수락과 같음
+	gd, err := m.OfferDrawInGame(ctx, "b", g.ID)
+	if err != nil || gd == nil || gd.Status != StatusDraw || gd.Outcome != "draw" || gd.DrawOfferBy != "" {
+		t.Fatalf("cross offer: %+v %v", gd, err)
+	}
+	if cur, _ := m.LoadGame(ctx, g.ID); cur == nil || cur.Status != StatusDraw {
+		t.Fatalf("draw not persisted to redis")
+	}
+}
+
+func TestSweepAbandoned_ForfeitAndAbort(t *testing.T) {
+    m := newTestManager(t)
+    ctx := context.Background()
+
+    // 2수 이상 진행 후 방치: 차례인 쪽(백) 패배
+    g1, err := m.CreateGameFromChallenge(ctx, "r1", "r2", "w", "W", "b", "B", "white", "none")
+    if err != nil { t.Fatalf("create g1: %v", err) }
+    if _, _, err := m.PlayMoveByRoom(ctx, "w", "r1", "e2e4"); err != nil { t.Fatalf("move1: %v", err) }
+    if _, _, err := m.PlayMoveByRoom(ctx, "b", "r2", "e7e5"); err != nil { t.Fatalf("move2: %v", err) }
+    // 수가 없는 방치 대국: 중단
+    g2, err := m.CreateGameFromChallenge(ctx, "r3", "r4", "x", "X", "y", "Y", "white", "none")
+    if err != nil { t.Fatalf("create g2: %v", err) }
+    // 최근 대국: 유지
+    g3, err := m.CreateGameFromChallenge(ctx, "r5", "r6", "p", "P", "q", "Q", "white", "none")
+    if err != nil { t.Fatalf("create g3: %v", err) }
+
+    for _, id := range []string{g1.ID, g2.ID} {
+        cur, _ := m.get(ctx, id)
+        cur.UpdatedAt = time.Now().Add(-2 * time.Hour)
+        if err := m.save(ctx, cur); err != nil { t.Fatalf("save: %v", err) }
+    }
+
+    done, err := m.SweepAbandoned(ctx, time.Hour)
+    if err != nil { t.Fatalf("sweep: %v", err) }
+    if len(done) != 2 { t.Fatalf("expected 2 finalized games, got %d", len(done)) }
+
+    f1, _ := m.get(ctx, g1.ID)
+    if f1.Status != StatusFinished || f1.Winner != "b" || f1.Outcome != "abandon" {
+        t.Fatalf("unexpected forfeit state: %s winner=%s outcome=%s", f1.Status, f1.Winner, f1.Outcome)
+    }
+    f2, _ := m.get(ctx, g2.ID)
+    if f2.Status != StatusAborted { t.Fatalf("expected aborted, got %s", f2.Status) }
+    f3, _ := m.get(ctx, g3.ID)
+    if f3.Status != StatusActive { t.Fatalf("recent game should stay active, got %s", f3.Status) }
+    // 종료 후 같은 방에서 새 대국을 막지 않아야 함
+    if busy, _ := m.GetActiveGameByUserInRoom(ctx, "b", "r2"); busy != nil { t.Fatalf("player still blocked by abandoned game") }
+}
+
+func TestTakeback_PopsOneOrTwoPlies(t *testing.T) {
+    m := newTestManager(t)
+    ctx := context.Background()
+    if _, err := m.CreateGameFromChallenge(ctx, "r1", "r2", "w", "W", "b", "B", "white", "none"); err != nil {
+        t.Fatalf("create: %v", err)
+    }
+    // 둔 수가 없으면 요청 불가
+    if _, err := m.RequestTakebackByRoom(ctx, "w", "r1"); !errors.Is(err, ErrNothingToUndo) {
+        t.Fatalf("expected ErrNothingToUndo, got %v", err)
+    }
+    if _, _, err := m.PlayMoveByRoom(ctx, "w", "r1", "e2e4"); err != nil { t.Fatalf("move: %v", err) }
+
+    // 상대 차례에서 요청 → 1수 되돌림
+    if _, err := m.RequestTakebackByRoom(ctx, "w", "r1"); err != nil { t.Fatalf("request: %v", err) }
+    if _, _, err := m.AcceptTakebackByRoom(ctx, "w", "r1"); !errors.Is(err, ErrOwnTakeback) {
+        t.Fatalf("expected ErrOwnTakeback, got %v", err)
+    }
+    g, n, err := m.AcceptTakebackByRoom(ctx, "b", "r2")
+    if err != nil || g == nil { t.Fatalf("accept: %v", err) }
+    if n != 1 || len(g.MovesUCI) != 0 || len(g.MovesSAN) != 0 || g.Turn != White {
+        t.Fatalf("unexpected state after 1-ply takeback: n=%d moves=%v turn=%s", n, g.MovesUCI, g.Turn)
+    }
+
+    // 상대가 이미 응수한 뒤 요청 → 2수 되돌림
+    if _, _, err := m.PlayMoveByRoom(ctx, "w", "r1", "d2d4"); err != nil { t.Fatalf("move2: %v", err) }
+    if _, _, err := m.PlayMoveByRoom(ctx, "b", "r2", "d7d5"); err != nil { t.Fatalf("move3: %v", err) }
+    if _, err := m.RequestTakebackByRoom(ctx, "w", "r1"); err != nil { t.Fatalf("request2: %v", err) }
+    if _, err := m.DeclineTakebackByRoom(ctx, "b", "r2"); err != nil { t.Fatalf("decline: %v", err) }
+    if _, _, err := m.AcceptTakebackByRoom(ctx, "b", "r2"); !errors.Is(err, ErrNoTakeback) {
+        t.Fatalf("expected ErrNoTakeback after decline, got %v", err)
+    }
+    if _, err := m.RequestTakebackByRoom(ctx, "w", "r1"); err != nil { t.Fatalf("request3: %v", err) }
+    g, n, err = m.AcceptTakebackByRoom(ctx, "b", "r2")
+    if err != nil || g == nil { t.Fatalf("accept2: %v", err) }
+    if n != 2 || len(g.MovesUCI) != 0 || g.Turn != White || g.FEN != reconstruct("", nil).FEN() {
+        t.Fatalf("unexpected state after 2-ply takeback: n=%d moves=%v turn=%s fen=%s", n, g.MovesUCI, g.Turn, g.FEN)
+    }
+}
+
+func TestCreateGame_RespectsActiveGameCap(t *testing.T) {
+    m := newTestManager(t)
+    ctx := context.Background()
+    m.SetMaxActiveGames(1)
+    if _, err := m.CreateGameFromChallenge(ctx, "r1", "r2", "a", "A", "b", "B", "white", "none"); err != nil {
+        t.Fatalf("create: %v", err)
+    }
+    if _, err := m.CreateGameFromChallenge(ctx, "r3", "r4", "c", "C", "d", "D", "white", "none"); !errors.Is(err, ErrTooManyGames) {
+        t.Fatalf("expected ErrTooManyGames, got %v", err)
+    }
+    // 종료된 대국은 상한에 포함되지 않음
+    if _, _, err := m.ResignByRoom(ctx, "a", "r1"); err != nil { t.Fatalf("resign: %v", err) }
+    if _, err := m.CreateGameFromChallenge(ctx, "r3", "r4", "c", "C", "d", "D", "white", "none"); err != nil {
+        t.Fatalf("create after finish: %v", err)
+    }
+}
+
+func TestCreateGame_ActiveGameCapUnderConcurrency(t *testing.T) {
+    m := newTestManager(t)
+    ctx := context.Background()
+    m.SetMaxActiveGames(3)
+    var (
+        wg      sync.WaitGroup
+        mu      sync.Mutex
+        created int
+    )
+    for i := 0; i < 8; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            a, b := fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)
+            if _, err := m.CreateGameFromChallenge(ctx, "r1", "r2", a, a, b, b, "white", "none"); err == nil {
+                mu.Lock()
+                created++
+                mu.Unlock()
+            } else if !errors.Is(err, ErrTooManyGames) {
+                t.Errorf("create %d: %v", i, err)
+            }
+        }(i)
+    }
+    wg.Wait()
+    if n, _ := m.CountActiveGames(ctx); created != 3 || n != 3 {
+        t.Fatalf("created=%d active=%d, want 3", created, n)
+    }
+}
+
+func TestCreateGame_CapIgnoresExpiredGames(t *testing.T) {
+    m := newTestManager(t)
+    ctx := context.Background()
+    m.SetMaxActiveGames(1)
+    g, err := m.CreateGameFromChallenge(ctx, "r1", "r2", "a", "A", "b", "B", "white", "none")
+    if err != nil { t.Fatalf("create: %v", err) }
+    // 종료 기록 없이 키만 만료된 대국은 상한 확인 시 집합에서 정리됨
+    if err := m.rdb.Del(ctx, gameKey(g.ID)).Err(); err != nil { t.Fatalf("del: %v", err) }
+    if _, err := m.CreateGameFromChallenge(ctx, "r3", "r4", "c", "C", "d", "D", "white", "none"); err != nil {
+        t.Fatalf("create after expiry: %v", err)
+    }
+    if ids, _ := m.rdb.SMembers(ctx, activeGamesKey).Result(); len(ids) != 1 || ids[0] == g.ID {
+        t.Fatalf("active set = %v", ids)
+    }
+}
+
+func TestMovesLeaveActiveSetUntouched(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+	m.SetMaxActiveGames(10)
+	g, err := m.CreateGameFromChallenge(ctx, "r1", "r2", "w", "W", "b", "B", "white", "none")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// 수는 pvp:active를 건드리지 않으므로 그 집합을 WATCH한 트랜잭션(대국 생성 등)과 경합하지 않음
+	err = m.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		if _, _, err := m.PlayMoveInGame(ctx, "w", g.ID, "e2e4"); err != nil {
+			return err
+		}
+		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SCard(ctx, activeGamesKey)
+			return nil
+		})
+		return err
+	}, activeGamesKey)
+	if err != nil {
+		t.Fatalf("move touched %s: %v", activeGamesKey, err)
+	}
+	if _, _, err := m.ResignInGame(ctx, "b", g.ID); err != nil {
+		t.Fatalf("resign: %v", err)
+	}
+	if ok, _ := m.rdb.SIsMember(ctx, activeGamesKey, g.ID).Result(); ok {
+		t.Fatal("finished game still in active set")
+	}
+}
+
+func TestOnResultFiresOnFinalStateWithoutRepository(t *testing.T) {
+    m := newTestManager(t)
+    ctx := context.Background()
+    var got []*Game
+    m.OnResult(func(_ context.Context, g *Game) { got = append(got, g) })
+    m.OnResult(func(context.Context, *Game) { panic("boom") })
+
+    g, err := m.CreateGameFromChallenge(ctx, "r1", "r2", "w", "W", "b", "B", "white", "none")
+    if err != nil { t.Fatalf("create: %v", err) }
+    if _, _, err := m.PlayMoveByRoom(ctx, "w", "r1", "e2e4"); err != nil { t.Fatalf("move: %v", err) }
+    if len(got) != 0 { t.Fatalf("hook fired before game ended") }
+    if _, _, err := m.ResignByRoom(ctx, "b", "r2"); err != nil { t.Fatalf("resign: %v", err) }
+    if len(got) != 1 || got[0].ID != g.ID || got[0].Winner != "w" { t.Fatalf("unexpected hook calls: %+v", got) }
+}
+
+func TestCorrespondenceGame_NoTTLAndSweepIdle(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+
+	g, err := m.CreateGameFromChallenge(ctx, "r1", "r2", "w", "W", "b", "B", "white", TimeControlCorrespondence)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !g.Correspondence || g.WhiteRoom != "r1" || g.BlackRoom != "r2" || g.RoomOf("b") != "r2" {
+		t.Fatalf("unexpected correspondence fields: corr=%v white=%q black=%q", g.Correspondence, g.WhiteRoom, g.BlackRoom)
+	}
+	if ttl, _ := m.rdb.TTL(ctx, gameKey(g.ID)).Result(); ttl != -1 {
+		t.Fatalf("active correspondence game should not expire, ttl=%v", ttl)
+	}
+	list, err := m.ActiveGamesByUser(ctx, "b")
+	if err != nil || len(list) != 1 || list[0].ID != g.ID {
+		t.Fatalf("CorrespondenceGamesByUser: %v %+v", err, list)
+	}
+
+	cur, _ := m.get(ctx, g.ID)
+	cur.UpdatedAt = time.Now().Add(-48 * time.Hour)
+	if err := m.save(ctx, cur); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// 통신 대국 기준이 0이면 일반 방치 기준과 무관하게 유지
+	if done, err := m.SweepAbandoned(ctx, time.Hour); err != nil || len(done) != 0 {
+		t.Fatalf("correspondence game swept with corrIdle=0: %v %d", err, len(done))
+	}
+	m.SetCorrespondenceIdle(24 * time.Hour)
+	done, err := m.SweepAbandoned(ctx, time.Hour)
+	if err != nil || len(done) != 1 || done[0].Status != StatusAborted {
+		t.Fatalf("expected idle correspondence game to be aborted: %v %+v", err, done)
+	}
+	if ttl, _ := m.rdb.TTL(ctx, gameKey(g.ID)).Result(); ttl <= 0 {
+		t.Fatalf("finished correspondence game should expire, ttl=%v", ttl)
+	}
+	if list, _ := m.ActiveGamesByUser(ctx, "w"); len(list) != 0 {
+		t.Fatalf("finished game still listed: %+v", list)
+	}
+}
+
+func TestCorrespondenceMove_RejectedWhenPostgresWriteFails(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+	g, err := m.CreateGameFromChallenge(ctx, "r1", "r2", "w", "W", "b", "B", "white", TimeControlCorrespondence)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	db, err := sql.Open("postgres", "postgres://127.0.0.1:1/none?sslmode=disable")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	db.Close()
+	m.AttachRepository(&Repository{db: db})
+
+	// Postgres 기록이 실패하면 Redis에도 반영하지 않고 오류를 돌려줌
+	if _, _, err := m.PlayMoveInGame(ctx, "w", g.ID, "e2e4"); err == nil {
+		t.Fatal("move accepted without a Postgres write")
+	}
+	if cur, _ := m.cached(ctx, g.ID); cur == nil || len(cur.MovesUCI) != 0 {
+		t.Fatalf("redis changed after failed Postgres write: %+v", cur)
+	}
+}
+
+func TestCreateGame_ConcurrentHandlesAreUnique(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+	const n = 6
+	handles := make([]int, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			opp := fmt.Sprintf("o%d", i)
+			g, err := m.CreateGameFromChallenge(ctx, "r1", "r1", "u", "U", opp, opp, "white", "none")
+			if err != nil {
+				t.Errorf("create %d: %v", i, err)
+				return
+			}
+			handles[i] = g.Handle
+		}(i)
+	}
+	wg.Wait()
+	seen := map[int]bool{}
+	for _, h := range handles {
+		if h <= 0 || seen[h] {
+			t.Fatalf("handles must be unique and positive: %v", handles)
+		}
+		seen[h] = true
+	}
+}
+
+func TestCreateGame_PerUserLimitUnderConcurrency(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+	m.SetMaxGamesPerUser(2)
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+	)
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			opp := fmt.Sprintf("o%d", i)
+			_, err := m.CreateGameFromChallenge(ctx, "r1", "r1", "u", "U", opp, opp, "white", "none")
+			switch {
+			case err == nil:
+				mu.Lock()
+				created++
+				mu.Unlock()
+			case !errors.Is(err, ErrTooManyUserGames):
+				t.Errorf("create %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if games, _ := m.ActiveGamesByUser(ctx, "u"); created != 2 || len(games) != 2 {
+		t.Fatalf("created=%d active=%d, want 2", created, len(games))
+	}
+}
+
+func TestMultiGame_HandlesAndSelection(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+
+	g1, err := m.CreateGameFromChallenge(ctx, "r1", "r1", "u", "U", "a", "A", "white", "")
+	if err != nil {
+		t.Fatalf("create g1: %v", err)
+	}
+	g2, err := m.CreateGameFromChallenge(ctx, "r1", "r1", "u", "U", "b", "B", "black", "")
+	if err != nil {
+		t.Fatalf("create g2: %v", err)
+	}
+	// a의 첫 대국이지만 u의 #1과 겹치지 않도록 #3
+	g3, err := m.CreateGameFromChallenge(ctx, "r2", "r2", "a", "A", "u", "U", "white", "")
+	if err != nil {
+		t.Fatalf("create g3: %v", err)
+	}
+	if g1.Handle != 1 || g2.Handle != 2 || g3.Handle != 3 {
+		t.Fatalf("unexpected handles: %d %d %d", g1.Handle, g2.Handle, g3.Handle)
+	}
+	if list, _ := m.ActiveGamesByUser(ctx, "u"); len(list) != 3 || list[0].ID != g1.ID || list[2].ID != g3.ID {
+		t.Fatalf("ActiveGamesByUser: %+v", list)
+	}
+
+	// r1에서 u 차례인 대국은 g1(백)뿐 → 자동 선택
+	if g, err := m.ResolveGame(ctx, "u", "r1", 0); err != nil || g.ID != g1.ID {
+		t.Fatalf("expected g1 by turn, got %+v %v", g, err)
+	}
+	if _, _, err := m.PlayMoveInGame(ctx, "u", g1.ID, "e2e4"); err != nil {
+		t.Fatalf("move g1: %v", err)
+	}
+	if _, _, err := m.PlayMoveInGame(ctx, "b", g2.ID, "e2e4"); err != nil {
+		t.Fatalf("move g2: %v", err)
+	}
+	if _, _, err := m.PlayMoveInGame(ctx, "a", g1.ID, "e7e5"); err != nil {
+		t.Fatalf("reply g1: %v", err)
+	}
+	// 두 대국 모두 u 차례 → 지정 필요
+	if _, err := m.ResolveGame(ctx, "u", "r1", 0); !errors.Is(err, ErrAmbiguousGame) {
+		t.Fatalf("expected ErrAmbiguousGame, got %v", err)
+	}
+	// 방 단위 명령도 같은 규칙: 임의의 대국을 고르지 않고 ErrAmbiguousGame
+	if _, _, err := m.ResignByRoom(ctx, "u", "r1"); !errors.Is(err, ErrAmbiguousGame) {
+		t.Fatalf("expected ErrAmbiguousGame on resign, got %v", err)
+	}
+	if _, err := m.OfferDrawByRoom(ctx, "u", "r1"); !errors.Is(err, ErrAmbiguousGame) {
+		t.Fatalf("expected ErrAmbiguousGame on draw offer, got %v", err)
+	}
+	if _, err := m.SetDefaultGame(ctx, "u", "r1", 3); !errors.Is(err, ErrGameNotInRoom) {
+		t.Fatalf("expected ErrGameNotInRoom, got %v", err)
+	}
+	if _, err := m.SetDefaultGame(ctx, "u", "r1", 2); err != nil {
+		t.Fatalf("SetDefaultGame: %v", err)
+	}
+	if g, err := m.ResolveGame(ctx, "u", "r1", 0); err != nil || g.ID != g2.ID {
+		t.Fatalf("expected default g2, got %+v %v", g, err)
+	}
+	if g, _ := m.GetActiveGameByUserInRoom(ctx, "u", "r1"); g == nil || g.ID != g2.ID {
+		t.Fatalf("room-scoped commands should follow the default game")
+	}
+	if g, err := m.OfferDrawByRoom(ctx, "u", "r1"); err != nil || g.ID != g2.ID {
+		t.Fatalf("draw offer should follow the default game, got %+v %v", g, err)
+	}
+	// 다른 방 대국도 ID로 직접 처리, 참가자가 아니면 대상 없음
+	if g, err := m.OfferDrawInGame(ctx, "u", g3.ID); err != nil || g == nil || g.DrawOfferBy != "u" {
+		t.Fatalf("OfferDrawInGame: %+v %v", g, err)
+	}
+	if g, err := m.OfferDrawInGame(ctx, "b", g3.ID); err != nil || g != nil {
+		t.Fatalf("non-player must not touch the game: %+v %v", g, err)
+	}
+	// #3은 다른 방 대국이어도 직접 지정 가능
+	if g, err := m.ResolveGame(ctx, "u", "r1", 3); err != nil || g.ID != g3.ID {
+		t.Fatalf("expected #3, got %+v %v", g, err)
+	}
+	if _, err := m.ResolveGame(ctx, "u", "r1", 9); !errors.Is(err, ErrNoSuchGame) {
+		t.Fatalf("expected ErrNoSuchGame, got %v", err)
+	}
+
+	m.SetMaxGamesPerUser(3)
+	if err := m.CanStartGame(ctx, "u"); !errors.Is(err, ErrTooManyUserGames) {
+		t.Fatalf("expected ErrTooManyUserGames, got %v", err)
+	}
+	if err := m.CanStartGame(ctx, "b"); err != nil {
+		t.Fatalf("b should be able to start a game: %v", err)
+	}
+}
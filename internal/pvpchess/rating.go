@@ -0,0 +1,49 @@
+package pvpchess
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	// DefaultRating is the starting PvP rating for a player without rated games.
+	DefaultRating = 1200
+	// ratingKFactor matches the single-player ladder so both scales move at the same pace.
+	ratingKFactor = 24
+)
+
+// RatingEntry is a row of the PvP rating ladder.
+type RatingEntry struct {
+	UserID string
+	Name   string
+	Rating int
+	Games  int
+	Wins   int
+	Losses int
+	Draws  int
+	// Delta is the change applied by the latest ApplyRatings call.
+	Delta int
+}
+
+// eloUpdate returns the new white/black ratings given white's score (1, 0.5, 0).
+func eloUpdate(white, black int, whiteScore float64) (int, int) {
+	expWhite := 1 / (1 + math.Pow(10, float64(black-white)/400))
+	delta := ratingKFactor * (whiteScore - expWhite)
+	newWhite := int(math.Round(float64(white) + delta))
+	newBlack := int(math.Round(float64(black) - delta))
+	return newWhite, newBlack
+}
+
+// whiteScore maps a final result token to white's score; ok is false for unrated results.
+func whiteScore(result string) (float64, bool) {
+	switch strings.ToLower(strings.TrimSpace(result)) {
+	case "white":
+		return 1, true
+	case "black":
+		return 0, true
+	case "draw":
+		return 0.5, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,33 @@
+package pvpchess
+
+import "testing"
+
+func TestEloUpdate_ZeroSumAndDirection(t *testing.T) {
+	w, b := eloUpdate(1200, 1200, 1)
+	if w != 1212 || b != 1188 {
+		t.Fatalf("equal ratings win: got w=%d b=%d", w, b)
+	}
+	w, b = eloUpdate(1200, 1200, 0.5)
+	if w != 1200 || b != 1200 {
+		t.Fatalf("equal ratings draw should not move: got w=%d b=%d", w, b)
+	}
+	// 약자가 비기면 상승
+	w, b = eloUpdate(1000, 1400, 0.5)
+	if w <= 1000 || b >= 1400 || (w-1000) != (1400-b) {
+		t.Fatalf("underdog draw: got w=%d b=%d", w, b)
+	}
+}
+
+func TestResultToken_MapsResignAndAbandon(t *testing.T) {
+	g := &Game{WhiteID: "w", BlackID: "b", Outcome: "abandon", Winner: "b"}
+	if r := resultToken(g); r != "black" {
+		t.Fatalf("abandon → %q", r)
+	}
+	g.Outcome, g.Winner = "resign", "w"
+	if r := resultToken(g); r != "white" {
+		t.Fatalf("resign → %q", r)
+	}
+	if _, ok := whiteScore(resultToken(&Game{Outcome: "aborted"})); ok {
+		t.Fatalf("aborted games must be unrated")
+	}
+}
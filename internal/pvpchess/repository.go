@@ -48,17 +48,7 @@ func (r *Repository) SaveResult(ctx context.Context, g *Game, method string) err
 	}
 
 	// derive result token and PGN result string
-	result := strings.TrimSpace(g.Outcome)
-	if result == "resign" {
-		// map resign outcome to winner color if possible
-		if g.Winner == g.WhiteID {
-			result = "white"
-		} else if g.Winner == g.BlackID {
-			result = "black"
-		} else {
-			result = ""
-		}
-	}
+	result := resultToken(g)
 	pgnResult := mapResultToPGN(result)
 
 	// build PGN text from SAN list
@@ -105,6 +95,182 @@ func (r *Repository) SaveResult(ctx context.Context, g *Game, method string) err
 	return err
 }
 
+// ApplyRatings updates both players' PvP Elo ratings for a finished game exactly once.
+// pvp_games.rated 플래그로 중복 반영을 막으며, SaveResult 이후에 호출해야 합니다.
+// 반영 대상이 아니거나 이미 반영된 경우 applied=false를 반환합니다.
+func (r *Repository) ApplyRatings(ctx context.Context, g *Game) (white, black RatingEntry, applied bool, err error) {
+	if r == nil || r.db == nil || g == nil {
+		return
+	}
+	score, ok := whiteScore(resultToken(g))
+	if !ok {
+		return
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil || !applied {
+			_ = tx.Rollback()
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, `UPDATE pvp_games SET rated = TRUE WHERE game_id = $1 AND rated = FALSE`, g.ID)
+	if err != nil {
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return
+	}
+
+	white, black, err = lockRatingPair(ctx, tx, g)
+	if err != nil {
+		return
+	}
+	prevWhite, prevBlack := white.Rating, black.Rating
+	white.Rating, black.Rating = eloUpdate(prevWhite, prevBlack, score)
+	white.Delta, black.Delta = white.Rating-prevWhite, black.Rating-prevBlack
+	white.Games++
+	black.Games++
+	switch score {
+	case 1:
+		white.Wins++
+		black.Losses++
+	case 0:
+		white.Losses++
+		black.Wins++
+	default:
+		white.Draws++
+		black.Draws++
+	}
+	for _, e := range []RatingEntry{white, black} {
+		if err = saveRating(ctx, tx, e); err != nil {
+			return
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return
+	}
+	applied = true
+	return
+}
+
+// TopRatings returns the highest rated PvP players. room가 비어 있지 않으면 해당 방에서 대국한 플레이어로 제한합니다.
+func (r *Repository) TopRatings(ctx context.Context, room string, limit int) ([]RatingEntry, error) {
+	if r == nil || r.db == nil {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	room = strings.TrimSpace(room)
+	q := `SELECT user_id, name, rating, games, wins, losses, draws FROM pvp_ratings`
+	args := []any{limit}
+	if room != "" {
+		q += ` WHERE user_id IN (
+            SELECT white_id FROM pvp_games WHERE origin_room = $2 OR resolve_room = $2
+            UNION
+            SELECT black_id FROM pvp_games WHERE origin_room = $2 OR resolve_room = $2
+          )`
+		args = append(args, room)
+	}
+	q += ` ORDER BY rating DESC, games DESC, user_id LIMIT $1`
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []RatingEntry
+	for rows.Next() {
+		var e RatingEntry
+		if err := rows.Scan(&e.UserID, &e.Name, &e.Rating, &e.Games, &e.Wins, &e.Losses, &e.Draws); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// Rating returns a single player's rating row; players without rated games get DefaultRating.
+func (r *Repository) Rating(ctx context.Context, userID string) (RatingEntry, error) {
+	e := RatingEntry{UserID: strings.TrimSpace(userID), Rating: DefaultRating}
+	if r == nil || r.db == nil {
+		return e, nil
+	}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT name, rating, games, wins, losses, draws FROM pvp_ratings WHERE user_id = $1`,
+		e.UserID,
+	).Scan(&e.Name, &e.Rating, &e.Games, &e.Wins, &e.Losses, &e.Draws)
+	if err == sql.ErrNoRows {
+		return e, nil
+	}
+	return e, err
+}
+
+// lockRatingPair locks both players' rating rows and returns them. 아직 행이 없는 플레이어는 기본 레이팅으로
+// 먼저 만들어 두고, 색과 무관하게 user_id 순서로 잠가 같은 두 사람의 동시 결과가 교착되지 않게 합니다.
+func lockRatingPair(ctx context.Context, tx *sql.Tx, g *Game) (white, black RatingEntry, err error) {
+	white = RatingEntry{UserID: strings.TrimSpace(g.WhiteID), Name: strings.TrimSpace(g.WhiteName)}
+	black = RatingEntry{UserID: strings.TrimSpace(g.BlackID), Name: strings.TrimSpace(g.BlackName)}
+	order := []*RatingEntry{&white, &black}
+	if black.UserID < white.UserID {
+		order[0], order[1] = order[1], order[0]
+	}
+	for _, e := range order {
+		if _, err = tx.ExecContext(ctx,
+			`INSERT INTO pvp_ratings (user_id, name, rating, updated_at) VALUES ($1,$2,$3,NOW()) ON CONFLICT (user_id) DO NOTHING`,
+			e.UserID, e.Name, DefaultRating,
+		); err != nil {
+			return
+		}
+	}
+	for _, e := range order {
+		if err = loadRatingForUpdate(ctx, tx, e); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func loadRatingForUpdate(ctx context.Context, tx *sql.Tx, e *RatingEntry) error {
+	return tx.QueryRowContext(ctx,
+		`SELECT rating, games, wins, losses, draws FROM pvp_ratings WHERE user_id = $1 FOR UPDATE`,
+		e.UserID,
+	).Scan(&e.Rating, &e.Games, &e.Wins, &e.Losses, &e.Draws)
+}
+
+func saveRating(ctx context.Context, tx *sql.Tx, e RatingEntry) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO pvp_ratings (
+        user_id, name, rating, games, wins, losses, draws, updated_at
+      ) VALUES ($1,$2,$3,$4,$5,$6,$7,NOW())
+      ON CONFLICT (user_id) DO UPDATE SET
+        name=EXCLUDED.name,
+        rating=EXCLUDED.rating,
+        games=EXCLUDED.games,
+        wins=EXCLUDED.wins,
+        losses=EXCLUDED.losses,
+        draws=EXCLUDED.draws,
+        updated_at=EXCLUDED.updated_at`,
+		e.UserID, e.Name, e.Rating, e.Games, e.Wins, e.Losses, e.Draws,
+	)
+	return err
+}
+
+// resultToken maps the game outcome to white/black/draw (resign/abandon → winner color).
+func resultToken(g *Game) string {
+	result := strings.TrimSpace(g.Outcome)
+	if result == "resign" || result == "abandon" {
+		if g.Winner == g.WhiteID {
+			return "white"
+		} else if g.Winner == g.BlackID {
+			return "black"
+		}
+		return ""
+	}
+	return result
+}
+
 func mapResultToPGN(result string) string {
 	switch strings.ToLower(strings.TrimSpace(result)) {
 	case "white":
@@ -159,3 +325,75 @@ func sanitizePGN(s string) string {
 	s = strings.ReplaceAll(s, "\"", "'")
 	return strings.TrimSpace(s)
 }
+
+// SaveActiveGame upserts the state of an ACTIVE correspondence game (pvp_correspondence_games).
+// 통신 대국은 진행 중에도 이 테이블이 진실 원천이며, Redis는 캐시로 사용됩니다.
+func (r *Repository) SaveActiveGame(ctx context.Context, g *Game) error {
+	if r == nil || r.db == nil || g == nil {
+		return nil
+	}
+	state, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO pvp_correspondence_games (game_id, white_id, black_id, state, updated_at)
+        VALUES ($1,$2,$3,$4,$5)
+        ON CONFLICT (game_id) DO UPDATE SET state=EXCLUDED.state, updated_at=EXCLUDED.updated_at
+        WHERE pvp_correspondence_games.updated_at <= EXCLUDED.updated_at`,
+		g.ID, g.WhiteID, g.BlackID, string(state), g.UpdatedAt)
+	return err
+}
+
+// DeleteActiveGame removes a correspondence game once it is no longer active.
+func (r *Repository) DeleteActiveGame(ctx context.Context, id string) error {
+	if r == nil || r.db == nil {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, `DELETE FROM pvp_correspondence_games WHERE game_id = $1`, strings.TrimSpace(id))
+	return err
+}
+
+// LoadActiveGame returns a stored correspondence game, or nil when absent.
+func (r *Repository) LoadActiveGame(ctx context.Context, id string) (*Game, error) {
+	if r == nil || r.db == nil {
+		return nil, nil
+	}
+	var raw []byte
+	err := r.db.QueryRowContext(ctx, `SELECT state FROM pvp_correspondence_games WHERE game_id = $1`, strings.TrimSpace(id)).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var g Game
+	if err := json.Unmarshal(raw, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// ActiveGames returns every stored correspondence game (startup restore).
+func (r *Repository) ActiveGames(ctx context.Context) ([]*Game, error) {
+	if r == nil || r.db == nil {
+		return nil, nil
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT state FROM pvp_correspondence_games ORDER BY updated_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*Game
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		var g Game
+		if err := json.Unmarshal(raw, &g); err != nil {
+			return nil, err
+		}
+		out = append(out, &g)
+	}
+	return out, rows.Err()
+}
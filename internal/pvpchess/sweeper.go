@@ -0,0 +1,185 @@
+package pvpchess
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// minPliesForForfeit: 이보다 적게 진행된 방치 대국은 승패 없이 중단(ABORTED) 처리합니다.
+const minPliesForForfeit = 2
+
+// errNotStale signals that a game was touched between scan and finalize.
+var errNotStale = errors.New("not_stale")
+
+// SweepAbandoned finalizes ACTIVE games whose UpdatedAt is older than idle (통신 대국은 SetCorrespondenceIdle 기준).
+// 차례인 쪽이 방치한 것으로 보고 상대에게 승리를 부여하며, 2수 미만 대국은 중단 처리합니다.
+// 시간제(clock)가 아닌 방치 대국 정리 용도입니다.
+func (m *Manager) SweepAbandoned(ctx context.Context, idle time.Duration) ([]*Game, error) {
+	if m == nil || m.rdb == nil {
+		return nil, fmt.Errorf("pvp manager not initialized")
+	}
+	if idle <= 0 {
+		return nil, nil
+	}
+	cutoff := time.Now().Add(-idle)
+	ids, err := m.sweepCandidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []*Game
+	for _, id := range ids {
+		g, err := m.get(ctx, id)
+		if err != nil || g == nil {
+			continue
+		}
+		gameCutoff := cutoff
+		if g.Correspondence {
+			// 통신 대국은 별도 기준(SetCorrespondenceIdle) 적용, 0이면 정리하지 않음
+			if m.corrIdle <= 0 {
+				continue
+			}
+			gameCutoff = time.Now().Add(-m.corrIdle)
+		}
+		if g.Status != StatusActive || !g.UpdatedAt.Before(gameCutoff) {
+			continue
+		}
+		fg, method, err := m.finalizeAbandoned(ctx, id, gameCutoff)
+		if err != nil {
+			if !errors.Is(err, errNotStale) {
+				obslog.L().Warn("pvp_abandon_finalize_error", zap.String("game_id", id), zap.Error(err))
+			}
+			continue
+		}
+		obslog.L().Info("pvp_abandon",
+			zap.String("game_id", fg.ID),
+			zap.String("status", string(fg.Status)),
+			zap.String("winner", fg.Winner),
+			zap.Int("plies", len(fg.MovesUCI)),
+			zap.Time("last_update", g.UpdatedAt),
+		)
+		_ = m.persistIfFinal(ctx, fg, method)
+		out = append(out, fg)
+	}
+	return out, nil
+}
+
+// sweepCandidates lists the games SweepAbandoned checks: pvp:active와, 통신 대국 정리가 켜져 있으면
+// Redis에서 빠졌을 수 있는 저장소의 진행 중 통신 대국.
+func (m *Manager) sweepCandidates(ctx context.Context) ([]string, error) {
+	var ids []string
+	seen := map[string]bool{}
+	iter := m.rdb.SScan(ctx, activeGamesKey, 0, "", 200).Iterator()
+	for iter.Next(ctx) {
+		if id := iter.Val(); !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	if m.corrIdle > 0 && m.repo != nil {
+		games, err := m.repo.ActiveGames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range games {
+			if !seen[g.ID] {
+				seen[g.ID] = true
+				ids = append(ids, g.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// StartAbandonSweeper runs SweepAbandoned every interval until ctx is done.
+// onFinish는 종료 처리된 대국마다 호출됩니다(팬아웃 안내 등).
+func (m *Manager) StartAbandonSweeper(ctx context.Context, idle, interval time.Duration, onFinish func(*Game)) {
+	if m == nil || idle <= 0 || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				games, err := m.SweepAbandoned(ctx, idle)
+				if err != nil {
+					obslog.L().Warn("pvp_abandon_sweep_error", zap.Error(err))
+				}
+				if onFinish == nil {
+					continue
+				}
+				for _, g := range games {
+					onFinish(g)
+				}
+			}
+		}
+	}()
+}
+
+// finalizeAbandoned re-checks staleness under WATCH and writes the forfeit/abort outcome.
+func (m *Manager) finalizeAbandoned(ctx context.Context, id string, cutoff time.Time) (*Game, string, error) {
+	gameK := gameKey(id)
+	var out *Game
+	method := ""
+	err := m.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		raw, err := tx.Get(ctx, gameK).Bytes()
+		if err == redis.Nil {
+			return errNotStale
+		}
+		if err != nil {
+			return err
+		}
+		var cur Game
+		if jerr := json.Unmarshal(raw, &cur); jerr != nil {
+			return jerr
+		}
+		if cur.Status != StatusActive || !cur.UpdatedAt.Before(cutoff) {
+			return errNotStale
+		}
+		if len(cur.MovesUCI) < minPliesForForfeit {
+			cur.Status = StatusAborted
+			cur.Winner = ""
+			cur.Outcome = "aborted"
+			method = "aborted"
+		} else {
+			// 차례인 쪽이 방치 → 기다리던 쪽 승리
+			idleID := cur.WhiteID
+			if cur.Turn == Black {
+				idleID = cur.BlackID
+			}
+			cur.Status = StatusFinished
+			cur.Winner = opponentID(&cur, idleID)
+			cur.Outcome = "abandon"
+			method = "abandonment"
+		}
+		cur.DrawOfferBy = ""
+		cur.DrawOfferPly = 0
+		cur.UpdatedAt = time.Now()
+		if err := m.commitGame(ctx, tx, &cur, StatusActive); err != nil {
+			return err
+		}
+		out = &cur
+		return nil
+	}, gameK)
+	if err != nil {
+		if errors.Is(err, redis.TxFailedErr) {
+			return nil, "", errNotStale
+		}
+		return nil, "", err
+	}
+	m.unindexCorrespondence(ctx, out)
+	return out, method, nil
+}
@@ -1,6 +1,7 @@
 package pvpchess
 
 import (
+	"errors"
 	"time"
 )
 
@@ -41,4 +42,68 @@ type Game struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	Winner      string    `json:"winner,omitempty"`
 	Outcome     string    `json:"outcome,omitempty"`
+
+	// Handle is the short game number shown as #N; unique among both players' ACTIVE games (0 for older games).
+	Handle int `json:"handle,omitempty"`
+
+	// Correspondence marks a 통신 대국: no Redis TTL while active, mirrored to Postgres on every change.
+	Correspondence bool `json:"correspondence,omitempty"`
+	// WhiteRoom/BlackRoom are the rooms each player joined from (차례 알림 대상).
+	WhiteRoom string `json:"white_room,omitempty"`
+	BlackRoom string `json:"black_room,omitempty"`
+
+	// DrawOfferBy is the user who has a pending draw offer; DrawOfferPly is len(MovesUCI) when it was made.
+	DrawOfferBy  string `json:"draw_offer_by,omitempty"`
+	DrawOfferPly int    `json:"draw_offer_ply,omitempty"`
+
+	// TakebackBy is the user who requested a takeback; TakebackPly is len(MovesUCI) when it was requested.
+	TakebackBy  string `json:"takeback_by,omitempty"`
+	TakebackPly int    `json:"takeback_ply,omitempty"`
+
+	// PvP ratings after the result was applied (zero when unrated).
+	WhiteRating      int `json:"white_rating,omitempty"`
+	WhiteRatingDelta int `json:"white_rating_delta,omitempty"`
+	BlackRating      int `json:"black_rating,omitempty"`
+	BlackRatingDelta int `json:"black_rating_delta,omitempty"`
 }
+
+// Draw offer errors surfaced to the command layer.
+var (
+	ErrDrawOfferPending = errors.New("draw offer already pending")
+	ErrNoDrawOffer      = errors.New("no pending draw offer")
+	ErrOwnDrawOffer     = errors.New("cannot respond to own draw offer")
+)
+
+// Takeback errors surfaced to the command layer.
+var (
+	ErrTakebackPending = errors.New("takeback request already pending")
+	ErrNoTakeback      = errors.New("no pending takeback request")
+	ErrOwnTakeback     = errors.New("cannot respond to own takeback request")
+	ErrNothingToUndo   = errors.New("no move to take back")
+)
+
+// TimeControlCorrespondence is the CreateGameFromChallenge time control that starts a correspondence game.
+const TimeControlCorrespondence = "correspondence"
+
+// RoomOf returns the room the player joined from ("" when unknown or not a player).
+func (g *Game) RoomOf(userID string) string {
+	switch userID {
+	case g.WhiteID:
+		return g.WhiteRoom
+	case g.BlackID:
+		return g.BlackRoom
+	}
+	return ""
+}
+
+// ErrTooManyGames is returned when the instance-wide ACTIVE game cap is reached.
+var ErrTooManyGames = errors.New("too many active games")
+
+// Game selection errors surfaced to the command layer.
+var (
+	// ErrTooManyUserGames: 사용자가 동시 진행 가능한 대국 수(SetMaxGamesPerUser)를 이미 채움
+	ErrTooManyUserGames = errors.New("user has too many active games")
+	ErrNoSuchGame       = errors.New("no active game with that handle")
+	ErrAmbiguousGame    = errors.New("several active games match; pick one by handle")
+	ErrGameNotInRoom    = errors.New("game is not played in this room")
+)
@@ -0,0 +1,315 @@
+package chess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	nchess "github.com/corentings/chess/v2"
+	corechess "github.com/park285/Cheese-KakaoTalk-bot/internal/chess"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/domain"
+	"go.uber.org/zap"
+)
+
+var ErrAnalysisUnavailable = errors.New("chess analysis unavailable")
+
+// 플레이어 수 분류(센티폰 손실 기준)
+const (
+	MoveClassBest       = "best"
+	MoveClassGood       = "good"
+	MoveClassInaccuracy = "inaccuracy"
+	MoveClassMistake    = "mistake"
+	MoveClassBlunder    = "blunder"
+)
+
+const (
+	defaultAnalysisDepth = 14
+	analysisTimeout      = 3 * time.Minute
+	// analysisEvalClamp caps mate scores so a single forced mate does not dominate cp loss/accuracy.
+	analysisEvalClamp = 1000
+)
+
+// Analyzer is implemented by engines that can evaluate every position of a finished game.
+type Analyzer interface {
+	AnalyzeGame(ctx context.Context, startFEN string, moves []string, depth int) ([]corechess.PositionEval, error)
+}
+
+// AnalysisReport is the post-game analysis of a stored game from the player's side.
+type AnalysisReport struct {
+	Game        *domain.ChessGame
+	Analysis    *domain.GameAnalysis
+	PlayerColor string
+	Counts      map[string]int
+	// Worst is the player's move with the largest centipawn loss (nil when the player made no moves).
+	Worst      *domain.MoveEvaluation
+	WorstSAN   string
+	BestSAN    string
+	BoardImage []byte
+}
+
+// Analyze replays a stored game through the engine (once; later calls reuse the stored result)
+// and reports the player's accuracy, move classes and worst moment.
+func (s *Service) Analyze(ctx context.Context, meta SessionMeta, id int64) (*AnalysisReport, error) {
+	if err := s.ensureReady(); err != nil {
+		return nil, err
+	}
+	if err := s.ensureRoomAllowed(meta); err != nil {
+		return nil, err
+	}
+	identity := deriveIdentity(meta)
+	game, err := s.repo.GetGame(ctx, id, identity.PlayerHash)
+	if err != nil {
+		return nil, err
+	}
+	if game == nil {
+		return nil, ErrGameNotFound
+	}
+	human := nchess.White
+	if game.PlayerColor == PlayerColorBlack {
+		human = nchess.Black
+	}
+
+	analysis, err := s.repo.GetAnalysis(ctx, game.ID)
+	if err != nil {
+		return nil, err
+	}
+	if analysis == nil {
+		analysis, err = s.runAnalysis(ctx, game, human)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := &AnalysisReport{
+		Game:        game,
+		Analysis:    analysis,
+		PlayerColor: colorName(human),
+		Counts:      map[string]int{},
+	}
+	for i := range analysis.Moves {
+		mv := &analysis.Moves[i]
+		if mv.Class == "" {
+			continue
+		}
+		report.Counts[mv.Class]++
+		if report.Worst == nil || mv.CPLoss > report.Worst.CPLoss {
+			report.Worst = mv
+		}
+	}
+	if report.Worst != nil {
+		s.attachWorstMoment(ctx, report, human)
+	}
+	return report, nil
+}
+
+func (s *Service) runAnalysis(ctx context.Context, game *domain.ChessGame, human nchess.Color) (*domain.GameAnalysis, error) {
+	if s.analyzer == nil {
+		return nil, ErrAnalysisUnavailable
+	}
+	final, err := replayMoves(game.StartFEN, game.MovesUCI)
+	if err != nil {
+		return nil, err
+	}
+	depth := s.cfg.AnalysisDepth
+	analysisCtx, cancel := context.WithTimeout(ctx, analysisTimeout)
+	defer cancel()
+
+	start := time.Now()
+	evals, err := s.analyzer.AnalyzeGame(analysisCtx, game.StartFEN, game.MovesUCI, depth)
+	if err != nil {
+		s.logger.Warn("chess analysis failed",
+			zap.Error(err),
+			zap.Int64("game_id", game.ID),
+			zap.Int("plies", len(game.MovesUCI)),
+			zap.Int("depth", depth),
+		)
+		return nil, mapEngineError(err)
+	}
+	first := final.Positions()[0].Turn()
+	analysis, err := buildGameAnalysis(game.ID, game.MovesUCI, evals, human, first, final.Outcome(), depth)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveAnalysis(ctx, analysis); err != nil {
+		s.logger.Warn("failed to persist chess analysis", zap.Error(err), zap.Int64("game_id", game.ID))
+	}
+	s.logger.Info("chess analysis",
+		zap.Int64("game_id", game.ID),
+		zap.Int("plies", len(game.MovesUCI)),
+		zap.Int("depth", depth),
+		zap.Float64("accuracy", analysis.Accuracy),
+		zap.Duration("elapsed", time.Since(start)),
+	)
+	return analysis, nil
+}
+
+// buildGameAnalysis turns engine evaluations (len(moves)+1, white-relative) into per-ply
+// verdicts; only the human's moves are classified and counted toward accuracy.
+// first is the side to move in the starting position.
+func buildGameAnalysis(gameID int64, moves []string, evals []corechess.PositionEval, human, first nchess.Color, final nchess.Outcome, depth int) (*domain.GameAnalysis, error) {
+	if len(evals) != len(moves)+1 {
+		return nil, fmt.Errorf("analysis length mismatch: %d evals for %d moves", len(evals), len(moves))
+	}
+	// 종국 포지션은 엔진 점수가 없으므로 결과로 대체
+	if n := len(evals) - 1; n > 0 && evals[n].BestMove == "" {
+		switch final {
+		case nchess.WhiteWon:
+			evals[n].EvalCP = corechess.MateScoreCP
+		case nchess.BlackWon:
+			evals[n].EvalCP = -corechess.MateScoreCP
+		case nchess.Draw:
+			evals[n].EvalCP = 0
+		}
+	}
+
+	analysis := &domain.GameAnalysis{GameID: gameID, Depth: depth}
+	accuracySum, accuracyN := 0.0, 0
+	for i, mv := range moves {
+		mover := first
+		if i%2 == 1 {
+			mover = first.Other()
+		}
+		sign := 1
+		if mover == nchess.Black {
+			sign = -1
+		}
+		before := clampEval(evals[i].EvalCP) * sign
+		after := clampEval(evals[i+1].EvalCP) * sign
+		loss := before - after
+		if loss < 0 {
+			loss = 0
+		}
+		played := strings.ToLower(strings.TrimSpace(mv))
+		entry := domain.MoveEvaluation{
+			Ply:      i + 1,
+			MoveUCI:  played,
+			BestMove: evals[i].BestMove,
+			EvalCP:   evals[i+1].EvalCP,
+			CPLoss:   loss,
+		}
+		if mover == human {
+			entry.Class = classifyMove(loss, played, evals[i].BestMove)
+			accuracySum += moveAccuracy(before, after)
+			accuracyN++
+		}
+		analysis.Moves = append(analysis.Moves, entry)
+	}
+	if accuracyN > 0 {
+		analysis.Accuracy = math.Round(accuracySum/float64(accuracyN)*10) / 10
+	}
+	return analysis, nil
+}
+
+func classifyMove(cpLoss int, played, best string) string {
+	switch {
+	case strings.EqualFold(played, best) || cpLoss <= 10:
+		return MoveClassBest
+	case cpLoss <= 50:
+		return MoveClassGood
+	case cpLoss <= 100:
+		return MoveClassInaccuracy
+	case cpLoss <= 300:
+		return MoveClassMistake
+	default:
+		return MoveClassBlunder
+	}
+}
+
+func clampEval(cp int) int {
+	if cp > analysisEvalClamp {
+		return analysisEvalClamp
+	}
+	if cp < -analysisEvalClamp {
+		return -analysisEvalClamp
+	}
+	return cp
+}
+
+// winPercent maps a mover-relative evaluation to an expected score in percent.
+func winPercent(cp int) float64 {
+	return 50 + 50*(2/(1+math.Exp(-0.00368208*float64(cp)))-1)
+}
+
+// moveAccuracy scores a single move (0..100) from the drop in win percentage.
+func moveAccuracy(before, after int) float64 {
+	drop := winPercent(before) - winPercent(after)
+	if drop < 0 {
+		drop = 0
+	}
+	acc := 103.1668*math.Exp(-0.04354*drop) - 3.1669
+	return math.Max(0, math.Min(100, acc))
+}
+
+func replayMoves(startFEN string, moves []string) (*nchess.Game, error) {
+	return replaySession(&sessionPayload{StartFEN: startFEN, Moves: moves})
+}
+
+// attachWorstMoment renders the position before the player's worst move with the engine's
+// better move as an arrow and the moved piece marked.
+func (s *Service) attachWorstMoment(ctx context.Context, report *AnalysisReport, human nchess.Color) {
+	worst := report.Worst
+	game, err := replayMoves(report.Game.StartFEN, report.Game.MovesUCI[:worst.Ply-1])
+	if err != nil {
+		s.logger.Warn("failed to replay chess game for analysis", zap.Error(err), zap.Int64("game_id", report.Game.ID))
+		return
+	}
+	pos := game.Position()
+	notationUCI := nchess.UCINotation{}
+	notationSAN := nchess.AlgebraicNotation{}
+	side := "White"
+	if human == nchess.Black {
+		side = "Black"
+	}
+	opts := RenderOptions{
+		HUDHeader: fmt.Sprintf("분석 #%d • 정확도 %.1f%%", report.Game.ID, report.Analysis.Accuracy),
+		HUDTurn:   fmt.Sprintf("%s • %d턴", side, (worst.Ply+1)/2),
+	}
+	opts.Material, opts.Captured = computeMaterial(game)
+	if human == nchess.Black {
+		opts.Flip = true
+		opts.ViewerColor = nchess.Black
+	}
+	if played, err := notationUCI.Decode(pos, worst.MoveUCI); err == nil {
+		report.WorstSAN = notationSAN.Encode(pos, played)
+		opts.Player = &PlayerMarker{Square: played.S1()}
+	}
+	if worst.BestMove != "" && !strings.EqualFold(worst.BestMove, worst.MoveUCI) {
+		if best, err := notationUCI.Decode(pos, worst.BestMove); err == nil {
+			report.BestSAN = notationSAN.Encode(pos, best)
+			opts.Hint = &MoveHighlight{From: best.S1(), To: best.S2()}
+		}
+	}
+	data, err := s.renderer.RenderPNG(ctx, pos.Board(), opts)
+	if err != nil {
+		s.logger.Warn("failed to render chess analysis board", zap.Error(err))
+		return
+	}
+	report.BoardImage = data
+}
+
+// EvalGraph renders the stored analysis of a game as an evaluation chart.
+// 아직 분석되지 않은 게임이거나 렌더러가 차트를 지원하지 않으면 nil을 반환합니다.
+func (s *Service) EvalGraph(ctx context.Context, game *domain.ChessGame) ([]byte, error) {
+	if game == nil {
+		return nil, ErrGameNotFound
+	}
+	graph, ok := s.renderer.(EvalGraphRenderer)
+	if !ok {
+		return nil, nil
+	}
+	analysis, err := s.repo.GetAnalysis(ctx, game.ID)
+	if err != nil || analysis == nil || len(analysis.Moves) == 0 {
+		return nil, err
+	}
+	side := "White"
+	if game.PlayerColor == PlayerColorBlack {
+		side = "Black"
+	}
+	return graph.RenderEvalGraphPNG(ctx, analysis, EvalGraphOptions{
+		Title:    fmt.Sprintf("평가 그래프 #%d", game.ID),
+		Subtitle: fmt.Sprintf("%s • 정확도 %.1f%% • depth %d", side, analysis.Accuracy, analysis.Depth),
+	})
+}
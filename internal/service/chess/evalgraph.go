@@ -0,0 +1,217 @@
+package chess
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	imagedraw "image/draw"
+	"image/png"
+	"math"
+	"strings"
+
+	fontassets "github.com/park285/Cheese-KakaoTalk-bot/internal/assets/fonts"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/domain"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// EvalGraphOptions controls the evaluation chart caption.
+type EvalGraphOptions struct {
+	Title    string
+	Subtitle string
+}
+
+// EvalGraphRenderer is implemented by renderers that can also draw an evaluation-over-time chart.
+type EvalGraphRenderer interface {
+	RenderEvalGraphPNG(ctx context.Context, analysis *domain.GameAnalysis, opts EvalGraphOptions) ([]byte, error)
+}
+
+var (
+	evalGraphBackground = color.RGBA{R: 22, G: 24, B: 36, A: 255}
+	evalGraphPlotColor  = color.NRGBA{R: 32, G: 35, B: 52, A: 255}
+	evalGraphWhiteArea  = color.NRGBA{R: 236, G: 239, B: 255, A: 150}
+	evalGraphBlackArea  = color.NRGBA{R: 8, G: 9, B: 14, A: 170}
+	evalGraphGridColor  = color.NRGBA{R: 204, G: 210, B: 236, A: 40}
+	evalGraphZeroColor  = color.NRGBA{R: 204, G: 210, B: 236, A: 120}
+	evalGraphLineColor  = color.NRGBA{R: 255, G: 196, B: 64, A: 255}
+	evalGraphBlunder    = color.NRGBA{R: 236, G: 72, B: 72, A: 255}
+	evalGraphLabelColor = color.NRGBA{R: 204, G: 210, B: 236, A: 255}
+)
+
+// RenderEvalGraphPNG draws the white-relative evaluation after every ply. Mate scores are
+// clamped to ±analysisEvalClamp and the player's blunders are marked with red dots.
+func (r *svgBoardRenderer) RenderEvalGraphPNG(ctx context.Context, analysis *domain.GameAnalysis, opts EvalGraphOptions) ([]byte, error) {
+	if analysis == nil || len(analysis.Moves) == 0 {
+		return nil, fmt.Errorf("analysis is empty")
+	}
+
+	const (
+		width        = 960
+		height       = 480
+		leftMargin   = 72
+		rightMargin  = 28
+		topMargin    = 76
+		bottomMargin = 48
+		lineRadius   = 2
+		markerRadius = 7
+		gridStepCP   = 250
+	)
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	imagedraw.Draw(img, img.Bounds(), image.NewUniform(evalGraphBackground), image.Point{}, imagedraw.Src)
+	plot := image.Rect(leftMargin, topMargin, width-rightMargin, height-bottomMargin)
+	drawRoundedPanel(img, plot.Inset(-8), 12, evalGraphPlotColor)
+
+	plies := len(analysis.Moves)
+	xAt := func(ply int) float64 {
+		return float64(plot.Min.X) + float64(plot.Dx())*float64(ply)/float64(plies)
+	}
+	yAt := func(cp int) float64 {
+		ratio := float64(clampEval(cp)) / float64(analysisEvalClamp)
+		return float64(plot.Min.Y) + float64(plot.Dy())*(1-ratio)/2
+	}
+	zeroY := yAt(0)
+
+	// 시작 포지션은 0으로 두고 ply마다 평가값을 잇는다
+	points := make([]pointF, 0, plies+1)
+	points = append(points, pointF{X: xAt(0), Y: zeroY})
+	for i, mv := range analysis.Moves {
+		points = append(points, pointF{X: xAt(i + 1), Y: yAt(mv.EvalCP)})
+	}
+
+	// 우세 영역 채우기(백 우세=밝게, 흑 우세=어둡게), 픽셀 열마다 한 번씩
+	for x := plot.Min.X; x < plot.Max.X; x++ {
+		pos := (float64(x) + 0.5 - float64(plot.Min.X)) / float64(plot.Dx()) * float64(plies)
+		i := int(pos)
+		if i >= plies {
+			i = plies - 1
+		}
+		a, b := points[i], points[i+1]
+		y := a.Y + (b.Y-a.Y)*(pos-float64(i))
+		top, bottom, clr := y, zeroY, evalGraphWhiteArea
+		if y > zeroY {
+			top, bottom, clr = zeroY, y, evalGraphBlackArea
+		}
+		imagedraw.Draw(img, image.Rect(x, int(math.Round(top)), x+1, int(math.Round(bottom))), image.NewUniform(clr), image.Point{}, imagedraw.Over)
+	}
+
+	for cp := -analysisEvalClamp; cp <= analysisEvalClamp; cp += gridStepCP {
+		clr := evalGraphGridColor
+		if cp == 0 {
+			clr = evalGraphZeroColor
+		}
+		y := int(yAt(cp))
+		imagedraw.Draw(img, image.Rect(plot.Min.X, y, plot.Max.X, y+1), image.NewUniform(clr), image.Point{}, imagedraw.Over)
+	}
+
+	for i := 1; i < len(points); i++ {
+		drawThickSegment(img, points[i-1], points[i], lineRadius, evalGraphLineColor)
+	}
+	for i, mv := range analysis.Moves {
+		if mv.Class != MoveClassBlunder {
+			continue
+		}
+		center := points[i+1]
+		drawDisc(img, image.Point{X: int(math.Round(center.X)), Y: int(math.Round(center.Y))}, markerRadius, evalGraphBlunder)
+	}
+
+	if err := drawEvalGraphLabels(img, plot, plies, opts, xAt, yAt); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return pngBuf.Bytes(), nil
+}
+
+func drawEvalGraphLabels(img *image.RGBA, plot image.Rectangle, plies int, opts EvalGraphOptions, xAt func(int) float64, yAt func(int) float64) error {
+	titleFace, err := fontassets.CaptionFaceSized(26)
+	if err != nil {
+		return err
+	}
+	labelFace, err := fontassets.CaptionFaceSized(16)
+	if err != nil {
+		return err
+	}
+
+	drawer := &font.Drawer{Dst: img, Face: titleFace, Src: image.NewUniform(hudTextPrimary)}
+	title := strings.TrimSpace(opts.Title)
+	if title == "" {
+		title = "평가 그래프"
+	}
+	drawer.Dot = fixed.P(plot.Min.X-8, 38)
+	drawer.DrawString(title)
+	if sub := strings.TrimSpace(opts.Subtitle); sub != "" {
+		drawer.Face = labelFace
+		drawer.Src = image.NewUniform(hudTurnTextColor)
+		drawer.Dot = fixed.P(plot.Min.X-8, 62)
+		drawer.DrawString(sub)
+	}
+
+	drawer.Face = labelFace
+	drawer.Src = image.NewUniform(evalGraphLabelColor)
+	ascent := labelFace.Metrics().Ascent.Ceil()
+	for _, cp := range []int{analysisEvalClamp, analysisEvalClamp / 2, 0, -analysisEvalClamp / 2, -analysisEvalClamp} {
+		label := fmt.Sprintf("%+d", cp/100)
+		if cp == 0 {
+			label = "0"
+		}
+		width := drawer.MeasureString(label).Round()
+		drawer.Dot = fixed.P(plot.Min.X-16-width, int(yAt(cp))+ascent/2)
+		drawer.DrawString(label)
+	}
+
+	// 가로축: 수 번호(전체 수에 맞춰 5/10/20 단위)
+	moves := (plies + 1) / 2
+	step := 5
+	for moves/step > 8 {
+		step *= 2
+	}
+	for move := step; move <= moves; move += step {
+		drawCenteredText(drawer, fmt.Sprintf("%d", move), int(xAt(move*2-1)), plot.Max.Y+16+ascent)
+	}
+	return nil
+}
+
+func drawThickSegment(img *image.RGBA, a, b pointF, radius int, clr color.Color) {
+	steps := int(math.Ceil(math.Hypot(b.X-a.X, b.Y-a.Y)))
+	if steps < 1 {
+		steps = 1
+	}
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := int(math.Round(a.X + (b.X-a.X)*t))
+		y := int(math.Round(a.Y + (b.Y-a.Y)*t))
+		drawSolidDisc(img, x, y, radius, clr)
+	}
+}
+
+// drawSolidDisc paints without blending so overlapping stamps of a line do not accumulate alpha.
+func drawSolidDisc(img *image.RGBA, cx, cy, radius int, clr color.Color) {
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y > radius*radius {
+				continue
+			}
+			if (image.Point{X: cx + x, Y: cy + y}).In(img.Bounds()) {
+				img.Set(cx+x, cy+y, clr)
+			}
+		}
+	}
+}
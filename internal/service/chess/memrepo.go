@@ -20,6 +20,8 @@ type memrepo struct {
     gamesByIndex map[string]*domain.ChessGame   // sessionUUID|playerHash -> game
 
     profiles map[string]*domain.ChessProfile // playerHash|roomHash -> profile
+
+    analyses map[int64]*domain.GameAnalysis // gameID -> analysis
 }
 
 func NewMemoryRepository() Repository {
@@ -28,6 +30,7 @@ func NewMemoryRepository() Repository {
         gamesByUser:  make(map[string][]*domain.ChessGame),
         gamesByIndex: make(map[string]*domain.ChessGame),
         profiles:     make(map[string]*domain.ChessProfile),
+        analyses:     make(map[int64]*domain.GameAnalysis),
     }
 }
 
@@ -123,6 +126,29 @@ func (m *memrepo) UpsertProfile(ctx context.Context, profile *domain.ChessProfil
     return nil
 }
 
+func (m *memrepo) SaveAnalysis(ctx context.Context, analysis *domain.GameAnalysis) error {
+    if analysis == nil {
+        return nil
+    }
+    copy := *analysis
+    copy.Moves = append([]domain.MoveEvaluation(nil), analysis.Moves...)
+    m.mu.Lock()
+    m.analyses[analysis.GameID] = &copy
+    m.mu.Unlock()
+    return nil
+}
+
+func (m *memrepo) GetAnalysis(ctx context.Context, gameID int64) (*domain.GameAnalysis, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+    if a, ok := m.analyses[gameID]; ok && a != nil {
+        copy := *a
+        copy.Moves = append([]domain.MoveEvaluation(nil), a.Moves...)
+        return &copy, nil
+    }
+    return nil, nil
+}
+
 func (m *memrepo) sessionKey(sessionUUID, playerHash string) string {
     return strings.TrimSpace(sessionUUID) + "|" + strings.TrimSpace(playerHash)
 }
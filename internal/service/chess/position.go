@@ -0,0 +1,101 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	nchess "github.com/corentings/chess/v2"
+)
+
+var ErrInvalidPosition = errors.New("invalid chess position")
+
+// startPosFEN is the engine/UCI token for the standard initial position.
+const startPosFEN = "startpos"
+
+// newGameFrom creates a game at startFEN; an empty FEN means the standard initial position.
+func newGameFrom(startFEN string) (*nchess.Game, error) {
+	fen := strings.TrimSpace(startFEN)
+	if fen == "" || fen == startPosFEN {
+		return nchess.NewGame(), nil
+	}
+	opt, err := nchess.FEN(fen)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPosition, err)
+	}
+	game := nchess.NewGame(opt)
+	// 외부에서 받은 포지션이므로 PGN 내보내기에 시작 FEN을 남김
+	game.AddTagPair("SetUp", "1")
+	game.AddTagPair("FEN", fen)
+	return game, nil
+}
+
+// engineFEN returns the FEN token for EvaluateRequest / UCI position commands.
+func engineFEN(startFEN string) string {
+	if fen := strings.TrimSpace(startFEN); fen != "" {
+		return fen
+	}
+	return startPosFEN
+}
+
+// validateStartFEN checks that fen describes a playable position: one king per side, the
+// side not to move is not in check, and the side to move has at least one legal move.
+// 정규화된 FEN을 반환합니다.
+func validateStartFEN(fen string) (string, error) {
+	fen = strings.Join(strings.Fields(fen), " ")
+	if fen == "" {
+		return "", fmt.Errorf("%w: empty FEN", ErrInvalidPosition)
+	}
+	game, err := newGameFrom(fen)
+	if err != nil {
+		return "", err
+	}
+	pos := game.Position()
+	board := pos.Board()
+	kings := map[nchess.Color]int{}
+	for _, piece := range board.SquareMap() {
+		if piece.Type() == nchess.King {
+			kings[piece.Color()]++
+		}
+	}
+	if kings[nchess.White] != 1 || kings[nchess.Black] != 1 {
+		return "", fmt.Errorf("%w: each side needs exactly one king", ErrInvalidPosition)
+	}
+	// 둘 차례가 아닌 쪽이 체크 상태면 불가능한 포지션
+	if opponentInCheck(pos) {
+		return "", fmt.Errorf("%w: side not to move is in check", ErrInvalidPosition)
+	}
+	if len(pos.ValidMoves()) == 0 {
+		return "", fmt.Errorf("%w: game is already over", ErrInvalidPosition)
+	}
+	return pos.String(), nil
+}
+
+// positionFromPGN parses a PGN (tags optional) and returns the FEN reached after its moves,
+// which becomes the starting position of the new session.
+func positionFromPGN(pgn string) (string, error) {
+	pgn = strings.TrimSpace(pgn)
+	if pgn == "" {
+		return "", fmt.Errorf("%w: empty PGN", ErrInvalidPosition)
+	}
+	opt, err := nchess.PGN(strings.NewReader(pgn))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidPosition, err)
+	}
+	game := nchess.NewGame(opt)
+	if game.Outcome() != nchess.NoOutcome {
+		return "", fmt.Errorf("%w: game is already over", ErrInvalidPosition)
+	}
+	return validateStartFEN(game.FEN())
+}
+
+// opponentInCheck reports whether the side to move could capture the opposing king.
+func opponentInCheck(pos *nchess.Position) bool {
+	board := pos.Board()
+	for _, mv := range pos.ValidMoves() {
+		if piece := board.Piece(mv.S2()); piece.Type() == nchess.King {
+			return true
+		}
+	}
+	return false
+}
@@ -36,6 +36,8 @@ type RenderOptions struct {
     // - 상대 수: 하늘색 화살표
     // 값이 NoColor이면 기존(백=사각형, 흑=화살표) 규칙을 사용.
     ViewerColor nchess.Color
+    // Hint: 추천 수(분석의 더 나은 수) — 초록 화살표
+    Hint *MoveHighlight
 }
 
 type PlayerMarker struct {
@@ -121,6 +123,9 @@ func (r *svgBoardRenderer) RenderPNG(ctx context.Context, board *nchess.Board, o
         return nil, err
     }
     drawHighlight(img, board, opts.Highlight, squareSize, boardOrigin, opts)
+    if opts.Hint != nil {
+        drawArrow(img, opts.Hint.From, opts.Hint.To, squareSize, boardOrigin, hintMoveArrow, opts.Flip)
+    }
     drawPlayerMarker(img, board, opts.Player, squareSize, boardOrigin, opts.Flip)
 
     if err := drawCoordinates(img, squareSize, boardOrigin, sideMargin, opts.Flip); err != nil {
@@ -148,6 +153,7 @@ var (
 	whiteMoveHighlightFill    = color.NRGBA{R: 255, G: 228, B: 120, A: 140}
 	blackMoveHighlightArrow   = color.NRGBA{R: 148, G: 207, B: 255, A: 170}
 	neutralMoveHighlightArrow = color.NRGBA{R: 182, G: 184, B: 190, A: 140}
+	hintMoveArrow             = color.NRGBA{R: 96, G: 196, B: 120, A: 180}
 	hudPanelColor             = color.NRGBA{R: 28, G: 31, B: 46, A: 250}
 	hudTurnPanelColor         = color.NRGBA{R: 32, G: 35, B: 52, A: 245}
 	hudShadowColor            = color.NRGBA{0, 0, 0, 50}
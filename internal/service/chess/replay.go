@@ -0,0 +1,347 @@
+package chess
+
+import (
+	"bytes"
+	"compress/lzw"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/png"
+	"strconv"
+	"strings"
+	"time"
+
+	nchess "github.com/corentings/chess/v2"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/domain"
+	"go.uber.org/zap"
+	xdraw "golang.org/x/image/draw"
+)
+
+var ErrReplayTooLarge = errors.New("chess replay exceeds size limit")
+
+const (
+	defaultReplayFrameDelay = 800 * time.Millisecond
+	defaultReplayMaxBytes   = 5 << 20
+	// defaultReplayMaxFrames: 이보다 긴 대국은 렌더링 전에 일정 간격으로 수를 건너뛰어 프레임 수를 제한
+	defaultReplayMaxFrames = 80
+	// 마지막 프레임은 결과를 볼 수 있도록 더 오래 유지
+	replayFinalFrameHold = 3
+)
+
+// replayScales are tried in order until the encoded GIF fits ReplayMaxBytes.
+var replayScales = []float64{1, 0.75, 0.5, 0.35}
+
+// errReplayScaleTooLarge aborts one scale as soon as the partial GIF passes the size limit.
+var errReplayScaleTooLarge = errors.New("replay scale too large")
+
+// Replay renders a stored game as an animated GIF: the start position followed by one frame
+// per ply with the move drawn as an arrow, from the player's side of the board.
+// 긴 대국은 ReplayMaxFrames에 맞춰 수를 건너뛰며, 프레임은 만들어지는 즉시 GIF로 인코딩하고
+// 크기 제한을 넘는 순간 더 작은 배율로 넘어갑니다.
+func (s *Service) Replay(ctx context.Context, game *domain.ChessGame) ([]byte, error) {
+	if game == nil {
+		return nil, ErrGameNotFound
+	}
+	maxFrames := s.cfg.ReplayMaxFrames
+	if maxFrames <= 0 {
+		maxFrames = defaultReplayMaxFrames
+	}
+	frames, err := s.newReplayFrames(ctx, game, replayPlies(len(game.MovesUCI), maxFrames))
+	if err != nil {
+		return nil, err
+	}
+
+	delay := int(s.cfg.ReplayFrameDelay / (10 * time.Millisecond))
+	if delay <= 0 {
+		delay = 1
+	}
+	for _, scale := range replayScales {
+		data, err := encodeReplayGIF(ctx, frames, scale, delay, s.cfg.ReplayMaxBytes)
+		if errors.Is(err, errReplayScaleTooLarge) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	s.logger.Warn("chess replay too large",
+		zap.Int64("game_id", game.ID),
+		zap.Int("frames", frames.count()),
+		zap.Int("max_bytes", s.cfg.ReplayMaxBytes),
+	)
+	return nil, ErrReplayTooLarge
+}
+
+// replayPlies picks which plies get a frame (0 = start position, n = after the n-th move):
+// every ply when they fit in maxFrames, otherwise evenly spaced plies that always include the last.
+func replayPlies(moves, maxFrames int) []int {
+	if maxFrames < 2 {
+		maxFrames = 2
+	}
+	if moves+1 <= maxFrames {
+		plies := make([]int, moves+1)
+		for i := range plies {
+			plies[i] = i
+		}
+		return plies
+	}
+	step := (moves + maxFrames - 2) / (maxFrames - 1)
+	plies := make([]int, 0, maxFrames)
+	for p := 0; p < moves; p += step {
+		plies = append(plies, p)
+	}
+	return append(plies, moves)
+}
+
+// replayFrames renders replay frames lazily in order and keeps only their PNG bytes,
+// so a retry at a smaller scale does not re-render the board.
+type replayFrames struct {
+	s      *Service
+	ctx    context.Context
+	game   *domain.ChessGame
+	replay *nchess.Game
+	plies  []int
+	pngs   [][]byte
+
+	header     string
+	human      nchess.Color
+	firstMove  int
+	blackFirst bool
+	applied    int
+}
+
+func (s *Service) newReplayFrames(ctx context.Context, game *domain.ChessGame, plies []int) (*replayFrames, error) {
+	f := &replayFrames{s: s, ctx: ctx, game: game, plies: plies, human: nchess.White, firstMove: 1}
+	side := "White"
+	if game.PlayerColor == PlayerColorBlack {
+		f.human = nchess.Black
+		side = "Black"
+	}
+	f.header = fmt.Sprintf("기보 #%d • %s", game.ID, side)
+
+	replay, err := newGameFrom(game.StartFEN)
+	if err != nil {
+		return nil, err
+	}
+	f.replay = replay
+	if fields := strings.Fields(game.StartFEN); len(fields) == 6 {
+		if n, convErr := strconv.Atoi(fields[5]); convErr == nil && n > 0 {
+			f.firstMove = n
+		}
+	}
+	f.blackFirst = replay.Position().Turn() == nchess.Black
+	f.pngs = make([][]byte, 0, len(plies))
+	return f, nil
+}
+
+func (f *replayFrames) count() int { return len(f.plies) }
+
+// frame returns the PNG of frame i, rendering the frames up to it if needed.
+func (f *replayFrames) frame(i int) ([]byte, error) {
+	for len(f.pngs) <= i {
+		if err := f.renderNext(); err != nil {
+			return nil, err
+		}
+	}
+	return f.pngs[i], nil
+}
+
+func (f *replayFrames) renderNext() error {
+	target := f.plies[len(f.pngs)]
+	opts := RenderOptions{HUDTurn: "시작"}
+	notationUCI := nchess.UCINotation{}
+	notationSAN := nchess.AlgebraicNotation{}
+	for f.applied < target {
+		mv := f.game.MovesUCI[f.applied]
+		pos := f.replay.Position()
+		move, err := notationUCI.Decode(pos, strings.ToLower(strings.TrimSpace(mv)))
+		if err != nil {
+			return fmt.Errorf("decode move %s: %w", mv, err)
+		}
+		san := notationSAN.Encode(pos, move)
+		mover := pos.Turn()
+		if err := f.replay.Move(move, nil); err != nil {
+			return fmt.Errorf("apply move %s: %w", mv, err)
+		}
+		ply := f.applied
+		if f.blackFirst {
+			ply++
+		}
+		turn := fmt.Sprintf("%d. %s", f.firstMove+ply/2, san)
+		if mover == nchess.Black {
+			turn = fmt.Sprintf("%d... %s", f.firstMove+ply/2, san)
+		}
+		// 뷰어를 상대 진영으로 두어 모든 수를 화살표로 표시
+		opts = RenderOptions{
+			HUDTurn:     turn,
+			Highlight:   &MoveHighlight{From: move.S1(), To: move.S2()},
+			ViewerColor: mover.Other(),
+		}
+		f.applied++
+	}
+	opts.HUDHeader = f.header
+	opts.Material, opts.Captured = computeMaterial(f.replay)
+	opts.Flip = f.human == nchess.Black
+	data, err := f.s.renderer.RenderPNG(f.ctx, f.replay.Position().Board(), opts)
+	if err != nil {
+		return err
+	}
+	f.pngs = append(f.pngs, data)
+	return nil
+}
+
+// encodeReplayGIF streams every frame at scale into a GIF, one decoded frame at a time,
+// and gives up with errReplayScaleTooLarge as soon as the output passes maxBytes.
+func encodeReplayGIF(ctx context.Context, frames *replayFrames, scale float64, delay, maxBytes int) ([]byte, error) {
+	var (
+		w     *gifStream
+		cache = make(map[uint32]uint8)
+		n     = frames.count()
+	)
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data, err := frames.frame(i)
+		if err != nil {
+			return nil, err
+		}
+		frame, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode replay frame: %w", err)
+		}
+		src := frame
+		if scale < 1 {
+			b := frame.Bounds()
+			dst := image.NewRGBA(image.Rect(0, 0, int(float64(b.Dx())*scale), int(float64(b.Dy())*scale)))
+			xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), frame, b, xdraw.Src, nil)
+			src = dst
+		}
+		pm := quantizeFrame(src, cache)
+		if w == nil {
+			w = newGIFStream(pm.Rect.Dx(), pm.Rect.Dy(), pm.Palette)
+		}
+		d := delay
+		if i == n-1 {
+			d *= replayFinalFrameHold
+		}
+		if err := w.writeFrame(pm, d); err != nil {
+			return nil, fmt.Errorf("encode gif: %w", err)
+		}
+		if maxBytes > 0 && w.len() > maxBytes {
+			return nil, errReplayScaleTooLarge
+		}
+	}
+	if w == nil {
+		return nil, fmt.Errorf("encode gif: no frames")
+	}
+	return w.finish(), nil
+}
+
+// gifStream writes an animated, endlessly looping GIF frame by frame with one global palette
+// (image/gif는 모든 프레임을 메모리에 모은 뒤에야 인코딩하므로 직접 작성).
+type gifStream struct {
+	buf           bytes.Buffer
+	width, height int
+}
+
+func newGIFStream(width, height int, pal color.Palette) *gifStream {
+	w := &gifStream{width: width, height: height}
+	w.buf.WriteString("GIF89a")
+	// logical screen descriptor: global colour table of 256 entries
+	w.buf.Write([]byte{byte(width), byte(width >> 8), byte(height), byte(height >> 8), 0xF7, 0, 0})
+	for i := 0; i < 256; i++ {
+		var r, g, b uint32
+		if i < len(pal) {
+			r, g, b, _ = pal[i].RGBA()
+		}
+		w.buf.Write([]byte{byte(r >> 8), byte(g >> 8), byte(b >> 8)})
+	}
+	// NETSCAPE2.0 application extension: loop forever
+	w.buf.Write([]byte{0x21, 0xFF, 0x0B})
+	w.buf.WriteString("NETSCAPE2.0")
+	w.buf.Write([]byte{0x03, 0x01, 0x00, 0x00, 0x00})
+	return w
+}
+
+func (w *gifStream) writeFrame(pm *image.Paletted, delay int) error {
+	if pm.Rect.Dx() != w.width || pm.Rect.Dy() != w.height {
+		return fmt.Errorf("frame size %v differs from %dx%d", pm.Rect.Size(), w.width, w.height)
+	}
+	// graphic control extension (delay) + image descriptor without a local colour table
+	w.buf.Write([]byte{0x21, 0xF9, 0x04, 0x00, byte(delay), byte(delay >> 8), 0x00, 0x00})
+	w.buf.Write([]byte{0x2C, 0, 0, 0, 0, byte(w.width), byte(w.width >> 8), byte(w.height), byte(w.height >> 8), 0x00})
+	w.buf.WriteByte(8) // LZW minimum code size
+	bw := &gifBlockWriter{w: &w.buf}
+	lw := lzw.NewWriter(bw, lzw.LSB, 8)
+	for y := 0; y < w.height; y++ {
+		row := pm.Pix[y*pm.Stride : y*pm.Stride+w.width]
+		if _, err := lw.Write(row); err != nil {
+			return err
+		}
+	}
+	if err := lw.Close(); err != nil {
+		return err
+	}
+	bw.flush()
+	w.buf.WriteByte(0x00) // block terminator
+	return nil
+}
+
+func (w *gifStream) len() int { return w.buf.Len() }
+
+func (w *gifStream) finish() []byte {
+	w.buf.WriteByte(0x3B)
+	return w.buf.Bytes()
+}
+
+// gifBlockWriter splits LZW output into the GIF's length-prefixed sub-blocks of up to 255 bytes.
+type gifBlockWriter struct {
+	w   *bytes.Buffer
+	blk [255]byte
+	n   int
+}
+
+func (b *gifBlockWriter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		b.blk[b.n] = c
+		b.n++
+		if b.n == len(b.blk) {
+			b.flush()
+		}
+	}
+	return len(p), nil
+}
+
+func (b *gifBlockWriter) flush() {
+	if b.n == 0 {
+		return
+	}
+	b.w.WriteByte(byte(b.n))
+	b.w.Write(b.blk[:b.n])
+	b.n = 0
+}
+
+// quantizeFrame maps a frame onto the Plan9 palette without dithering (smaller GIFs);
+// cache memoizes nearest-colour lookups since board frames reuse few colours.
+func quantizeFrame(src image.Image, cache map[uint32]uint8) *image.Paletted {
+	b := src.Bounds()
+	dst := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), palette.Plan9)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, _ := src.At(x, y).RGBA()
+			key := (r>>8)<<16 | (g>>8)<<8 | bl>>8
+			idx, ok := cache[key]
+			if !ok {
+				idx = uint8(dst.Palette.Index(color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(bl >> 8), A: 255}))
+				cache[key] = idx
+			}
+			dst.Pix[(y-b.Min.Y)*dst.Stride+(x-b.Min.X)] = idx
+		}
+	}
+	return dst
+}
@@ -20,6 +20,8 @@ type Repository interface {
 	GetGameBySession(ctx context.Context, sessionUUID string, playerHash string) (*domain.ChessGame, error)
 	GetProfile(ctx context.Context, playerHash string, roomHash string) (*domain.ChessProfile, error)
 	UpsertProfile(ctx context.Context, profile *domain.ChessProfile) error
+	SaveAnalysis(ctx context.Context, analysis *domain.GameAnalysis) error
+	GetAnalysis(ctx context.Context, gameID int64) (*domain.GameAnalysis, error)
 }
 
 type repository struct {
@@ -60,9 +62,12 @@ func (r *repository) InsertGame(ctx context.Context, game *domain.ChessGame) (in
 			ended_at,
 			duration_ms,
 			blunders,
-			engine_latency_ms
+			engine_latency_ms,
+			player_color,
+			start_fen,
+			rated
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9::jsonb, $10, $11, $12, $13, $14, $15)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9::jsonb, $10, $11, $12, $13, $14, $15, $16, NULLIF($17, ''), $18)
 		ON CONFLICT (session_uuid) DO NOTHING
 		RETURNING id`
 
@@ -85,6 +90,9 @@ func (r *repository) InsertGame(ctx context.Context, game *domain.ChessGame) (in
 		game.Duration.Milliseconds(),
 		game.Blunders,
 		game.EngineLatency.Milliseconds(),
+		playerColorOrDefault(game.PlayerColor),
+		game.StartFEN,
+		game.Rated,
 	).Scan(&id)
 	if err == sql.ErrNoRows || (err == nil && !id.Valid) {
 		return 0, ErrDuplicateGame
@@ -116,7 +124,10 @@ func (r *repository) GetRecentGames(ctx context.Context, playerHash string, limi
 			ended_at,
 			duration_ms,
 			blunders,
-			engine_latency_ms
+			engine_latency_ms,
+			player_color,
+			COALESCE(start_fen, ''),
+			rated
 		FROM chess_games
 		WHERE player_hash = $1
 		ORDER BY ended_at DESC
@@ -154,6 +165,9 @@ func (r *repository) GetRecentGames(ctx context.Context, playerHash string, limi
 			&durationMS,
 			&game.Blunders,
 			&latencyMS,
+			&game.PlayerColor,
+			&game.StartFEN,
+			&game.Rated,
 		); err != nil {
 			return nil, fmt.Errorf("scan chess game: %w", err)
 		}
@@ -192,7 +206,10 @@ func (r *repository) GetGame(ctx context.Context, id int64, playerHash string) (
 			ended_at,
 			duration_ms,
 			blunders,
-			engine_latency_ms
+			engine_latency_ms,
+			player_color,
+			COALESCE(start_fen, ''),
+			rated
 		FROM chess_games
 		WHERE id = $1 AND player_hash = $2`
 
@@ -221,6 +238,9 @@ func (r *repository) GetGame(ctx context.Context, id int64, playerHash string) (
 		&durationMS,
 		&game.Blunders,
 		&latencyMS,
+		&game.PlayerColor,
+		&game.StartFEN,
+		&game.Rated,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -262,7 +282,10 @@ func (r *repository) GetGameBySession(ctx context.Context, sessionUUID string, p
 			ended_at,
 			duration_ms,
 			blunders,
-			engine_latency_ms
+			engine_latency_ms,
+			player_color,
+			COALESCE(start_fen, ''),
+			rated
 		FROM chess_games
 		WHERE session_uuid = $1 AND player_hash = $2
 		ORDER BY ended_at DESC
@@ -293,6 +316,9 @@ func (r *repository) GetGameBySession(ctx context.Context, sessionUUID string, p
 		&durationMS,
 		&game.Blunders,
 		&latencyMS,
+		&game.PlayerColor,
+		&game.StartFEN,
+		&game.Rated,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
@@ -421,3 +447,110 @@ func (r *repository) UpsertProfile(ctx context.Context, profile *domain.ChessPro
 	}
 	return nil
 }
+
+func (r *repository) SaveAnalysis(ctx context.Context, analysis *domain.GameAnalysis) error {
+	if analysis == nil {
+		return fmt.Errorf("nil chess analysis payload")
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin analysis tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM chess_game_analysis WHERE game_id = $1`, analysis.GameID); err != nil {
+		return fmt.Errorf("clear chess analysis: %w", err)
+	}
+	const insert = `
+		INSERT INTO chess_game_analysis (
+			game_id,
+			ply,
+			move_uci,
+			best_move,
+			eval_cp,
+			cp_loss,
+			class
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	blunders := 0
+	for _, mv := range analysis.Moves {
+		if _, err := tx.ExecContext(ctx, insert, analysis.GameID, mv.Ply, mv.MoveUCI, mv.BestMove, mv.EvalCP, mv.CPLoss, mv.Class); err != nil {
+			return fmt.Errorf("insert chess analysis ply %d: %w", mv.Ply, err)
+		}
+		if mv.Class == MoveClassBlunder {
+			blunders++
+		}
+	}
+	const update = `
+		UPDATE chess_games
+		SET accuracy = $2, analysis_depth = $3, blunders = $4
+		WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, update, analysis.GameID, analysis.Accuracy, analysis.Depth, blunders); err != nil {
+		return fmt.Errorf("update chess game accuracy: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit chess analysis: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) GetAnalysis(ctx context.Context, gameID int64) (*domain.GameAnalysis, error) {
+	var (
+		accuracy sql.NullFloat64
+		depth    sql.NullInt64
+	)
+	err := r.db.QueryRowContext(ctx, `SELECT accuracy, analysis_depth FROM chess_games WHERE id = $1`, gameID).Scan(&accuracy, &depth)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select chess game accuracy: %w", err)
+	}
+	if !accuracy.Valid {
+		return nil, nil
+	}
+
+	const query = `
+		SELECT
+			ply,
+			move_uci,
+			best_move,
+			eval_cp,
+			cp_loss,
+			class
+		FROM chess_game_analysis
+		WHERE game_id = $1
+		ORDER BY ply`
+	rows, err := r.db.QueryContext(ctx, query, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("select chess analysis: %w", err)
+	}
+	defer rows.Close()
+
+	analysis := &domain.GameAnalysis{
+		GameID:   gameID,
+		Depth:    int(depth.Int64),
+		Accuracy: accuracy.Float64,
+	}
+	for rows.Next() {
+		var mv domain.MoveEvaluation
+		if err := rows.Scan(&mv.Ply, &mv.MoveUCI, &mv.BestMove, &mv.EvalCP, &mv.CPLoss, &mv.Class); err != nil {
+			return nil, fmt.Errorf("scan chess analysis: %w", err)
+		}
+		analysis.Moves = append(analysis.Moves, mv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate chess analysis: %w", err)
+	}
+	if len(analysis.Moves) == 0 {
+		return nil, nil
+	}
+	return analysis, nil
+}
+
+func playerColorOrDefault(color string) string {
+	if color == PlayerColorBlack {
+		return PlayerColorBlack
+	}
+	return PlayerColorWhite
+}
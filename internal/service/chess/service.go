@@ -2,11 +2,13 @@ package chess
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"strings"
 	"time"
 
@@ -42,6 +44,13 @@ const (
 	defaultHUDPlayerLabel           = "Player"
 )
 
+// 싱글 플레이 진영 선택값. sessionPayload.PlayerColor가 비어 있으면 백으로 간주합니다(기존 세션 호환).
+const (
+	PlayerColorWhite  = "white"
+	PlayerColorBlack  = "black"
+	PlayerColorRandom = "random"
+)
+
 var (
 	initialPieceCounts = map[nchess.Color]map[nchess.PieceType]int{
 		nchess.White: {
@@ -100,10 +109,18 @@ type Config struct {
 	HistoryLimit        int
 	AllowedRooms        []string
 	DefaultOpeningStyle string
+	// AnalysisDepth: 대국 후 분석 고정 탐색 깊이(0이면 기본값)
+	AnalysisDepth int
+	// ReplayFrameDelay/ReplayMaxBytes: 기보 GIF 프레임 간격과 최대 크기(0이면 기본값)
+	ReplayFrameDelay time.Duration
+	ReplayMaxBytes   int
+	// ReplayMaxFrames: 기보 GIF 최대 프레임 수, 더 긴 대국은 수를 건너뜀(0이면 기본값)
+	ReplayMaxFrames int
 }
 
 type Service struct {
 	engine       Evaluator
+	analyzer     Analyzer
 	cache        *cache.CacheService
 	renderer     BoardRenderer
 	repo         Repository
@@ -122,6 +139,46 @@ type sessionPayload struct {
 	StartedAt   time.Time `json:"started_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	AutoAssist  bool      `json:"auto_assist,omitempty"`
+	PlayerColor string    `json:"player_color,omitempty"`
+	// StartFEN: 지정 포지션(FEN/PGN 가져오기)에서 시작한 세션의 시작 FEN. 비어 있으면 표준 시작
+	StartFEN string `json:"start_fen,omitempty"`
+}
+
+// ParsePlayerColor maps a user token (백/흑/랜덤, white/black/random) to a PlayerColor value.
+func ParsePlayerColor(token string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(token)) {
+	case "백", "white", "w":
+		return PlayerColorWhite, true
+	case "흑", "black", "b":
+		return PlayerColorBlack, true
+	case "랜덤", "무작위", "random":
+		return PlayerColorRandom, true
+	default:
+		return "", false
+	}
+}
+
+// resolvePlayerColor turns a requested color into white/black, flipping a coin for random.
+func resolvePlayerColor(color string) string {
+	switch strings.ToLower(strings.TrimSpace(color)) {
+	case PlayerColorBlack:
+		return PlayerColorBlack
+	case PlayerColorRandom:
+		if n, _ := rand.Int(rand.Reader, big.NewInt(2)); n != nil && n.Int64() == 0 {
+			return PlayerColorBlack
+		}
+		return PlayerColorWhite
+	default:
+		return PlayerColorWhite
+	}
+}
+
+// humanColor returns the side the human plays; legacy payloads without a color are White.
+func (p *sessionPayload) humanColor() nchess.Color {
+	if p != nil && p.PlayerColor == PlayerColorBlack {
+		return nchess.Black
+	}
+	return nchess.White
 }
 
 type SessionState struct {
@@ -145,6 +202,9 @@ type SessionState struct {
 	Material      MaterialScore
 	Captured      CapturedPieces
 	AutoAssist    bool
+	PlayerColor   string
+	// StartFEN: 지정 포지션에서 시작한 세션이면 시작 FEN (레이팅 미반영)
+	StartFEN string
 }
 
 type MoveSummary struct {
@@ -331,6 +391,20 @@ func NewService(engine Evaluator, cacheSvc *cache.CacheService, repo Repository,
 	if cfg.HistoryLimit <= 0 || cfg.HistoryLimit > maxHistoryLimit {
 		cfg.HistoryLimit = 10
 	}
+	if cfg.AnalysisDepth <= 0 {
+		cfg.AnalysisDepth = defaultAnalysisDepth
+	}
+	if cfg.ReplayFrameDelay <= 0 {
+		cfg.ReplayFrameDelay = defaultReplayFrameDelay
+	}
+	if cfg.ReplayMaxBytes <= 0 {
+		cfg.ReplayMaxBytes = defaultReplayMaxBytes
+	}
+	if cfg.ReplayMaxFrames <= 0 {
+		cfg.ReplayMaxFrames = defaultReplayMaxFrames
+	}
+	// 분석은 선택 기능: 엔진이 Analyzer를 구현할 때만 활성화
+	analyzer, _ := engine.(Analyzer)
 	if logger == nil {
 		logger = zap.NewNop()
 	}
@@ -346,6 +420,7 @@ func NewService(engine Evaluator, cacheSvc *cache.CacheService, repo Repository,
 
 	return &Service{
 		engine:   engine,
+		analyzer: analyzer,
 		cache:    cacheSvc,
 		renderer: renderer,
 		repo:     repo,
@@ -355,13 +430,42 @@ func NewService(engine Evaluator, cacheSvc *cache.CacheService, repo Repository,
 			HistoryLimit:        cfg.HistoryLimit,
 			AllowedRooms:        append([]string(nil), cfg.AllowedRooms...),
 			DefaultOpeningStyle: styleKey,
+			AnalysisDepth:       cfg.AnalysisDepth,
+			ReplayFrameDelay:    cfg.ReplayFrameDelay,
+			ReplayMaxBytes:      cfg.ReplayMaxBytes,
+			ReplayMaxFrames:     cfg.ReplayMaxFrames,
 		},
 		allowedRooms: allowedRooms,
 		logger:       logger,
 	}, nil
 }
 
-func (s *Service) StartSession(ctx context.Context, meta SessionMeta, preset string, autoAssist bool) (*SessionState, error) {
+// StartSession starts (or resumes) a single-player game. color is PlayerColorWhite/Black/Random;
+// 빈 값이면 백으로 시작하며, 흑을 선택하면 엔진이 첫 수를 둡니다.
+func (s *Service) StartSession(ctx context.Context, meta SessionMeta, preset string, color string, autoAssist bool) (*SessionState, error) {
+	return s.startSession(ctx, meta, preset, color, "", autoAssist)
+}
+
+// StartSessionFromFEN starts an unrated game from an arbitrary position.
+// 진영을 지정하지 않으면 FEN의 둘 차례 쪽을 플레이어가 맡습니다.
+func (s *Service) StartSessionFromFEN(ctx context.Context, meta SessionMeta, preset string, color string, fen string) (*SessionState, error) {
+	startFEN, err := validateStartFEN(fen)
+	if err != nil {
+		return nil, err
+	}
+	return s.startSession(ctx, meta, preset, color, startFEN, false)
+}
+
+// StartSessionFromPGN starts an unrated game from the position reached at the end of a PGN.
+func (s *Service) StartSessionFromPGN(ctx context.Context, meta SessionMeta, preset string, color string, pgn string) (*SessionState, error) {
+	startFEN, err := positionFromPGN(pgn)
+	if err != nil {
+		return nil, err
+	}
+	return s.startSession(ctx, meta, preset, color, startFEN, false)
+}
+
+func (s *Service) startSession(ctx context.Context, meta SessionMeta, preset, color, startFEN string, autoAssist bool) (*SessionState, error) {
 	if err := s.ensureReady(); err != nil {
 		return nil, err
 	}
@@ -434,16 +538,38 @@ func (s *Service) StartSession(ctx context.Context, meta SessionMeta, preset str
 		StartedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 		AutoAssist:  autoAssist,
+		StartFEN:    startFEN,
+	}
+
+	game, err := newGameFrom(startFEN)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(color) == "" && startFEN != "" {
+		color = colorName(game.Position().Turn())
+	}
+	payload.PlayerColor = resolvePlayerColor(color)
+
+	var highlight *MoveHighlight
+	if game.Position().Turn() != payload.humanColor() {
+		// 엔진 차례로 시작하면(플레이어 흑 등) 엔진이 먼저 둔 뒤 세션을 저장
+		engineMove, _, engineUCI, _, err := s.playEngineMove(ctx, identity, payload, game)
+		if err != nil {
+			return nil, err
+		}
+		if engineMove != nil {
+			payload.Moves = append(payload.Moves, engineUCI)
+			highlight = &MoveHighlight{From: engineMove.S1(), To: engineMove.S2()}
+		}
 	}
 
 	if err := s.saveSession(ctx, identity.SessionID, payload); err != nil {
 		return nil, err
 	}
 
-	game := nchess.NewGame()
 	state := s.stateFromGame(payload, game)
 	s.applyPlayerName(state, payload, meta)
-	s.attachBoardImage(ctx, state, game.Position(), nil, nil)
+	s.attachBoardImage(ctx, state, game.Position(), highlight, nil)
 	state.Profile = profile
 	return state, nil
 }
@@ -525,7 +651,7 @@ func (s *Service) computeAssistSuggestion(ctx context.Context, payload *sessionP
 
 	result, err := s.engine.Evaluate(evalCtx, corechess.EvaluateRequest{
 		PresetName: "level8",
-		FEN:        "startpos",
+		FEN:        engineFEN(payload.StartFEN),
 		Moves:      append([]string(nil), payload.Moves...),
 	})
 	if err != nil {
@@ -581,7 +707,7 @@ func (s *Service) populateAutoAssist(ctx context.Context, payload *sessionPayloa
 		return
 	}
 	pos := game.Position()
-	if pos == nil || pos.Turn() != nchess.White {
+	if pos == nil || pos.Turn() != payload.humanColor() {
 		return
 	}
 	suggestion, err := s.computeAssistSuggestion(ctx, payload, game)
@@ -689,28 +815,11 @@ func (s *Service) Play(ctx context.Context, meta SessionMeta, moveInput string)
 		return summary, nil
 	}
 
-	evalTimeout := s.evaluationTimeout(payload.Preset)
-	evalCtx, cancel := context.WithTimeout(ctx, evalTimeout)
-	defer cancel()
-
-	result, err := s.engine.Evaluate(evalCtx, corechess.EvaluateRequest{
-		PresetName: payload.Preset,
-		FEN:        "startpos",
-		Moves:      payload.Moves,
-	})
+	engineMove, engineSAN, engineUCI, result, err := s.playEngineMove(ctx, identity, payload, game)
 	if err != nil {
-		s.logger.Warn("chess engine evaluation failed",
-			zap.Error(err),
-			zap.String("session_id", identity.SessionID),
-			zap.String("preset", payload.Preset),
-			zap.Int("move_count", len(payload.Moves)),
-			zap.Duration("timeout", evalTimeout),
-		)
-		return nil, mapEngineError(err)
+		return nil, err
 	}
-
-	engineMoveText := strings.ToLower(strings.TrimSpace(result.Chosen.Move))
-	if engineMoveText == "" {
+	if engineMove == nil {
 		state := s.stateFromGame(payload, game)
 		s.applyPlayerName(state, payload, meta)
 		s.attachBoardImage(ctx, state, game.Position(), nil, playerMarker)
@@ -729,20 +838,6 @@ func (s *Service) Play(ctx context.Context, meta SessionMeta, moveInput string)
 		s.populateAutoAssist(ctx, payload, game, summary)
 		return summary, nil
 	}
-	// Server-side logging only: ECO label and forced/source info for chosen engine reply
-	s.logOpeningLabel(game, engineMoveText, payload.Preset, s.cfg.DefaultOpeningStyle, result.Chosen.Forced)
-
-	posBeforeEngine := game.Position()
-	engineMove, err := notationUCI.Decode(posBeforeEngine, engineMoveText)
-	if err != nil {
-		return nil, fmt.Errorf("decode engine move: %w", err)
-	}
-	if err := game.Move(engineMove, nil); err != nil {
-		return nil, fmt.Errorf("apply engine move: %w", err)
-	}
-
-	engineSAN := notationSAN.Encode(posBeforeEngine, engineMove)
-	engineUCI := strings.ToLower(notationUCI.Encode(posBeforeEngine, engineMove))
 	payload.Moves = append(payload.Moves, engineUCI)
 
 	payload.UpdatedAt = time.Now()
@@ -776,6 +871,52 @@ func (s *Service) Play(ctx context.Context, meta SessionMeta, moveInput string)
 	return summary, nil
 }
 
+// playEngineMove asks the engine for a reply to payload.Moves and applies it to game.
+// 엔진이 둘 수가 없으면(move == nil) 에러 없이 반환하며, payload.Moves 갱신은 호출자가 담당합니다.
+func (s *Service) playEngineMove(ctx context.Context, identity sessionIdentity, payload *sessionPayload, game *nchess.Game) (*nchess.Move, string, string, corechess.EvaluateResult, error) {
+	evalTimeout := s.evaluationTimeout(payload.Preset)
+	evalCtx, cancel := context.WithTimeout(ctx, evalTimeout)
+	defer cancel()
+
+	result, err := s.engine.Evaluate(evalCtx, corechess.EvaluateRequest{
+		PresetName: payload.Preset,
+		FEN:        engineFEN(payload.StartFEN),
+		Moves:      payload.Moves,
+	})
+	if err != nil {
+		s.logger.Warn("chess engine evaluation failed",
+			zap.Error(err),
+			zap.String("session_id", identity.SessionID),
+			zap.String("preset", payload.Preset),
+			zap.Int("move_count", len(payload.Moves)),
+			zap.Duration("timeout", evalTimeout),
+		)
+		return nil, "", "", result, mapEngineError(err)
+	}
+
+	engineMoveText := strings.ToLower(strings.TrimSpace(result.Chosen.Move))
+	if engineMoveText == "" {
+		return nil, "", "", result, nil
+	}
+	// Server-side logging only: ECO label and forced/source info for chosen engine reply
+	s.logOpeningLabel(game, engineMoveText, payload.Preset, s.cfg.DefaultOpeningStyle, result.Chosen.Forced)
+
+	notationSAN := nchess.AlgebraicNotation{}
+	notationUCI := nchess.UCINotation{}
+	posBeforeEngine := game.Position()
+	engineMove, err := notationUCI.Decode(posBeforeEngine, engineMoveText)
+	if err != nil {
+		return nil, "", "", result, fmt.Errorf("decode engine move: %w", err)
+	}
+	if err := game.Move(engineMove, nil); err != nil {
+		return nil, "", "", result, fmt.Errorf("apply engine move: %w", err)
+	}
+
+	engineSAN := notationSAN.Encode(posBeforeEngine, engineMove)
+	engineUCI := strings.ToLower(notationUCI.Encode(posBeforeEngine, engineMove))
+	return engineMove, engineSAN, engineUCI, result, nil
+}
+
 func mapEngineError(err error) error {
 	if err == nil {
 		return ErrEngineUnavailable
@@ -875,7 +1016,7 @@ func (s *Service) Resign(ctx context.Context, meta SessionMeta) (*SessionState,
 	if err != nil {
 		return nil, err
 	}
-	game.Resign(nchess.White)
+	game.Resign(payload.humanColor())
 	payload.UpdatedAt = time.Now()
 
 	state := s.stateFromGame(payload, game)
@@ -1112,7 +1253,10 @@ func (s *Service) deleteSession(ctx context.Context, sessionID string) error {
 }
 
 func replaySession(payload *sessionPayload) (*nchess.Game, error) {
-	game := nchess.NewGame()
+	game, err := newGameFrom(payload.StartFEN)
+	if err != nil {
+		return nil, err
+	}
 	notation := nchess.UCINotation{}
 	for _, mv := range payload.Moves {
 		move, err := notation.Decode(game.Position(), strings.ToLower(strings.TrimSpace(mv)))
@@ -1155,6 +1299,8 @@ func (s *Service) stateFromGame(payload *sessionPayload, game *nchess.Game) *Ses
 		StartedAt:     payload.StartedAt,
 		UpdatedAt:     payload.UpdatedAt,
 		AutoAssist:    payload.AutoAssist,
+		PlayerColor:   colorName(payload.humanColor()),
+		StartFEN:      payload.StartFEN,
 	}
 	state.Material, state.Captured = computeMaterial(game)
 	return state
@@ -1243,6 +1389,11 @@ func (s *Service) attachBoardImage(ctx context.Context, state *SessionState, pos
 		HUDHeader: hudHeader,
 		HUDTurn:   hudTurn,
 	}
+	if state.PlayerColor == PlayerColorBlack {
+		// 흑 플레이어는 흑 시점으로 렌더링(내 수=사각형, 엔진 수=화살표)
+		opts.Flip = true
+		opts.ViewerColor = nchess.Black
+	}
 	data, err := s.renderer.RenderPNG(ctx, position.Board(), opts)
 	if err != nil {
 		s.logger.Warn("failed to render chess board image", zap.Error(err))
@@ -1378,7 +1529,7 @@ func computeMaterial(game *nchess.Game) (MaterialScore, CapturedPieces) {
 }
 
 func (s *Service) persistFinishedGame(ctx context.Context, identity sessionIdentity, payload *sessionPayload, game *nchess.Game, engineResult corechess.EvaluateResult) (int64, *domain.ChessProfile, int, error) {
-	result := resultFromOutcome(game.Outcome())
+	result := resultFromOutcome(game.Outcome(), payload.humanColor())
 	method := methodFromOutcome(game.Method())
 	now := time.Now()
 
@@ -1398,6 +1549,9 @@ func (s *Service) persistFinishedGame(ctx context.Context, identity sessionIdent
 		Duration:      now.Sub(payload.StartedAt),
 		Blunders:      boolToInt(engineResult.Blunder),
 		EngineLatency: engineResult.Duration,
+		PlayerColor:   colorName(payload.humanColor()),
+		StartFEN:      payload.StartFEN,
+		Rated:         payload.StartFEN == "",
 	}
 
 	if gameRecord.EnginePreset == "" {
@@ -1426,7 +1580,7 @@ func (s *Service) persistFinishedGame(ctx context.Context, identity sessionIdent
 	if err != nil && !errors.Is(err, ErrProfileNotFound) {
 		return gameID, nil, 0, err
 	}
-	profile, delta := applyGameResult(profile, identity, payload.Preset, game.Outcome(), now)
+	profile, delta := applyGameResult(profile, identity, payload.Preset, game.Outcome(), payload.humanColor(), gameRecord.Rated, now)
 
 	if err := s.repo.UpsertProfile(ctx, profile); err != nil {
 		return gameID, nil, 0, err
@@ -1509,24 +1663,42 @@ func boolToInt(v bool) int {
 	return 0
 }
 
-func resultFromOutcome(outcome nchess.Outcome) string {
+// resultFromOutcome maps a game outcome to win/loss/draw from the human's side.
+func resultFromOutcome(outcome nchess.Outcome, human nchess.Color) string {
 	switch outcome {
-	case nchess.WhiteWon:
-		return "win"
-	case nchess.BlackWon:
-		return "loss"
 	case nchess.Draw:
 		return "draw"
+	case nchess.WhiteWon, nchess.BlackWon:
+		if outcome == winningOutcome(human) {
+			return "win"
+		}
+		return "loss"
 	default:
 		return "unknown"
 	}
 }
 
+func winningOutcome(color nchess.Color) nchess.Outcome {
+	if color == nchess.Black {
+		return nchess.BlackWon
+	}
+	return nchess.WhiteWon
+}
+
+func colorName(color nchess.Color) string {
+	if color == nchess.Black {
+		return PlayerColorBlack
+	}
+	return PlayerColorWhite
+}
+
 func methodFromOutcome(method nchess.Method) string {
 	return strings.ToLower(method.String())
 }
 
-func applyGameResult(profile *domain.ChessProfile, identity sessionIdentity, preset string, outcome nchess.Outcome, endedAt time.Time) (*domain.ChessProfile, int) {
+// applyGameResult updates record, streak and rating; unrated games (custom start positions)
+// only touch the last-played fields.
+func applyGameResult(profile *domain.ChessProfile, identity sessionIdentity, preset string, outcome nchess.Outcome, human nchess.Color, rated bool, endedAt time.Time) (*domain.ChessProfile, int) {
 	if profile == nil {
 		profile = &domain.ChessProfile{
 			PlayerHash: identity.PlayerHash,
@@ -1538,19 +1710,22 @@ func applyGameResult(profile *domain.ChessProfile, identity sessionIdentity, pre
 
 	prevRating := profile.Rating
 
-	profile.GamesPlayed++
 	profile.LastPreset = preset
 	profile.LastPlayedAt = endedAt
 	profile.UpdatedAt = endedAt
+	if !rated {
+		return profile, 0
+	}
+	profile.GamesPlayed++
 
 	resultType := ""
 	var score float64
-	switch outcome {
-	case nchess.WhiteWon:
+	switch resultFromOutcome(outcome, human) {
+	case "win":
 		profile.Wins++
 		resultType = "win"
 		score = 1.0
-	case nchess.BlackWon:
+	case "loss":
 		profile.Losses++
 		resultType = "loss"
 		score = 0.0
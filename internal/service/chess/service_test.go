@@ -0,0 +1,264 @@
+package chess
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/gif"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	nchess "github.com/corentings/chess/v2"
+	corechess "github.com/park285/Cheese-KakaoTalk-bot/internal/chess"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/domain"
+	"go.uber.org/zap"
+)
+
+func TestResultFromOutcome_RespectsPlayerColor(t *testing.T) {
+	cases := []struct {
+		outcome nchess.Outcome
+		human   nchess.Color
+		want    string
+	}{
+		{nchess.WhiteWon, nchess.White, "win"},
+		{nchess.BlackWon, nchess.White, "loss"},
+		{nchess.WhiteWon, nchess.Black, "loss"},
+		{nchess.BlackWon, nchess.Black, "win"},
+		{nchess.Draw, nchess.Black, "draw"},
+	}
+	for _, c := range cases {
+		if got := resultFromOutcome(c.outcome, c.human); got != c.want {
+			t.Fatalf("resultFromOutcome(%s, %s) = %q, want %q", c.outcome, c.human, got, c.want)
+		}
+	}
+}
+
+func TestApplyGameResult_BlackWinRaisesRating(t *testing.T) {
+	id := sessionIdentity{PlayerHash: "p", RoomHash: "r"}
+	profile, delta := applyGameResult(nil, id, "level5", nchess.BlackWon, nchess.Black, true, time.Now())
+	if profile.Wins != 1 || profile.Losses != 0 || delta <= 0 {
+		t.Fatalf("expected a win with positive delta, got wins=%d losses=%d delta=%d", profile.Wins, profile.Losses, delta)
+	}
+	profile, delta = applyGameResult(nil, id, "level5", nchess.WhiteWon, nchess.Black, true, time.Now())
+	if profile.Losses != 1 || delta >= 0 {
+		t.Fatalf("expected a loss with negative delta, got losses=%d delta=%d", profile.Losses, delta)
+	}
+}
+
+func TestParsePlayerColor(t *testing.T) {
+	for token, want := range map[string]string{"흑": PlayerColorBlack, "백": PlayerColorWhite, "랜덤": PlayerColorRandom, "Black": PlayerColorBlack} {
+		got, ok := ParsePlayerColor(token)
+		if !ok || got != want {
+			t.Fatalf("ParsePlayerColor(%q) = %q,%v want %q", token, got, ok, want)
+		}
+	}
+	if _, ok := ParsePlayerColor("level5"); ok {
+		t.Fatalf("preset token must not parse as a color")
+	}
+	if c := resolvePlayerColor(PlayerColorRandom); c != PlayerColorWhite && c != PlayerColorBlack {
+		t.Fatalf("random must resolve to white or black, got %q", c)
+	}
+}
+
+func TestBuildGameAnalysis_ClassifiesOnlyPlayerMoves(t *testing.T) {
+	moves := []string{"e2e4", "e7e5", "d1h5", "b8c6"}
+	evals := []corechess.PositionEval{
+		{BestMove: "e2e4", EvalCP: 30},
+		{BestMove: "e7e5", EvalCP: 30},
+		{BestMove: "g1f3", EvalCP: 30},
+		{BestMove: "b8c6", EvalCP: -250}, // 백의 Qh5로 280cp 손실(mistake)
+		{BestMove: "f1c4", EvalCP: -240},
+	}
+	a, err := buildGameAnalysis(7, moves, evals, nchess.White, nchess.White, nchess.NoOutcome, 12)
+	if err != nil {
+		t.Fatalf("buildGameAnalysis: %v", err)
+	}
+	if len(a.Moves) != 4 || a.Depth != 12 || a.GameID != 7 {
+		t.Fatalf("unexpected analysis shape: %+v", a)
+	}
+	if a.Moves[0].Class != MoveClassBest || a.Moves[2].Class != MoveClassMistake {
+		t.Fatalf("unexpected classes: %q %q", a.Moves[0].Class, a.Moves[2].Class)
+	}
+	if a.Moves[1].Class != "" || a.Moves[3].Class != "" {
+		t.Fatalf("engine moves must not be classified: %q %q", a.Moves[1].Class, a.Moves[3].Class)
+	}
+	if a.Moves[2].CPLoss != 280 || a.Accuracy <= 0 || a.Accuracy >= 100 {
+		t.Fatalf("unexpected loss/accuracy: loss=%d acc=%.1f", a.Moves[2].CPLoss, a.Accuracy)
+	}
+
+	if _, err := buildGameAnalysis(7, moves, evals[:3], nchess.White, nchess.White, nchess.NoOutcome, 12); err == nil {
+		t.Fatalf("expected length mismatch error")
+	}
+}
+
+func TestBuildGameAnalysis_TerminalPositionUsesOutcome(t *testing.T) {
+	// 바보의 메이트: 흑(플레이어)의 마지막 수는 최선으로 분류되어야 함
+	moves := []string{"f2f3", "e7e5", "g2g4", "d8h4"}
+	evals := []corechess.PositionEval{
+		{BestMove: "e2e4", EvalCP: 30},
+		{BestMove: "e7e5", EvalCP: -60},
+		{BestMove: "e2e4", EvalCP: -70},
+		{BestMove: "d8h4", EvalCP: -corechess.MateScoreCP},
+		{},
+	}
+	a, err := buildGameAnalysis(1, moves, evals, nchess.Black, nchess.White, nchess.BlackWon, 10)
+	if err != nil {
+		t.Fatalf("buildGameAnalysis: %v", err)
+	}
+	last := a.Moves[3]
+	if last.EvalCP != -corechess.MateScoreCP || last.Class != MoveClassBest || last.CPLoss != 0 {
+		t.Fatalf("unexpected final move verdict: %+v", last)
+	}
+}
+
+func TestRenderEvalGraphPNG_ClampsMateAndDecodes(t *testing.T) {
+	analysis := &domain.GameAnalysis{GameID: 3, Depth: 10, Moves: []domain.MoveEvaluation{
+		{Ply: 1, EvalCP: 30},
+		{Ply: 2, EvalCP: -40},
+		{Ply: 3, EvalCP: -420, Class: MoveClassBlunder},
+		{Ply: 4, EvalCP: -corechess.MateScoreCP},
+	}}
+	r := &svgBoardRenderer{}
+	data, err := r.RenderEvalGraphPNG(context.Background(), analysis, EvalGraphOptions{Title: "평가 그래프 #3"})
+	if err != nil {
+		t.Fatalf("RenderEvalGraphPNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 960 || b.Dy() != 480 {
+		t.Fatalf("unexpected graph size: %v", b)
+	}
+	if _, err := r.RenderEvalGraphPNG(context.Background(), &domain.GameAnalysis{}, EvalGraphOptions{}); err == nil {
+		t.Fatalf("expected error for empty analysis")
+	}
+}
+
+func TestReplay_OneFramePerPlyAndSizeCap(t *testing.T) {
+	s := &Service{
+		renderer: &svgBoardRenderer{},
+		cfg:      Config{ReplayFrameDelay: 500 * time.Millisecond, ReplayMaxBytes: 8 << 20},
+		logger:   zap.NewNop(),
+	}
+	game := &domain.ChessGame{ID: 9, PlayerColor: PlayerColorBlack, MovesUCI: []string{"f2f3", "e7e5", "g2g4", "d8h4"}}
+
+	data, err := s.Replay(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode gif: %v", err)
+	}
+	if len(anim.Image) != len(game.MovesUCI)+1 {
+		t.Fatalf("expected %d frames, got %d", len(game.MovesUCI)+1, len(anim.Image))
+	}
+	if anim.Delay[0] != 50 || anim.Delay[len(anim.Delay)-1] != 50*replayFinalFrameHold {
+		t.Fatalf("unexpected delays: %v", anim.Delay)
+	}
+
+	s.cfg.ReplayMaxBytes = 1024
+	if _, err := s.Replay(context.Background(), game); !errors.Is(err, ErrReplayTooLarge) {
+		t.Fatalf("expected ErrReplayTooLarge, got %v", err)
+	}
+}
+
+func TestReplay_LongGameIsSampled(t *testing.T) {
+	if got := replayPlies(3, 80); len(got) != 4 || got[3] != 3 {
+		t.Fatalf("short game plies = %v", got)
+	}
+	plies := replayPlies(200, 80)
+	if len(plies) > 80 || plies[0] != 0 || plies[len(plies)-1] != 200 {
+		t.Fatalf("long game plies = %v", plies)
+	}
+
+	s := &Service{
+		renderer: &svgBoardRenderer{},
+		cfg:      Config{ReplayFrameDelay: 500 * time.Millisecond, ReplayMaxBytes: 8 << 20, ReplayMaxFrames: 4},
+		logger:   zap.NewNop(),
+	}
+	// 기사 왕복 16수: 국면 반복이지만 기보로는 유효
+	moves := make([]string, 0, 16)
+	for i := 0; i < 4; i++ {
+		moves = append(moves, "g1f3", "g8f6", "f3g1", "f6g8")
+	}
+	data, err := s.Replay(context.Background(), &domain.ChessGame{ID: 10, MovesUCI: moves})
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode gif: %v", err)
+	}
+	if len(anim.Image) > 4 {
+		t.Fatalf("expected at most 4 frames, got %d", len(anim.Image))
+	}
+}
+
+func TestApplyGameResult_UnratedKeepsRatingAndRecord(t *testing.T) {
+	id := sessionIdentity{PlayerHash: "p", RoomHash: "r"}
+	profile, delta := applyGameResult(nil, id, "level4", nchess.WhiteWon, nchess.White, false, time.Now())
+	if delta != 0 || profile.Rating != defaultPlayerRating {
+		t.Fatalf("unrated game changed rating: delta=%d rating=%d", delta, profile.Rating)
+	}
+	if profile.GamesPlayed != 0 || profile.Wins != 0 || profile.LastPreset != "level4" {
+		t.Fatalf("unexpected unrated profile: %+v", profile)
+	}
+}
+
+func TestValidateStartFEN(t *testing.T) {
+	valid, err := validateStartFEN("  4k3/8/8/8/8/8/4R3/4K3   b - - 0 1 ")
+	if err != nil || valid != "4k3/8/8/8/8/8/4R3/4K3 b - - 0 1" {
+		t.Fatalf("expected normalized FEN, got %q err=%v", valid, err)
+	}
+	for _, fen := range []string{
+		"not a fen",
+		"8/8/8/8/8/8/8/4K3 w - - 0 1",     // 흑 킹 없음
+		"4k3/8/8/8/8/8/4R3/4K3 w - - 0 1", // 둘 차례가 아닌 흑이 체크
+		"7k/5Q2/6K1/8/8/8/8/8 b - - 0 1",  // 스테일메이트
+	} {
+		if _, err := validateStartFEN(fen); !errors.Is(err, ErrInvalidPosition) {
+			t.Fatalf("expected ErrInvalidPosition for %q, got %v", fen, err)
+		}
+	}
+}
+
+func TestPositionFromPGN_StartsFromFinalPosition(t *testing.T) {
+	fen, err := positionFromPGN(`[Event "casual"] 1. e4 e5 2. Nf3 Nc6 *`)
+	if err != nil {
+		t.Fatalf("positionFromPGN: %v", err)
+	}
+	game, err := replaySession(&sessionPayload{StartFEN: fen, Moves: []string{"f1b5"}})
+	if err != nil {
+		t.Fatalf("replaySession: %v", err)
+	}
+	if game.Positions()[0].String() != fen || len(game.Moves()) != 1 {
+		t.Fatalf("replay did not start from imported position: %s", game.FEN())
+	}
+	if !strings.Contains(game.String(), fen) {
+		t.Fatalf("PGN export missing start FEN tag:\n%s", game.String())
+	}
+	if _, err := positionFromPGN("1. f3 e5 2. g4 Qh4#"); !errors.Is(err, ErrInvalidPosition) {
+		t.Fatalf("expected finished PGN to be rejected, got %v", err)
+	}
+}
+
+func TestBuildGameAnalysis_BlackToMoveStart(t *testing.T) {
+	// 흑 차례 시작: 첫 ply가 흑의 수
+	moves := []string{"e8d8", "e2e7"}
+	evals := []corechess.PositionEval{
+		{BestMove: "e8d8", EvalCP: 500},
+		{BestMove: "e2e7", EvalCP: 500},
+		{BestMove: "d8c8", EvalCP: 900},
+	}
+	a, err := buildGameAnalysis(2, moves, evals, nchess.Black, nchess.Black, nchess.NoOutcome, 10)
+	if err != nil {
+		t.Fatalf("buildGameAnalysis: %v", err)
+	}
+	if a.Moves[0].Class != MoveClassBest || a.Moves[1].Class != "" {
+		t.Fatalf("expected only the first (black) ply classified: %+v", a.Moves)
+	}
+}
@@ -0,0 +1,403 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/obslog"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	"go.uber.org/zap"
+)
+
+// maxRounds bounds the organizer-supplied Swiss round count.
+const maxRounds = 20
+
+// Manager runs room tournaments on top of pvpchess games.
+// 라운드마다 대진을 만들고 대국을 생성하며, pvpchess.Manager.OnResult로 결과를 받아 다음 라운드로 진행합니다.
+type Manager struct {
+	store Store
+	pvp   *pvpchess.Manager
+}
+
+func NewManager(store Store, pvp *pvpchess.Manager) *Manager {
+	return &Manager{store: store, pvp: pvp}
+}
+
+// Create opens registration for a new tournament in room. rounds는 스위스에서만 쓰이며 0이면 참가자 수로 정합니다.
+func (m *Manager) Create(ctx context.Context, room, userID, userName, name string, format Format, rounds int) (*Tournament, error) {
+	room, userID = strings.TrimSpace(room), strings.TrimSpace(userID)
+	if room == "" || userID == "" {
+		return nil, fmt.Errorf("invalid arguments")
+	}
+	if rounds < 0 || rounds > maxRounds {
+		return nil, ErrInvalidRounds
+	}
+	if cur, err := m.store.LatestTournament(ctx, room); err != nil {
+		return nil, err
+	} else if cur.Active() {
+		return nil, ErrTournamentExists
+	}
+	if strings.TrimSpace(name) == "" {
+		name = time.Now().Format("01/02") + " 토너먼트"
+	}
+	if format != FormatSwiss {
+		format, rounds = FormatRoundRobin, 0
+	}
+	t := &Tournament{
+		Room:        room,
+		Name:        strings.TrimSpace(name),
+		Format:      format,
+		Status:      StatusRegistering,
+		Rounds:      rounds,
+		CreatorID:   userID,
+		CreatorName: strings.TrimSpace(userName),
+		CreatedAt:   time.Now(),
+	}
+	if err := m.store.CreateTournament(ctx, t); err != nil {
+		return nil, err
+	}
+	obslog.L().Info("tournament_create", zap.Int64("tournament_id", t.ID), zap.String("room", room), zap.String("format", string(format)), zap.String("creator_id", userID))
+	return t, nil
+}
+
+// Join registers the user for the room's tournament, seeding by current PvP rating.
+func (m *Manager) Join(ctx context.Context, room, userID, userName string) (*Tournament, int, error) {
+	t, err := m.active(ctx, room)
+	if err != nil {
+		return nil, 0, err
+	}
+	if t.Status != StatusRegistering {
+		return nil, 0, ErrRegistrationClosed
+	}
+	rating := pvpchess.DefaultRating
+	if r, err := m.pvp.Rating(ctx, userID); err == nil {
+		rating = r.Rating
+	}
+	ok, err := m.store.AddPlayer(ctx, Player{TournamentID: t.ID, UserID: strings.TrimSpace(userID), Name: strings.TrimSpace(userName), Rating: rating, JoinedAt: time.Now()})
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return nil, 0, ErrAlreadyRegistered
+	}
+	players, err := m.store.Players(ctx, t.ID)
+	return t, len(players), err
+}
+
+// Leave withdraws the user while registration is still open.
+func (m *Manager) Leave(ctx context.Context, room, userID string) (*Tournament, int, error) {
+	t, err := m.active(ctx, room)
+	if err != nil {
+		return nil, 0, err
+	}
+	if t.Status != StatusRegistering {
+		return nil, 0, ErrRegistrationClosed
+	}
+	ok, err := m.store.RemovePlayer(ctx, t.ID, strings.TrimSpace(userID))
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return nil, 0, ErrNotRegistered
+	}
+	players, err := m.store.Players(ctx, t.ID)
+	return t, len(players), err
+}
+
+// Start closes registration and pairs round 1 (organizer only).
+// 이미 진행 중이면 대기 중인 대국(다른 대국 진행으로 보류된 판)을 다시 시도하고 현재 라운드를 반환합니다.
+func (m *Manager) Start(ctx context.Context, room, userID string) (*Event, error) {
+	t, err := m.active(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(t.CreatorID) != strings.TrimSpace(userID) {
+		return nil, ErrNotOrganizer
+	}
+	players, err := m.store.Players(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status == StatusRunning {
+		pairings, err := m.store.Pairings(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		cur := roundOf(pairings, t.CurrentRound)
+		games := m.startGames(ctx, t, players, cur)
+		return &Event{Kind: EventRoundStarted, Tournament: t, Round: t.CurrentRound, Pairings: cur, Players: playerNames(players), Games: games}, nil
+	}
+	if len(players) < 2 {
+		return nil, ErrTooFewPlayers
+	}
+	next := *t
+	next.Status = StatusRunning
+	next.CurrentRound = 1
+	next.StartedAt = time.Now()
+	switch t.Format {
+	case FormatSwiss:
+		if next.Rounds == 0 {
+			next.Rounds = defaultSwissRounds(len(players))
+		}
+	default:
+		next.Rounds = roundRobinRounds(len(players))
+	}
+	ok, err := m.store.TransitionTournament(ctx, &next, StatusRegistering, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrRegistrationClosed
+	}
+	obslog.L().Info("tournament_start", zap.Int64("tournament_id", t.ID), zap.Int("players", len(players)), zap.Int("rounds", next.Rounds))
+	return m.openRound(ctx, &next, players, nil)
+}
+
+// Cancel ends the tournament without a result (organizer only). 이미 시작된 대국은 일반 PvP로 계속됩니다.
+func (m *Manager) Cancel(ctx context.Context, room, userID string) (*Tournament, error) {
+	t, err := m.active(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(t.CreatorID) != strings.TrimSpace(userID) {
+		return nil, ErrNotOrganizer
+	}
+	next := *t
+	next.Status = StatusCancelled
+	next.FinishedAt = time.Now()
+	ok, err := m.store.TransitionTournament(ctx, &next, t.Status, t.CurrentRound)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoTournament
+	}
+	obslog.L().Info("tournament_cancel", zap.Int64("tournament_id", t.ID), zap.String("room", t.Room))
+	return &next, nil
+}
+
+// Standings returns the standings of the room's latest tournament (finished ones included).
+func (m *Manager) Standings(ctx context.Context, room string) (*Tournament, []Player, []Standing, error) {
+	t, err := m.store.LatestTournament(ctx, strings.TrimSpace(room))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if t == nil {
+		return nil, nil, nil, ErrNoTournament
+	}
+	players, err := m.store.Players(ctx, t.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pairings, err := m.store.Pairings(ctx, t.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return t, players, ComputeStandings(players, pairings, byePoints(t.Format)), nil
+}
+
+// RoundPairings returns the pairings of the tournament's current round.
+func (m *Manager) RoundPairings(ctx context.Context, t *Tournament) ([]Pairing, error) {
+	pairings, err := m.store.Pairings(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	return roundOf(pairings, t.CurrentRound), nil
+}
+
+// IngestResult records a finished pvpchess game that belongs to a tournament pairing.
+// 라운드가 모두 끝나면 다음 라운드를 열거나 대회를 종료하고, 보류된 대국이 시작되면 그 이벤트를 반환합니다.
+// 토너먼트 대국이 아니거나 이미 반영된 결과면 nil을 반환합니다.
+func (m *Manager) IngestResult(ctx context.Context, g *pvpchess.Game) (*Event, error) {
+	res := gameResult(g)
+	if res == ResultPending {
+		return nil, nil
+	}
+	p, ok, err := m.store.RecordResult(ctx, g.ID, res)
+	if err != nil || !ok {
+		return nil, err
+	}
+	obslog.L().Info("tournament_result", zap.Int64("tournament_id", p.TournamentID), zap.Int("round", p.Round), zap.Int("board", p.Board), zap.String("game_id", g.ID), zap.String("result", string(res)))
+	t, err := m.store.GetTournament(ctx, p.TournamentID)
+	if err != nil || t == nil || t.Status != StatusRunning {
+		return nil, err
+	}
+	return m.advance(ctx, t)
+}
+
+// advance starts deferred games of the current round, or moves on once every board is decided.
+func (m *Manager) advance(ctx context.Context, t *Tournament) (*Event, error) {
+	players, err := m.store.Players(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	pairings, err := m.store.Pairings(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	cur := roundOf(pairings, t.CurrentRound)
+	done := true
+	for _, p := range cur {
+		if p.Result == ResultPending {
+			done = false
+			break
+		}
+	}
+	if !done {
+		games := m.startGames(ctx, t, players, cur)
+		if len(games) == 0 {
+			return nil, nil
+		}
+		return &Event{Kind: EventRoundStarted, Tournament: t, Round: t.CurrentRound, Pairings: cur, Players: playerNames(players), Games: games}, nil
+	}
+
+	next := *t
+	if t.CurrentRound >= t.Rounds {
+		next.Status = StatusFinished
+		next.FinishedAt = time.Now()
+		ok, err := m.store.TransitionTournament(ctx, &next, StatusRunning, t.CurrentRound)
+		if err != nil || !ok {
+			return nil, err
+		}
+		obslog.L().Info("tournament_finish", zap.Int64("tournament_id", t.ID), zap.Int("rounds", t.Rounds))
+		return &Event{Kind: EventFinished, Tournament: &next, Round: t.CurrentRound, Players: playerNames(players), Standings: ComputeStandings(players, pairings, byePoints(t.Format))}, nil
+	}
+	next.CurrentRound++
+	ok, err := m.store.TransitionTournament(ctx, &next, StatusRunning, t.CurrentRound)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return m.openRound(ctx, &next, players, pairings)
+}
+
+// openRound pairs t.CurrentRound, stores the pairings and creates their games.
+func (m *Manager) openRound(ctx context.Context, t *Tournament, players []Player, history []Pairing) (*Event, error) {
+	var pairings []Pairing
+	standings := ComputeStandings(players, history, byePoints(t.Format))
+	if t.Format == FormatSwiss {
+		pairings = swissPairings(standings, history, t.CurrentRound)
+	} else {
+		pairings = roundRobinPairings(seedOrder(players), t.CurrentRound)
+	}
+	for i := range pairings {
+		pairings[i].TournamentID = t.ID
+	}
+	if err := m.store.SavePairings(ctx, pairings); err != nil {
+		return nil, err
+	}
+	obslog.L().Info("tournament_round", zap.Int64("tournament_id", t.ID), zap.Int("round", t.CurrentRound), zap.Int("boards", len(pairings)))
+	games := m.startGames(ctx, t, players, pairings)
+	return &Event{Kind: EventRoundStarted, Tournament: t, Round: t.CurrentRound, Pairings: pairings, Players: playerNames(players), Standings: standings, Games: games}, nil
+}
+
+// startGames creates pvpchess games for undecided boards without one, updating pairings in place.
+// 같은 방에서 이미 다른 대국 중인 선수가 있으면 그 판은 보류하고 이후 결과 반영 시 다시 시도합니다.
+func (m *Manager) startGames(ctx context.Context, t *Tournament, players []Player, pairings []Pairing) []*pvpchess.Game {
+	names := playerNames(players)
+	var out []*pvpchess.Game
+	for i := range pairings {
+		p := &pairings[i]
+		if p.IsBye() || p.GameID != "" || p.Result != ResultPending {
+			continue
+		}
+		if m.busy(ctx, p.WhiteID, t.Room) || m.busy(ctx, p.BlackID, t.Room) {
+			continue
+		}
+		g, err := m.pvp.CreateGameFromChallenge(ctx, t.Room, t.Room, p.WhiteID, names[p.WhiteID], p.BlackID, names[p.BlackID], "white", "")
+		if err != nil {
+			obslog.L().Warn("tournament_game_create_error", zap.Int64("tournament_id", t.ID), zap.Int("round", p.Round), zap.Int("board", p.Board), zap.Error(err))
+			continue
+		}
+		if err := m.store.SetPairingGame(ctx, t.ID, p.Round, p.Board, g.ID); err != nil {
+			obslog.L().Warn("tournament_pairing_bind_error", zap.Int64("tournament_id", t.ID), zap.String("game_id", g.ID), zap.Error(err))
+			continue
+		}
+		p.GameID = g.ID
+		out = append(out, g)
+	}
+	return out
+}
+
+func (m *Manager) busy(ctx context.Context, userID, room string) bool {
+	g, _ := m.pvp.GetActiveGameByUserInRoom(ctx, userID, room)
+	return g != nil
+}
+
+// active returns the room's tournament that is registering or running.
+func (m *Manager) active(ctx context.Context, room string) (*Tournament, error) {
+	t, err := m.store.LatestTournament(ctx, strings.TrimSpace(room))
+	if err != nil {
+		return nil, err
+	}
+	if !t.Active() {
+		return nil, ErrNoTournament
+	}
+	return t, nil
+}
+
+// gameResult maps a final pvpchess game to a pairing result.
+// 중단(ABORTED)된 대국은 차례였던 쪽이 방치한 것으로 보고 상대 승리로 처리합니다.
+func gameResult(g *pvpchess.Game) Result {
+	if g == nil {
+		return ResultPending
+	}
+	switch {
+	case g.Status == pvpchess.StatusAborted:
+		if g.Turn == pvpchess.White {
+			return ResultBlackWin
+		}
+		return ResultWhiteWin
+	case g.Winner != "" && g.Winner == g.WhiteID, g.Outcome == "white":
+		return ResultWhiteWin
+	case g.Winner != "" && g.Winner == g.BlackID, g.Outcome == "black":
+		return ResultBlackWin
+	case g.Status == pvpchess.StatusDraw, g.Outcome == "draw":
+		return ResultDraw
+	}
+	return ResultPending
+}
+
+// byePoints: 스위스는 부전승 1점, 풀리그는 쉬는 라운드라 0점.
+func byePoints(f Format) float64 {
+	if f == FormatSwiss {
+		return 1
+	}
+	return 0
+}
+
+// seedOrder sorts players by seed rating (then registration time) for round-robin tables.
+func seedOrder(players []Player) []string {
+	ps := append([]Player(nil), players...)
+	sort.SliceStable(ps, func(i, j int) bool {
+		if ps[i].Rating != ps[j].Rating {
+			return ps[i].Rating > ps[j].Rating
+		}
+		return ps[i].JoinedAt.Before(ps[j].JoinedAt)
+	})
+	ids := make([]string, len(ps))
+	for i, p := range ps {
+		ids[i] = p.UserID
+	}
+	return ids
+}
+
+func roundOf(pairings []Pairing, round int) []Pairing {
+	var out []Pairing
+	for _, p := range pairings {
+		if p.Round == round {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func playerNames(players []Player) map[string]string {
+	out := make(map[string]string, len(players))
+	for _, p := range players {
+		out[p.UserID] = p.Name
+	}
+	return out
+}
@@ -0,0 +1,25 @@
+package tournament
+
+import (
+	"testing"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+)
+
+func TestGameResultMapping(t *testing.T) {
+	cases := []struct {
+		g    pvpchess.Game
+		want Result
+	}{
+		{pvpchess.Game{WhiteID: "w", BlackID: "b", Status: pvpchess.StatusFinished, Outcome: "white"}, ResultWhiteWin},
+		{pvpchess.Game{WhiteID: "w", BlackID: "b", Status: pvpchess.StatusResigned, Outcome: "resign", Winner: "b"}, ResultBlackWin},
+		{pvpchess.Game{WhiteID: "w", BlackID: "b", Status: pvpchess.StatusDraw, Outcome: "draw"}, ResultDraw},
+		{pvpchess.Game{WhiteID: "w", BlackID: "b", Status: pvpchess.StatusAborted, Outcome: "aborted", Turn: pvpchess.White}, ResultBlackWin},
+		{pvpchess.Game{WhiteID: "w", BlackID: "b", Status: pvpchess.StatusActive}, ResultPending},
+	}
+	for i, c := range cases {
+		if got := gameResult(&c.g); got != c.want {
+			t.Fatalf("case %d: got %q want %q", i, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package tournament_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/tournament"
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/tournament/tournamenttest"
+)
+
+func newTestManager(t *testing.T) (*tournament.Manager, tournament.Store, *pvpchess.Manager) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis: %v", err)
+	}
+	t.Cleanup(func() { mr.Close() })
+	pvp, err := pvpchess.NewManager(fmt.Sprintf("redis://%s/0", mr.Addr()))
+	if err != nil {
+		t.Fatalf("pvpchess.NewManager: %v", err)
+	}
+	store := tournamenttest.NewMemoryStore()
+	return tournament.NewManager(store, pvp), store, pvp
+}
+
+// resignAll makes black resign every game in the event and feeds results back like the OnResult hook.
+func resignAll(t *testing.T, m *tournament.Manager, pvp *pvpchess.Manager, ev *tournament.Event) []*tournament.Event {
+	t.Helper()
+	ctx := context.Background()
+	var out []*tournament.Event
+	for _, g := range ev.Games {
+		fg, _, err := pvp.ResignByRoom(ctx, g.BlackID, g.OriginRoom)
+		if err != nil {
+			t.Fatalf("resign %s: %v", g.ID, err)
+		}
+		next, err := m.IngestResult(ctx, fg)
+		if err != nil {
+			t.Fatalf("ingest: %v", err)
+		}
+		if next != nil {
+			out = append(out, next)
+		}
+	}
+	return out
+}
+
+func TestRoundRobinTournamentRunsToCompletion(t *testing.T) {
+	m, store, pvp := newTestManager(t)
+	ctx := context.Background()
+
+	tour, err := m.Create(ctx, "room", "u1", "U1", "봄 리그", tournament.FormatRoundRobin, 0)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := m.Create(ctx, "room", "u2", "U2", "", tournament.FormatSwiss, 0); err != tournament.ErrTournamentExists {
+		t.Fatalf("expected ErrTournamentExists, got %v", err)
+	}
+	for _, u := range []string{"u1", "u2", "u3"} {
+		if _, _, err := m.Join(ctx, "room", u, strings.ToUpper(u)); err != nil {
+			t.Fatalf("Join %s: %v", u, err)
+		}
+	}
+	if _, _, err := m.Join(ctx, "room", "u2", "U2"); err != tournament.ErrAlreadyRegistered {
+		t.Fatalf("expected ErrAlreadyRegistered, got %v", err)
+	}
+	if _, err := m.Start(ctx, "room", "u2"); err != tournament.ErrNotOrganizer {
+		t.Fatalf("expected ErrNotOrganizer, got %v", err)
+	}
+
+	ev, err := m.Start(ctx, "room", "u1")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if ev.Tournament.Rounds != 3 || ev.Round != 1 || len(ev.Games) != 1 || len(ev.Pairings) != 2 {
+		t.Fatalf("unexpected first round: rounds=%d games=%d pairings=%+v", ev.Tournament.Rounds, len(ev.Games), ev.Pairings)
+	}
+	if _, _, err := m.Join(ctx, "room", "u4", "U4"); err != tournament.ErrRegistrationClosed {
+		t.Fatalf("expected ErrRegistrationClosed, got %v", err)
+	}
+
+	var last *tournament.Event
+	for round := 1; round <= 3; round++ {
+		evs := resignAll(t, m, pvp, ev)
+		if len(evs) != 1 {
+			t.Fatalf("round %d: expected one follow-up event, got %d", round, len(evs))
+		}
+		ev, last = evs[0], evs[0]
+		if round < 3 && (ev.Kind != tournament.EventRoundStarted || ev.Round != round+1) {
+			t.Fatalf("round %d: expected next round to start, got %+v", round, ev)
+		}
+	}
+	if last.Kind != tournament.EventFinished || last.Tournament.Status != tournament.StatusFinished {
+		t.Fatalf("expected tournament to finish, got %+v", last)
+	}
+	total := 0.0
+	for _, s := range last.Standings {
+		total += s.Points
+		if s.Games != 2 {
+			t.Fatalf("%s played %d games, want 2", s.UserID, s.Games)
+		}
+	}
+	if total != 3 {
+		t.Fatalf("expected 3 points distributed, got %v", total)
+	}
+	if _, _, st, err := m.Standings(ctx, "room"); err != nil || len(st) != 3 {
+		t.Fatalf("Standings after finish: %v %+v", err, st)
+	}
+	if cur, _ := store.GetTournament(ctx, tour.ID); cur.Status != tournament.StatusFinished {
+		t.Fatalf("stored status %s", cur.Status)
+	}
+}
+
+func TestTournamentDefersGameWhilePlayerBusy(t *testing.T) {
+	m, _, pvp := newTestManager(t)
+	ctx := context.Background()
+
+	if _, err := m.Create(ctx, "room", "u1", "U1", "", tournament.FormatSwiss, 1); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for _, u := range []string{"u1", "u2"} {
+		if _, _, err := m.Join(ctx, "room", u, u); err != nil {
+			t.Fatalf("Join: %v", err)
+		}
+	}
+	// u2가 같은 방에서 다른 대국 중 → 토너먼트 판 보류
+	side, err := pvp.CreateGameFromChallenge(ctx, "room", "room", "u2", "u2", "x", "x", "white", "")
+	if err != nil {
+		t.Fatalf("side game: %v", err)
+	}
+	ev, err := m.Start(ctx, "room", "u1")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if len(ev.Games) != 0 || ev.Pairings[0].GameID != "" {
+		t.Fatalf("expected deferred board, got %+v", ev.Pairings)
+	}
+
+	fg, _, err := pvp.ResignByRoom(ctx, "x", "room")
+	if err != nil || fg.ID != side.ID {
+		t.Fatalf("resign side game: %v", err)
+	}
+	if next, err := m.IngestResult(ctx, fg); err != nil || next != nil {
+		t.Fatalf("non-tournament game should be ignored: %+v %v", next, err)
+	}
+	again, err := m.Start(ctx, "room", "u1")
+	if err != nil || len(again.Games) != 1 {
+		t.Fatalf("expected organizer retry to start the board: %+v %v", again, err)
+	}
+	fin := resignAll(t, m, pvp, again)
+	if len(fin) != 1 || fin[0].Kind != tournament.EventFinished {
+		t.Fatalf("expected single-round swiss to finish, got %+v", fin)
+	}
+}
@@ -0,0 +1,164 @@
+package tournament
+
+import (
+	"math"
+	"sort"
+)
+
+// defaultSwissRounds picks ceil(log2(n))+1 rounds, capped at n-1 so rematches stay avoidable.
+func defaultSwissRounds(n int) int {
+	if n < 2 {
+		return 0
+	}
+	r := int(math.Ceil(math.Log2(float64(n)))) + 1
+	if r > n-1 {
+		r = n - 1
+	}
+	return r
+}
+
+// roundRobinRounds returns the number of rounds for a full round robin (one bye per round when odd).
+func roundRobinRounds(n int) int {
+	if n < 2 {
+		return 0
+	}
+	if n%2 == 1 {
+		return n
+	}
+	return n - 1
+}
+
+// roundRobinPairings returns round (1-based) of a circle-method schedule over seeded player ids.
+// 첫 번째 시드를 고정하고 나머지를 회전하며, 홀수면 빈 자리("")와 짝지어진 선수가 부전승입니다.
+func roundRobinPairings(seeded []string, round int) []Pairing {
+	ids := append([]string(nil), seeded...)
+	if len(ids)%2 == 1 {
+		ids = append(ids, "")
+	}
+	n := len(ids)
+	if n < 2 || round < 1 || round > n-1 {
+		return nil
+	}
+	rot := make([]string, n-1)
+	for i := range rot {
+		rot[i] = ids[1+(i+round-1)%(n-1)]
+	}
+	circle := append([]string{ids[0]}, rot...)
+
+	var out []Pairing
+	var bye *Pairing
+	board := 1
+	for i := 0; i < n/2; i++ {
+		a, b := circle[i], circle[n-1-i]
+		// 색 배정: 고정 시드는 라운드마다 교대, 나머지는 윗줄이 백(회전하며 백·흑 횟수가 고르게 분배)
+		if i == 0 && round%2 == 0 {
+			a, b = b, a
+		}
+		switch {
+		case a == "":
+			bye = &Pairing{Round: round, WhiteID: b, Result: ResultBye}
+		case b == "":
+			bye = &Pairing{Round: round, WhiteID: a, Result: ResultBye}
+		default:
+			out = append(out, Pairing{Round: round, Board: board, WhiteID: a, BlackID: b})
+			board++
+		}
+	}
+	if bye != nil {
+		bye.Board = board
+		out = append(out, *bye)
+	}
+	return out
+}
+
+// swissPairings pairs the next Swiss round from the current standings and past pairings.
+// 점수·시드 순으로 정렬해 재대결을 피하는 짝을 백트래킹으로 찾고(불가능하면 재대결 허용),
+// 홀수면 아직 부전승이 없는 최하위가 부전승을 받습니다.
+func swissPairings(standings []Standing, history []Pairing, round int) []Pairing {
+	order := make([]string, 0, len(standings))
+	ranked := append([]Standing(nil), standings...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Points != ranked[j].Points {
+			return ranked[i].Points > ranked[j].Points
+		}
+		return ranked[i].Rating > ranked[j].Rating
+	})
+	for _, s := range ranked {
+		order = append(order, s.UserID)
+	}
+
+	played := map[[2]string]bool{}
+	hadBye := map[string]bool{}
+	colorBalance := map[string]int{}
+	lastColor := map[string]int{}
+	for _, p := range history {
+		if p.IsBye() {
+			hadBye[p.WhiteID] = true
+			continue
+		}
+		played[[2]string{p.WhiteID, p.BlackID}] = true
+		played[[2]string{p.BlackID, p.WhiteID}] = true
+		colorBalance[p.WhiteID]++
+		colorBalance[p.BlackID]--
+		lastColor[p.WhiteID] = 1
+		lastColor[p.BlackID] = -1
+	}
+
+	var byeID string
+	if len(order)%2 == 1 {
+		idx := len(order) - 1
+		for i := len(order) - 1; i >= 0; i-- {
+			if !hadBye[order[i]] {
+				idx = i
+				break
+			}
+		}
+		byeID = order[idx]
+		order = append(order[:idx:idx], order[idx+1:]...)
+	}
+
+	pairs, ok := matchAvoidingRematches(order, played)
+	if !ok {
+		pairs = nil
+		for i := 0; i+1 < len(order); i += 2 {
+			pairs = append(pairs, [2]string{order[i], order[i+1]})
+		}
+	}
+
+	out := make([]Pairing, 0, len(pairs)+1)
+	for i, pr := range pairs {
+		white, black := pr[0], pr[1]
+		switch {
+		case colorBalance[white] > colorBalance[black]:
+			white, black = black, white
+		case colorBalance[white] == colorBalance[black] && lastColor[white] == 1 && lastColor[black] != 1:
+			white, black = black, white
+		}
+		out = append(out, Pairing{Round: round, Board: i + 1, WhiteID: white, BlackID: black})
+	}
+	if byeID != "" {
+		out = append(out, Pairing{Round: round, Board: len(pairs) + 1, WhiteID: byeID, Result: ResultBye})
+	}
+	return out
+}
+
+// matchAvoidingRematches pairs players in order, preferring the nearest-ranked unplayed opponent.
+func matchAvoidingRematches(order []string, played map[[2]string]bool) ([][2]string, bool) {
+	if len(order) == 0 {
+		return nil, true
+	}
+	first := order[0]
+	for j := 1; j < len(order); j++ {
+		opp := order[j]
+		if played[[2]string{first, opp}] {
+			continue
+		}
+		rest := make([]string, 0, len(order)-2)
+		rest = append(rest, order[1:j]...)
+		rest = append(rest, order[j+1:]...)
+		if sub, ok := matchAvoidingRematches(rest, played); ok {
+			return append([][2]string{{first, opp}}, sub...), true
+		}
+	}
+	return nil, false
+}
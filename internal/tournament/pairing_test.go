@@ -0,0 +1,109 @@
+package tournament
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRoundRobinEveryoneMeetsOnce(t *testing.T) {
+	for _, n := range []int{2, 3, 4, 5, 6} {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("p%d", i)
+		}
+		met := map[[2]string]int{}
+		byes := map[string]int{}
+		whites := map[string]int{}
+		for r := 1; r <= roundRobinRounds(n); r++ {
+			seen := map[string]bool{}
+			for _, p := range roundRobinPairings(ids, r) {
+				if p.IsBye() {
+					byes[p.WhiteID]++
+					seen[p.WhiteID] = true
+					continue
+				}
+				if seen[p.WhiteID] || seen[p.BlackID] {
+					t.Fatalf("n=%d round %d: player paired twice: %+v", n, r, p)
+				}
+				seen[p.WhiteID], seen[p.BlackID] = true, true
+				a, b := p.WhiteID, p.BlackID
+				if a > b {
+					a, b = b, a
+				}
+				met[[2]string{a, b}]++
+				whites[p.WhiteID]++
+			}
+			if len(seen) != n {
+				t.Fatalf("n=%d round %d: %d players scheduled", n, r, len(seen))
+			}
+		}
+		if want := n * (n - 1) / 2; len(met) != want {
+			t.Fatalf("n=%d: %d distinct pairs, want %d", n, len(met), want)
+		}
+		for pair, c := range met {
+			if c != 1 {
+				t.Fatalf("n=%d: pair %v met %d times", n, pair, c)
+			}
+		}
+		if n%2 == 1 {
+			for _, id := range ids {
+				if byes[id] != 1 {
+					t.Fatalf("n=%d: %s had %d byes", n, id, byes[id])
+				}
+			}
+		}
+		for _, id := range ids {
+			games := n - 1
+			if w := whites[id]; w < games/2-1 || w > (games+1)/2+1 {
+				t.Fatalf("n=%d: %s has unbalanced colors (%d whites of %d)", n, id, w, games)
+			}
+		}
+	}
+}
+
+func TestSwissAvoidsRematchesAndRotatesBye(t *testing.T) {
+	standings := []Standing{
+		{UserID: "a", Points: 1, Rating: 1500},
+		{UserID: "b", Points: 1, Rating: 1400},
+		{UserID: "c", Points: 0, Rating: 1300},
+		{UserID: "d", Points: 0, Rating: 1200},
+		{UserID: "e", Points: 1, Rating: 1100},
+	}
+	history := []Pairing{
+		{Round: 1, Board: 1, WhiteID: "a", BlackID: "c", Result: ResultWhiteWin},
+		{Round: 1, Board: 2, WhiteID: "b", BlackID: "d", Result: ResultWhiteWin},
+		{Round: 1, Board: 3, WhiteID: "e", Result: ResultBye},
+	}
+	ps := swissPairings(standings, history, 2)
+	if len(ps) != 3 {
+		t.Fatalf("expected 2 boards and a bye, got %+v", ps)
+	}
+	bye := ps[len(ps)-1]
+	if !bye.IsBye() || bye.WhiteID != "d" {
+		t.Fatalf("expected lowest player without a bye (d) to get it, got %+v", bye)
+	}
+	if ps[0].WhiteID != "b" && ps[0].BlackID != "b" || ps[0].WhiteID != "a" && ps[0].BlackID != "a" {
+		t.Fatalf("expected leaders a and b paired on board 1, got %+v", ps[0])
+	}
+	if (ps[1].WhiteID != "c" && ps[1].WhiteID != "e") || (ps[1].BlackID != "c" && ps[1].BlackID != "e") {
+		t.Fatalf("expected c vs e on board 2, got %+v", ps[1])
+	}
+}
+
+func TestSwissFallsBackToRematchWhenUnavoidable(t *testing.T) {
+	standings := []Standing{{UserID: "a", Points: 1}, {UserID: "b", Points: 0}}
+	history := []Pairing{{Round: 1, Board: 1, WhiteID: "a", BlackID: "b", Result: ResultWhiteWin}}
+	ps := swissPairings(standings, history, 2)
+	if len(ps) != 1 || ps[0].WhiteID != "b" || ps[0].BlackID != "a" {
+		t.Fatalf("expected rematch with colors swapped, got %+v", ps)
+	}
+}
+
+func TestDefaultSwissRounds(t *testing.T) {
+	cases := map[int]int{1: 0, 2: 1, 3: 2, 4: 3, 8: 4, 16: 5}
+	for n, want := range cases {
+		if got := defaultSwissRounds(n); got != want {
+			t.Fatalf("defaultSwissRounds(%d)=%d want %d", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package tournament
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// Store persists tournaments, registrations and pairings.
+// Repository(Postgres)가 기본 구현이며, 상태 전이는 TransitionTournament의 조건부 갱신으로 직렬화합니다.
+type Store interface {
+	CreateTournament(ctx context.Context, t *Tournament) error
+	// LatestTournament returns the most recent tournament created in room (nil when none).
+	LatestTournament(ctx context.Context, room string) (*Tournament, error)
+	GetTournament(ctx context.Context, id int64) (*Tournament, error)
+	// TransitionTournament saves t only if the stored status and round still equal from/fromRound.
+	TransitionTournament(ctx context.Context, t *Tournament, from Status, fromRound int) (bool, error)
+
+	AddPlayer(ctx context.Context, p Player) (bool, error)
+	RemovePlayer(ctx context.Context, tournamentID int64, userID string) (bool, error)
+	Players(ctx context.Context, tournamentID int64) ([]Player, error)
+
+	SavePairings(ctx context.Context, ps []Pairing) error
+	Pairings(ctx context.Context, tournamentID int64) ([]Pairing, error)
+	SetPairingGame(ctx context.Context, tournamentID int64, round, board int, gameID string) error
+	// RecordResult sets the result of the pairing bound to gameID once; ok=false when unknown or already decided.
+	RecordResult(ctx context.Context, gameID string, r Result) (*Pairing, bool, error)
+}
+
+// Repository is the Postgres-backed Store (pvp_tournaments, pvp_tournament_players, pvp_tournament_pairings).
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(databaseURL string) (*Repository, error) {
+	if strings.TrimSpace(databaseURL) == "" {
+		return nil, fmt.Errorf("DATABASE_URL is required")
+	}
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(4)
+	db.SetMaxIdleConns(2)
+	db.SetConnMaxLifetime(30 * time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+	return &Repository{db: db}, nil
+}
+
+func (r *Repository) Close() error {
+	if r == nil || r.db == nil {
+		return nil
+	}
+	return r.db.Close()
+}
+
+const tournamentColumns = `id, room, name, format, status, rounds, current_round, creator_id, creator_name, created_at, started_at, finished_at`
+
+func (r *Repository) CreateTournament(ctx context.Context, t *Tournament) error {
+	return r.db.QueryRowContext(ctx, `INSERT INTO pvp_tournaments (
+        room, name, format, status, rounds, current_round, creator_id, creator_name, created_at
+      ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id`,
+		t.Room, t.Name, string(t.Format), string(t.Status), t.Rounds, t.CurrentRound, t.CreatorID, t.CreatorName, t.CreatedAt,
+	).Scan(&t.ID)
+}
+
+func (r *Repository) LatestTournament(ctx context.Context, room string) (*Tournament, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+tournamentColumns+` FROM pvp_tournaments WHERE room = $1 ORDER BY id DESC LIMIT 1`, strings.TrimSpace(room))
+	return scanTournament(row)
+}
+
+func (r *Repository) GetTournament(ctx context.Context, id int64) (*Tournament, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+tournamentColumns+` FROM pvp_tournaments WHERE id = $1`, id)
+	return scanTournament(row)
+}
+
+func (r *Repository) TransitionTournament(ctx context.Context, t *Tournament, from Status, fromRound int) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE pvp_tournaments SET
+        status=$2, rounds=$3, current_round=$4, started_at=$5, finished_at=$6
+      WHERE id=$1 AND status=$7 AND current_round=$8`,
+		t.ID, string(t.Status), t.Rounds, t.CurrentRound, nullTime(t.StartedAt), nullTime(t.FinishedAt), string(from), fromRound,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
+func (r *Repository) AddPlayer(ctx context.Context, p Player) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO pvp_tournament_players (
+        tournament_id, user_id, name, rating, joined_at
+      ) VALUES ($1,$2,$3,$4,$5) ON CONFLICT (tournament_id, user_id) DO NOTHING`,
+		p.TournamentID, p.UserID, p.Name, p.Rating, p.JoinedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
+func (r *Repository) RemovePlayer(ctx context.Context, tournamentID int64, userID string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM pvp_tournament_players WHERE tournament_id = $1 AND user_id = $2`, tournamentID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
+func (r *Repository) Players(ctx context.Context, tournamentID int64) ([]Player, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT tournament_id, user_id, name, rating, joined_at
+      FROM pvp_tournament_players WHERE tournament_id = $1 ORDER BY joined_at, user_id`, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Player
+	for rows.Next() {
+		var p Player
+		if err := rows.Scan(&p.TournamentID, &p.UserID, &p.Name, &p.Rating, &p.JoinedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *Repository) SavePairings(ctx context.Context, ps []Pairing) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, p := range ps {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO pvp_tournament_pairings (
+            tournament_id, round, board, white_id, black_id, game_id, result
+          ) VALUES ($1,$2,$3,$4,$5,$6,$7)`,
+			p.TournamentID, p.Round, p.Board, p.WhiteID, p.BlackID, p.GameID, string(p.Result),
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) Pairings(ctx context.Context, tournamentID int64) ([]Pairing, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT tournament_id, round, board, white_id, black_id, game_id, result
+      FROM pvp_tournament_pairings WHERE tournament_id = $1 ORDER BY round, board`, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Pairing
+	for rows.Next() {
+		var p Pairing
+		var result string
+		if err := rows.Scan(&p.TournamentID, &p.Round, &p.Board, &p.WhiteID, &p.BlackID, &p.GameID, &result); err != nil {
+			return nil, err
+		}
+		p.Result = Result(result)
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *Repository) SetPairingGame(ctx context.Context, tournamentID int64, round, board int, gameID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE pvp_tournament_pairings SET game_id = $4
+      WHERE tournament_id = $1 AND round = $2 AND board = $3`, tournamentID, round, board, gameID)
+	return err
+}
+
+func (r *Repository) RecordResult(ctx context.Context, gameID string, res Result) (*Pairing, bool, error) {
+	var p Pairing
+	var result string
+	err := r.db.QueryRowContext(ctx, `UPDATE pvp_tournament_pairings SET result = $2
+      WHERE game_id = $1 AND result = ''
+      RETURNING tournament_id, round, board, white_id, black_id, game_id, result`,
+		strings.TrimSpace(gameID), string(res),
+	).Scan(&p.TournamentID, &p.Round, &p.Board, &p.WhiteID, &p.BlackID, &p.GameID, &result)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	p.Result = Result(result)
+	return &p, true, nil
+}
+
+func scanTournament(row *sql.Row) (*Tournament, error) {
+	var t Tournament
+	var format, status string
+	var started, finished sql.NullTime
+	err := row.Scan(&t.ID, &t.Room, &t.Name, &format, &status, &t.Rounds, &t.CurrentRound, &t.CreatorID, &t.CreatorName, &t.CreatedAt, &started, &finished)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.Format, t.Status = Format(format), Status(status)
+	if started.Valid {
+		t.StartedAt = started.Time
+	}
+	if finished.Valid {
+		t.FinishedAt = finished.Time
+	}
+	return &t, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
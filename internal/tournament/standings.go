@@ -0,0 +1,97 @@
+package tournament
+
+import (
+	"sort"
+	"strings"
+)
+
+// ComputeStandings scores every player over the recorded pairings and ranks them.
+// 정렬 기준: 승점 → Buchholz → Sonneborn-Berger → 승수 → 시드 레이팅.
+// 부전승 승점은 byePoints(스위스 1점, 풀리그 0점)이며 타이브레이크에는 포함하지 않습니다.
+func ComputeStandings(players []Player, pairings []Pairing, byePoints float64) []Standing {
+	rows := make(map[string]*Standing, len(players))
+	for _, p := range players {
+		rows[p.UserID] = &Standing{UserID: p.UserID, Name: p.Name, Rating: p.Rating}
+	}
+	type game struct {
+		opp   string
+		score float64
+	}
+	games := map[string][]game{}
+	for _, p := range pairings {
+		w := rows[p.WhiteID]
+		if w == nil {
+			continue
+		}
+		if p.IsBye() {
+			if p.Result == ResultBye {
+				w.Points += byePoints
+			}
+			continue
+		}
+		b := rows[p.BlackID]
+		if b == nil {
+			continue
+		}
+		ws, ok := whiteScore(p.Result)
+		if !ok {
+			continue
+		}
+		w.Points += ws
+		b.Points += 1 - ws
+		w.Games++
+		b.Games++
+		if ws == 1 {
+			w.Wins++
+		} else if ws == 0 {
+			b.Wins++
+		}
+		games[p.WhiteID] = append(games[p.WhiteID], game{opp: p.BlackID, score: ws})
+		games[p.BlackID] = append(games[p.BlackID], game{opp: p.WhiteID, score: 1 - ws})
+	}
+	for id, row := range rows {
+		for _, g := range games[id] {
+			opp := rows[g.opp].Points
+			row.Buchholz += opp
+			row.SonnebornBerger += g.score * opp
+		}
+	}
+
+	out := make([]Standing, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch {
+		case a.Points != b.Points:
+			return a.Points > b.Points
+		case a.Buchholz != b.Buchholz:
+			return a.Buchholz > b.Buchholz
+		case a.SonnebornBerger != b.SonnebornBerger:
+			return a.SonnebornBerger > b.SonnebornBerger
+		case a.Wins != b.Wins:
+			return a.Wins > b.Wins
+		case a.Rating != b.Rating:
+			return a.Rating > b.Rating
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	for i := range out {
+		out[i].Rank = i + 1
+	}
+	return out
+}
+
+// whiteScore converts a decided result into white's score.
+func whiteScore(r Result) (float64, bool) {
+	switch r {
+	case ResultWhiteWin:
+		return 1, true
+	case ResultBlackWin:
+		return 0, true
+	case ResultDraw:
+		return 0.5, true
+	}
+	return 0, false
+}
@@ -0,0 +1,48 @@
+package tournament
+
+import "testing"
+
+func TestComputeStandingsTiebreaks(t *testing.T) {
+	players := []Player{
+		{UserID: "a", Name: "A", Rating: 1300},
+		{UserID: "b", Name: "B", Rating: 1200},
+		{UserID: "c", Name: "C", Rating: 1100},
+		{UserID: "d", Name: "D", Rating: 1000},
+	}
+	pairings := []Pairing{
+		{Round: 1, WhiteID: "a", BlackID: "b", Result: ResultWhiteWin},
+		{Round: 1, WhiteID: "c", BlackID: "d", Result: ResultWhiteWin},
+		{Round: 2, WhiteID: "a", BlackID: "c", Result: ResultBlackWin},
+		{Round: 2, WhiteID: "b", BlackID: "d", Result: ResultDraw},
+		{Round: 3, WhiteID: "a", BlackID: "d", Result: ResultPending},
+	}
+	s := ComputeStandings(players, pairings, 1)
+	// 승점: c 2, a 1, b 0.5, d 0.5
+	if s[0].UserID != "c" || s[0].Points != 2 || s[0].Rank != 1 {
+		t.Fatalf("unexpected leader: %+v", s[0])
+	}
+	if s[1].UserID != "a" || s[1].Points != 1 || s[1].Buchholz != 2.5 || s[1].SonnebornBerger != 0.5 {
+		t.Fatalf("unexpected second: %+v", s[1])
+	}
+	// b와 d는 0.5점 동률: Buchholz b=1(a)+0.5(d)=1.5, d=2(c)+0.5(b)=2.5 → d 우선
+	if s[2].UserID != "d" || s[3].UserID != "b" {
+		t.Fatalf("expected Buchholz to rank d above b, got %s then %s", s[2].UserID, s[3].UserID)
+	}
+	if s[2].SonnebornBerger != 0.25 || s[2].Games != 2 {
+		t.Fatalf("unexpected d row: %+v", s[2])
+	}
+}
+
+func TestComputeStandingsByePoints(t *testing.T) {
+	players := []Player{{UserID: "a", Name: "A"}, {UserID: "b", Name: "B"}, {UserID: "c", Name: "C"}}
+	pairings := []Pairing{
+		{Round: 1, WhiteID: "a", BlackID: "b", Result: ResultDraw},
+		{Round: 1, WhiteID: "c", Result: ResultBye},
+	}
+	if s := ComputeStandings(players, pairings, 1); s[0].UserID != "c" || s[0].Points != 1 || s[0].Buchholz != 0 {
+		t.Fatalf("swiss bye should score 1 without tiebreak: %+v", s[0])
+	}
+	if s := ComputeStandings(players, pairings, 0); s[2].UserID != "c" || s[2].Points != 0 {
+		t.Fatalf("round-robin bye should score 0: %+v", s)
+	}
+}
@@ -0,0 +1,151 @@
+// Package tournamenttest provides an in-memory tournament.Store for tests.
+package tournamenttest
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/tournament"
+)
+
+// memStore is an in-memory tournament.Store.
+type memStore struct {
+	mu          sync.Mutex
+	nextID      int64
+	tournaments map[int64]*tournament.Tournament
+	players     map[int64][]tournament.Player
+	pairings    map[int64][]tournament.Pairing
+}
+
+// NewMemoryStore returns an empty in-memory store (Postgres 없이 토너먼트 흐름을 검증할 때 사용).
+func NewMemoryStore() tournament.Store {
+	return &memStore{tournaments: map[int64]*tournament.Tournament{}, players: map[int64][]tournament.Player{}, pairings: map[int64][]tournament.Pairing{}}
+}
+
+func (s *memStore) CreateTournament(_ context.Context, t *tournament.Tournament) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	t.ID = s.nextID
+	cp := *t
+	s.tournaments[t.ID] = &cp
+	return nil
+}
+
+func (s *memStore) LatestTournament(_ context.Context, room string) (*tournament.Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var best *tournament.Tournament
+	for _, t := range s.tournaments {
+		if t.Room == room && (best == nil || t.ID > best.ID) {
+			best = t
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	cp := *best
+	return &cp, nil
+}
+
+func (s *memStore) GetTournament(_ context.Context, id int64) (*tournament.Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t := s.tournaments[id]; t != nil {
+		cp := *t
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (s *memStore) TransitionTournament(_ context.Context, t *tournament.Tournament, from tournament.Status, fromRound int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.tournaments[t.ID]
+	if cur == nil || cur.Status != from || cur.CurrentRound != fromRound {
+		return false, nil
+	}
+	cp := *t
+	s.tournaments[t.ID] = &cp
+	return true, nil
+}
+
+func (s *memStore) AddPlayer(_ context.Context, p tournament.Player) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, x := range s.players[p.TournamentID] {
+		if x.UserID == p.UserID {
+			return false, nil
+		}
+	}
+	s.players[p.TournamentID] = append(s.players[p.TournamentID], p)
+	return true, nil
+}
+
+func (s *memStore) RemovePlayer(_ context.Context, id int64, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ps := s.players[id]
+	for i, x := range ps {
+		if x.UserID == userID {
+			s.players[id] = append(ps[:i:i], ps[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memStore) Players(_ context.Context, id int64) ([]tournament.Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]tournament.Player(nil), s.players[id]...), nil
+}
+
+func (s *memStore) SavePairings(_ context.Context, ps []tournament.Pairing) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range ps {
+		s.pairings[p.TournamentID] = append(s.pairings[p.TournamentID], p)
+	}
+	return nil
+}
+
+func (s *memStore) Pairings(_ context.Context, id int64) ([]tournament.Pairing, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := append([]tournament.Pairing(nil), s.pairings[id]...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Round != out[j].Round {
+			return out[i].Round < out[j].Round
+		}
+		return out[i].Board < out[j].Board
+	})
+	return out, nil
+}
+
+func (s *memStore) SetPairingGame(_ context.Context, id int64, round, board int, gameID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, p := range s.pairings[id] {
+		if p.Round == round && p.Board == board {
+			s.pairings[id][i].GameID = gameID
+		}
+	}
+	return nil
+}
+
+func (s *memStore) RecordResult(_ context.Context, gameID string, r tournament.Result) (*tournament.Pairing, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, ps := range s.pairings {
+		for i, p := range ps {
+			if p.GameID == gameID && p.GameID != "" && p.Result == tournament.ResultPending {
+				s.pairings[id][i].Result = r
+				cp := s.pairings[id][i]
+				return &cp, true, nil
+			}
+		}
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,140 @@
+package tournament
+
+import (
+	"errors"
+	"time"
+
+	"github.com/park285/Cheese-KakaoTalk-bot/internal/pvpchess"
+)
+
+// Format is the pairing system of a tournament.
+type Format string
+
+const (
+	FormatRoundRobin Format = "roundrobin"
+	FormatSwiss      Format = "swiss"
+)
+
+// ParseFormat maps a user token (리그/풀리그/스위스, roundrobin/swiss) to a Format.
+func ParseFormat(token string) (Format, bool) {
+	switch token {
+	case "리그", "풀리그", "라운드로빈", "roundrobin", "rr":
+		return FormatRoundRobin, true
+	case "스위스", "swiss":
+		return FormatSwiss, true
+	}
+	return "", false
+}
+
+// Status is the tournament lifecycle state.
+type Status string
+
+const (
+	StatusRegistering Status = "REGISTERING"
+	StatusRunning     Status = "RUNNING"
+	StatusFinished    Status = "FINISHED"
+	StatusCancelled   Status = "CANCELLED"
+)
+
+// Result is a pairing outcome from white's point of view.
+type Result string
+
+const (
+	ResultPending  Result = ""
+	ResultWhiteWin Result = "1-0"
+	ResultBlackWin Result = "0-1"
+	ResultDraw     Result = "1/2-1/2"
+	// ResultBye: 상대 없는 부전승 (WhiteID만 채워짐)
+	ResultBye Result = "bye"
+)
+
+// Tournament is one event held in a KakaoTalk room (pvp_tournaments).
+type Tournament struct {
+	ID           int64
+	Room         string
+	Name         string
+	Format       Format
+	Status       Status
+	Rounds       int
+	CurrentRound int
+	CreatorID    string
+	CreatorName  string
+	CreatedAt    time.Time
+	StartedAt    time.Time
+	FinishedAt   time.Time
+}
+
+// Active reports whether the tournament still accepts commands (registration or play).
+func (t *Tournament) Active() bool {
+	return t != nil && (t.Status == StatusRegistering || t.Status == StatusRunning)
+}
+
+// Player is a registered participant; Rating is the PvP rating at registration (seeding).
+type Player struct {
+	TournamentID int64
+	UserID       string
+	Name         string
+	Rating       int
+	JoinedAt     time.Time
+}
+
+// Pairing is one board of a round. BlackID가 비어 있으면 부전승(bye)입니다.
+type Pairing struct {
+	TournamentID int64
+	Round        int
+	Board        int
+	WhiteID      string
+	BlackID      string
+	GameID       string
+	Result       Result
+}
+
+// IsBye reports whether the pairing has no opponent.
+func (p Pairing) IsBye() bool { return p.BlackID == "" }
+
+// Standing is one row of the standings table.
+type Standing struct {
+	Rank     int
+	UserID   string
+	Name     string
+	Rating   int
+	Points   float64
+	Buchholz float64
+	// SonnebornBerger: 이긴 상대 점수 합 + 비긴 상대 점수의 절반
+	SonnebornBerger float64
+	Wins            int
+	Games           int
+}
+
+// EventKind describes what IngestResult or Start produced for announcement.
+type EventKind string
+
+const (
+	EventRoundStarted EventKind = "round_started"
+	EventFinished     EventKind = "finished"
+)
+
+// Event is returned when a round was paired or the tournament ended.
+type Event struct {
+	Kind       EventKind
+	Tournament *Tournament
+	Round      int
+	Pairings   []Pairing
+	// Players maps user id → display name for rendering pairings.
+	Players   map[string]string
+	Standings []Standing
+	// Games are the pvpchess games created by this event (보드 전송 대상).
+	Games []*pvpchess.Game
+}
+
+// Errors surfaced to the command layer.
+var (
+	ErrTournamentExists   = errors.New("room already has an active tournament")
+	ErrNoTournament       = errors.New("no active tournament in this room")
+	ErrRegistrationClosed = errors.New("tournament registration is closed")
+	ErrAlreadyRegistered  = errors.New("already registered")
+	ErrNotRegistered      = errors.New("not registered")
+	ErrNotOrganizer       = errors.New("only the organizer can do this")
+	ErrTooFewPlayers      = errors.New("at least two players are required")
+	ErrInvalidRounds      = errors.New("invalid number of rounds")
+)
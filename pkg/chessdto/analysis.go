@@ -0,0 +1,25 @@
+package chessdto
+
+type MoveEvaluation struct {
+	Ply      int
+	MoveUCI  string
+	BestMove string
+	EvalCP   int
+	CPLoss   int
+	Class    string
+}
+
+type GameAnalysis struct {
+	GameID      int64
+	Depth       int
+	Accuracy    float64
+	PlayerColor string
+	Counts      map[string]int
+	Moves       []MoveEvaluation
+	WorstPly    int
+	WorstSAN    string
+	BestSAN     string
+	WorstCPLoss int
+	WorstClass  string
+	BoardImage  []byte
+}
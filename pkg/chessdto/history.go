@@ -19,4 +19,7 @@ type ChessGame struct {
 	Duration      time.Duration
 	Blunders      int
 	EngineLatency time.Duration
+	// StartFEN: 지정 포지션(FEN/PGN 가져오기)에서 시작한 대국이면 시작 FEN
+	StartFEN string
+	Rated    bool
 }
@@ -26,4 +26,8 @@ type SessionState struct {
 	Outcome     string
 	OutcomeMeta string
 	GameID      int64
+	// PlayerColor: 싱글 플레이어의 진영(white|black), PvP는 보드를 보는 쪽. 비어 있으면 백.
+	PlayerColor string
+	// StartFEN: 지정 포지션에서 시작한 세션이면 시작 FEN (레이팅 미반영)
+	StartFEN string
 }